@@ -2,10 +2,12 @@ module github.com/Crank-Git/FSNRedux
 
 go 1.25.7
 
-require github.com/gen2brain/raylib-go/raylib v0.55.1
+require (
+	github.com/gen2brain/raylib-go/raylib v0.55.1
+	golang.org/x/sys v0.27.0
+)
 
 require (
 	github.com/ebitengine/purego v0.8.1 // indirect
 	golang.org/x/exp v0.0.0-20241108190413-2d47ceb2692f // indirect
-	golang.org/x/sys v0.27.0 // indirect
 )
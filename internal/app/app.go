@@ -10,25 +10,43 @@ import (
 	"runtime"
 	"sort"
 	"strings"
+	"time"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 	"github.com/Crank-Git/FSNRedux/internal/color"
+	"github.com/Crank-Git/FSNRedux/internal/config"
 	"github.com/Crank-Git/FSNRedux/internal/fs"
+	"github.com/Crank-Git/FSNRedux/internal/git"
 	"github.com/Crank-Git/FSNRedux/internal/input"
 	"github.com/Crank-Git/FSNRedux/internal/layout"
 	"github.com/Crank-Git/FSNRedux/internal/renderer"
 	"github.com/Crank-Git/FSNRedux/internal/scene"
+	"github.com/Crank-Git/FSNRedux/internal/shading"
 	"github.com/Crank-Git/FSNRedux/internal/ui"
 )
 
-// Config holds application configuration from CLI flags.
+// Config holds application configuration from CLI flags and config.json.
 type Config struct {
-	RootPath   string
-	Width      int
-	Height     int
-	MaxDepth   int
-	Theme      string
-	ShowHidden bool
+	RootPath      string
+	Width         int
+	Height        int
+	MaxDepth      int
+	Theme         string
+	ShowHidden    bool
+	ColorMode     color.Mode
+	AgeBuckets    []color.AgeBucket   // nil = color.DefaultAgeBuckets
+	Reference     config.Reference    // what ages are measured against
+	TimeField     fs.TimeField        // which timestamp drives the age
+	Shading       shading.Mode        // lighting pipeline (flat for low-end machines)
+	Layout        layout.Mode         // visualization algorithm
+	SectorHeight  layout.SectorHeight // what drives segment heights in the sunburst layout
+	FileHeights   bool                // file tiles grow taller with size
+	FileFootprint bool                // file tiles grow wider with size
+	Sort          fs.SortMode         // order of children in the sidebar, TreeV and Tab cycling
+	Geometry      layout.Geometry     // layout sizes and spacings (zero = layout.DefaultGeometry)
+	Stable        bool                // TreeV and MapV keep children where they were last seen
+	Order         layout.Order        // remembered placement for Stable, updated in place (nil = start empty)
+	Fisheye       bool                // enlarge the selected directory's subtree and squeeze the rest
 }
 
 // App is the main application that wires all subsystems together.
@@ -43,49 +61,135 @@ type App struct {
 	// State
 	tree          *fs.Tree
 	graph         *scene.Graph
+	layoutMode    layout.Mode
+	layoutCache   *layout.Incremental // reuses unchanged TreeV subtrees across expand/collapse
 	treeViewState *ui.TreeViewState
 	scanning      bool
 	scanResult    <-chan fs.ScanResult
+	scannedDepth  int // depth the tree is scanned to (0 = all); deeper directories load lazily
 	selectedPath  string
 	expandedPaths map[string]bool // tracks which dirs are expanded in 3D view
+	focusPath     string          // directory the layout is distorted around (see fisheyeFocus)
 
 	// Input bar (path entry / search)
 	inputBar      ui.InputBar
 	searchResults []string // paths matching current search
 	searchIndex   int      // current search result index
 
+	// Background pre-scan down to the depth setting (see startPrescan)
+	prescanResult <-chan fs.ScanResult
+	prescanCancel context.CancelFunc
+	prescanDepth  int
+	prescanOpen   bool // started when the tree opened, so it expands to the depth
+	openView      view // camera and selection when the tree opened
+
+	// Expanded directories beyond the scan, loaded in the background (see
+	// loadExpanded)
+	dirLoads []<-chan []dirLoad
+
+	// Whether birth times are recorded under birthRoot (see birthTimeSupported)
+	birthRoot      string
+	birthSupported bool
+
 	// Inspect panel
 	inspectOpen bool
 	inspectInfo *fs.InspectInfo
+	inspectRepo *git.Repo // non-nil when inspecting a repository root
 
 	// Settings menu
 	settings *ui.SettingsState
 
+	// Color legend
+	legend *ui.LegendState
+
+	// Size breakdown panel (aggregated for breakdownPath, see refreshBreakdown)
+	breakdown       *ui.BreakdownState
+	breakdownData   *fs.Breakdown
+	breakdownPath   string
+	breakdownResult <-chan *fs.Breakdown
+	breakdownCancel context.CancelFunc
+	breakdownCache  map[string]*fs.Breakdown // by directory path, for the current tree
+
+	// Reference-time choices offered in settings (now, scan, plus a configured date)
+	references []config.Reference
+
+	// Time travel: slider target plus an eased "virtual now" (unix seconds)
+	timeline     *ui.TimelineState
+	animator     *scene.Animator
+	timelineFrom time.Time
+	timelineTo   time.Time
+
+	// Git repositories found in the scanned tree (status loads in the background)
+	repos      []*git.Repo
+	gitResults []<-chan []*git.Repo // repositories opening in the background (see openRepos)
+
 	// File preview
 	preview ui.PreviewState
 }
 
 // New creates the application with the given config.
 func New(cfg Config) *App {
-	return &App{
+	if cfg.Geometry == (layout.Geometry{}) {
+		cfg.Geometry = layout.DefaultGeometry()
+	}
+	if cfg.Order == nil {
+		cfg.Order = layout.Order{}
+	}
+	a := &App{
 		config:        cfg,
-		scanner:       fs.NewScanner(fs.ScannerOptions{MaxDepth: 1, ShowHidden: cfg.ShowHidden}),
 		renderer:      renderer.New(),
 		inputState:    input.NewInputState(),
 		expandedPaths: make(map[string]bool),
-		settings:      ui.NewSettingsState(cfg.ShowHidden, cfg.Theme, cfg.MaxDepth, true),
+		settings:      ui.NewSettingsState(cfg.ShowHidden, cfg.Theme, cfg.MaxDepth, true, cfg.ColorMode),
+		legend:        ui.NewLegendState(),
+		timeline:      ui.NewTimelineState(),
+		breakdown:     ui.NewBreakdownState(),
+		animator:      scene.NewAnimator(),
+		layoutMode:    cfg.Layout,
+		layoutCache:   layout.NewIncremental(),
+		references:    []config.Reference{{Kind: config.RefNow}, {Kind: config.RefScan}},
+	}
+	if cfg.Reference.Kind == config.RefDate {
+		a.references = append(a.references, cfg.Reference)
+	}
+	for i, ref := range a.references {
+		a.settings.ReferenceOptions = append(a.settings.ReferenceOptions, ref.String())
+		if ref == cfg.Reference {
+			a.settings.Reference = i
+		}
 	}
+	a.settings.TimeField = cfg.TimeField
+	a.settings.Shading = cfg.Shading
+	a.settings.Sort = cfg.Sort
+	a.settings.Geometry = cfg.Geometry
+	a.settings.Stable = cfg.Stable
+	a.settings.Fisheye = cfg.Fisheye
+	a.renderer.Shading = cfg.Shading
+	a.scanner = a.newScanner()
+	return a
+}
+
+// newScanner creates the scanner for the opening scan and lazy loads: one
+// level, so the view appears at once (see startPrescan).
+func (a *App) newScanner() *fs.Scanner {
+	return a.scannerTo(1)
+}
+
+// scannerTo creates a scanner to depth (0 = unlimited) for the current
+// settings. Birth times are only captured when they drive the coloring,
+// since on Linux they cost a statx call.
+func (a *App) scannerTo(depth int) *fs.Scanner {
+	return fs.NewScanner(fs.ScannerOptions{
+		MaxDepth:   depth,
+		ShowHidden: a.config.ShowHidden,
+		BirthTime:  a.config.TimeField == fs.TimeBirth,
+	})
 }
 
 // Run is the main entry point - initializes window and runs the main loop.
 func (a *App) Run() {
-	rl.SetConfigFlags(rl.FlagWindowResizable)
-	rl.InitWindow(int32(a.config.Width), int32(a.config.Height),
-		fmt.Sprintf("FSNRedux - %s", a.config.RootPath))
-	defer rl.CloseWindow()
-	color.InitTheme(a.config.Theme)
-	ui.LoadFont()
-	defer ui.UnloadFont()
+	a.openWindow(fmt.Sprintf("FSNRedux - %s", a.config.RootPath))
+	defer a.closeWindow()
 	rl.SetTargetFPS(60)
 	rl.SetExitKey(0) // Disable Escape-to-quit so Escape works for in-app actions
 
@@ -98,14 +202,244 @@ func (a *App) Run() {
 	}
 }
 
+// openWindow creates the window and loads theme and font resources.
+func (a *App) openWindow(title string) {
+	rl.SetConfigFlags(rl.FlagWindowResizable)
+	rl.InitWindow(int32(a.config.Width), int32(a.config.Height), title)
+	color.InitTheme(a.config.Theme)
+	ui.LoadFont()
+}
+
+// closeWindow releases GPU resources and closes the window.
+func (a *App) closeWindow() {
+	a.renderer.Unload()
+	ui.UnloadFont()
+	rl.CloseWindow()
+}
+
 // startScan kicks off an async filesystem scan.
 func (a *App) startScan() {
 	a.scanning = true
 	a.tree = nil
 	a.graph = nil
+	a.repos = nil
+	a.gitResults = nil
+	a.dirLoads = nil
+	a.breakdownCache = nil
+	a.timeline.Active = false
+	a.cancelPrescan()
 	a.scanResult = a.scanner.Scan(context.Background(), a.config.RootPath)
 }
 
+// startPrescan scans the root down to the depth setting in the background,
+// unless the tree already reaches that deep. The result replaces the tree
+// when it arrives (see adoptTree); open makes it also expand to the depth.
+func (a *App) startPrescan(open bool) {
+	a.cancelPrescan()
+	depth := a.config.MaxDepth
+	if a.tree == nil || fs.DepthCovers(a.scannedDepth, depth) {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	a.prescanCancel = cancel
+	a.prescanDepth = depth
+	a.prescanOpen = open
+	a.prescanResult = a.scannerTo(depth).Scan(ctx, a.config.RootPath)
+	a.settings.Prescanning = true
+}
+
+// cancelPrescan stops a running pre-scan and drops its result.
+func (a *App) cancelPrescan() {
+	if a.prescanCancel != nil {
+		a.prescanCancel()
+	}
+	a.prescanCancel = nil
+	a.prescanResult = nil
+	a.settings.Prescanning = false
+}
+
+// adoptTree swaps in a deeper scan of the current root. Expansion and the
+// selection carry over by path, and directories opened beyond the scan are
+// loaded again. With open, directories above depth are expanded too, and
+// the camera is reframed unless the user has moved it or selected
+// something since the tree opened.
+func (a *App) adoptTree(tree *fs.Tree, depth int, open bool) {
+	a.tree = tree
+	a.scannedDepth = depth
+	a.layoutCache.Reset()
+	a.dirLoads = nil
+	a.breakdownCache = nil
+	if open && depth > 0 {
+		a.expandToDepth(tree.Root, depth-1)
+	}
+	a.loadExpanded(tree.Root)
+	a.annotateGit(tree.Root)
+	a.startGitStatus()
+	a.rebuildLayout(open && a.currentView() == a.openView)
+}
+
+// view is what the user is looking at: the camera orbit and the selection.
+type view struct {
+	target               rl.Vector3
+	distance, theta, phi float32
+	selected             string
+}
+
+// currentView captures the camera orbit and the selection.
+func (a *App) currentView() view {
+	cam := a.inputState.Camera
+	return view{cam.Target, cam.Distance, cam.Theta, cam.Phi, a.selectedPath}
+}
+
+// dirLoad is a directory read in the background for loadExpanded: entry
+// is in the tree, loaded a detached copy holding what was read.
+type dirLoad struct {
+	entry, loaded *fs.Entry
+}
+
+// loadExpanded lazily loads expanded directories under entry that the
+// scan did not reach. They are read in the background and spliced into the
+// tree by update (see spliceDirs).
+func (a *App) loadExpanded(entry *fs.Entry) {
+	var pending []*fs.Entry
+	a.collectUnloaded(entry, &pending)
+	if len(pending) == 0 {
+		return
+	}
+	scanner := a.scanner
+	ch := make(chan []dirLoad, 1)
+	a.dirLoads = append(a.dirLoads, ch)
+	go func() {
+		loads := make([]dirLoad, len(pending))
+		for i, e := range pending {
+			loaded := &fs.Entry{Name: e.Name, Path: e.Path, Type: e.Type, Depth: e.Depth}
+			scanner.LoadDir(loaded)
+			loads[i] = dirLoad{e, loaded}
+		}
+		ch <- loads
+	}()
+}
+
+// collectUnloaded appends the expanded directories under entry that are
+// not loaded yet.
+func (a *App) collectUnloaded(entry *fs.Entry, into *[]*fs.Entry) {
+	if !entry.IsDir() || !a.expandedPaths[entry.Path] {
+		return
+	}
+	if !entry.Loaded {
+		*into = append(*into, entry)
+		return
+	}
+	for _, child := range entry.Children {
+		a.collectUnloaded(child, into)
+	}
+}
+
+// spliceDirs moves directories read by loadExpanded into the tree, then
+// loads whatever is expanded below them and their nested repositories.
+func (a *App) spliceDirs(loads []dirLoad) {
+	for _, l := range loads {
+		e := l.entry
+		if e.Loaded {
+			continue
+		}
+		e.Children = l.loaded.Children
+		e.Size = l.loaded.Size
+		e.Error = l.loaded.Error
+		e.RepoRoot = e.RepoRoot || l.loaded.RepoRoot
+		e.Loaded = true
+		a.layoutCache.Invalidate(e.Path)
+		a.loadNestedRepos(e)
+		a.annotateGit(e)
+		a.loadExpanded(e)
+	}
+}
+
+// startGitStatus loads status for every repository in the scanned tree
+// (and the one enclosing the scan root, if any) in the background.
+func (a *App) startGitStatus() {
+	if a.tree == nil || !git.Available() {
+		return
+	}
+	var roots []string
+	if root := git.FindRoot(a.tree.Root.Path); root != "" {
+		roots = append(roots, root)
+	}
+	a.collectRepoRoots(a.tree.Root, &roots)
+	a.openRepos(roots)
+}
+
+// openRepos opens the repositories at roots in the background. git.Open
+// runs git status and git log, which take seconds in a large repository,
+// so update merges the result with addRepos once it arrives.
+func (a *App) openRepos(roots []string) {
+	if len(roots) == 0 {
+		return
+	}
+	ch := make(chan []*git.Repo, 1)
+	a.gitResults = append(a.gitResults, ch)
+	go func() {
+		var repos []*git.Repo
+		for _, root := range roots {
+			if repo, err := git.Open(root); err == nil {
+				repos = append(repos, repo)
+			}
+		}
+		ch <- repos
+	}()
+}
+
+// collectRepoRoots appends the paths of loaded repository roots below entry.
+func (a *App) collectRepoRoots(entry *fs.Entry, roots *[]string) {
+	if entry.RepoRoot && a.repoAt(entry.Path) == nil && !containsString(*roots, entry.Path) {
+		*roots = append(*roots, entry.Path)
+	}
+	for _, child := range entry.Children {
+		if child.IsDir() {
+			a.collectRepoRoots(child, roots)
+		}
+	}
+}
+
+// annotateGit applies git status to entry and its loaded descendants.
+// Repos are applied outermost first so nested repositories win.
+func (a *App) annotateGit(entry *fs.Entry) {
+	for _, repo := range a.repos {
+		repo.Annotate(entry)
+	}
+}
+
+// addRepos merges newly loaded repositories, keeping them ordered outermost first.
+func (a *App) addRepos(repos []*git.Repo) {
+	for _, repo := range repos {
+		if a.repoAt(repo.Root) == nil {
+			a.repos = append(a.repos, repo)
+		}
+	}
+	sort.Slice(a.repos, func(i, j int) bool {
+		return len(a.repos[i].Root) < len(a.repos[j].Root)
+	})
+}
+
+// repoAt returns the repository rooted exactly at path, or nil.
+func (a *App) repoAt(path string) *git.Repo {
+	for _, repo := range a.repos {
+		if repo.Root == path {
+			return repo
+		}
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
 // update handles input and checks for scan completion.
 func (a *App) update() {
 	// Check if scan completed
@@ -117,8 +451,13 @@ func (a *App) update() {
 				if result.Error == nil && result.Tree != nil {
 					a.tree = result.Tree
 					a.treeViewState = ui.NewTreeViewState(a.tree.Root.Path)
+					a.treeViewState.Sort = a.config.Sort
+					a.scannedDepth = 1
 					a.expandedPaths[a.tree.Root.Path] = true
 					a.rebuildLayout(true)
+					a.openView = a.currentView()
+					a.startGitStatus()
+					a.startPrescan(true)
 				}
 			}
 		default:
@@ -126,12 +465,106 @@ func (a *App) update() {
 		}
 	}
 
+	// Swap in the pre-scanned tree once it is ready
+	if a.prescanResult != nil {
+		select {
+		case result := <-a.prescanResult:
+			a.prescanResult = nil
+			a.prescanCancel = nil
+			a.settings.Prescanning = false
+			if result.Error == nil && result.Tree != nil && a.tree != nil {
+				a.adoptTree(result.Tree, a.prescanDepth, a.prescanOpen)
+			}
+		default:
+		}
+	}
+
+	// Splice in expanded directories read in the background
+	if len(a.dirLoads) > 0 {
+		loaded := false
+		waiting := a.dirLoads
+		a.dirLoads = nil // spliceDirs may start more loads
+		for _, ch := range waiting {
+			select {
+			case loads := <-ch:
+				a.spliceDirs(loads)
+				loaded = true
+			default:
+				a.dirLoads = append(a.dirLoads, ch)
+			}
+		}
+		if loaded && a.tree != nil {
+			a.rebuildLayout(false)
+		}
+	}
+
+	// Merge repositories whose git status finished loading
+	if len(a.gitResults) > 0 {
+		loaded := false
+		pending := a.gitResults[:0]
+		for _, ch := range a.gitResults {
+			select {
+			case repos := <-ch:
+				a.addRepos(repos)
+				loaded = true
+			default:
+				pending = append(pending, ch)
+			}
+		}
+		a.gitResults = pending
+		if loaded && a.tree != nil {
+			a.annotateGit(a.tree.Root)
+			a.updateHighlight()
+			a.rebuildLayout(false)
+		}
+	}
+
 	// Sync text input state to disable camera/shortcut keys
 	sidebarSearchActive := a.treeViewState != nil && a.treeViewState.SearchActive
 	textActive := a.inputBar.Active || sidebarSearchActive
 	modalOpen := a.inspectOpen || a.settings.Open || a.preview.Open
 	a.inputState.TextInputActive = textActive || modalOpen
 	a.inputState.Camera.KeyboardEnabled = !textActive && !modalOpen
+	screenW, screenH := int32(rl.GetScreenWidth()), int32(rl.GetScreenHeight())
+	a.inputState.OverlayCaptured = a.timeline.Captures(screenW) || a.breakdown.Captures(screenW, screenH) ||
+		(a.graph != nil && a.legend.Captures(a.legendTitle(), a.colorMapping().Legend(), screenH))
+
+	// Collect a finished breakdown aggregation
+	if a.breakdownResult != nil {
+		select {
+		case bd := <-a.breakdownResult:
+			a.breakdownResult = nil
+			a.breakdown.Loading = false
+			if bd == nil && a.graph != nil {
+				// Full scan failed - fall back to what is loaded
+				if node := a.graph.FindByPath(a.breakdownPath); node != nil {
+					bd = fs.ComputeBreakdown(node.Entry)
+				}
+			} else if bd != nil {
+				a.cacheBreakdown(bd)
+			}
+			a.breakdownData = bd
+		default:
+		}
+	}
+	if a.breakdown.Open {
+		a.refreshBreakdown()
+	}
+
+	// Fisheye: follow the selection (changed last frame, e.g. in the sidebar)
+	a.refocus()
+
+	// Step expand/collapse tweens; time travel recolors once they land
+	if a.graph != nil && a.graph.Animating() && !a.graph.Tick(rl.GetFrameTime()) {
+		a.applyTimeline()
+	}
+
+	// Ease the virtual clock toward the slider position
+	if a.timeline.Active {
+		if _, animating := a.animator.TickValue(rl.GetFrameTime()); animating {
+			a.applyTimeline()
+		}
+	}
 
 	// Check sidebar search submit
 	if a.treeViewState != nil && a.treeViewState.SearchSubmit != "" {
@@ -152,6 +585,7 @@ func (a *App) update() {
 		if rl.IsKeyPressed(rl.KeySpace) || rl.IsKeyPressed(rl.KeyEscape) {
 			a.inspectOpen = false
 			a.inspectInfo = nil
+			a.inspectRepo = nil
 		}
 		return
 	}
@@ -203,9 +637,11 @@ func (a *App) update() {
 			return
 		}
 
-		// Enter = expand selected directory
+		// Enter = expand selected directory or group of small files
 		if a.inputState.ExpandRequested {
-			if sel := a.inputState.Picker.SelectedNode; sel != nil && sel.Entry != nil && sel.Entry.IsDir() {
+			if sel := a.inputState.Picker.SelectedNode; sel != nil && sel.Group != nil {
+				a.expandGroup(sel.Group)
+			} else if sel != nil && sel.Entry != nil && sel.Entry.IsDir() {
 				if !a.expandedPaths[sel.Entry.Path] {
 					a.expandDir(sel.Entry.Path, sel)
 				}
@@ -214,19 +650,17 @@ func (a *App) update() {
 
 		// Escape = collapse selected dir / go to parent
 		if a.inputState.BackRequested {
-			// First clear search results if active
-			if len(a.searchResults) > 0 {
+			// First clear a box selection or search results if active
+			if len(a.inputState.Picker.Selection) > 0 {
+				a.inputState.Picker.Selection = nil
+			} else if len(a.searchResults) > 0 {
 				a.searchResults = nil
 				a.searchIndex = 0
 			} else if sel := a.inputState.Picker.SelectedNode; sel != nil {
 				if sel.Entry != nil && sel.Entry.IsDir() && a.expandedPaths[sel.Entry.Path] {
 					// Collapse current dir
-					delete(a.expandedPaths, sel.Entry.Path)
-					if a.treeViewState != nil {
-						delete(a.treeViewState.ExpandedDirs, sel.Entry.Path)
-					}
 					a.selectedPath = sel.Entry.Path
-					a.rebuildLayout(false)
+					a.collapseDir(sel.Entry.Path)
 				} else if sel.Parent != nil {
 					// Go to parent
 					a.inputState.Picker.SelectedNode = sel.Parent
@@ -268,6 +702,7 @@ func (a *App) update() {
 					// Directories get the inspect panel
 					info := sel.Entry.Inspect()
 					a.inspectInfo = &info
+					a.inspectRepo = a.repoAt(sel.Entry.Path)
 					a.inspectOpen = true
 				} else {
 					// Files get the preview panel
@@ -288,6 +723,34 @@ func (a *App) update() {
 			a.settings.Open = true
 		}
 
+		// C = cycle color mode
+		if a.inputState.CycleColorRequested {
+			a.settings.ColorMode = a.settings.ColorMode.Next()
+			a.applySettingsAction(ui.SettingsCycleColorMode)
+		}
+
+		// T = toggle time-travel slider
+		if a.inputState.TimeTravelRequested {
+			a.toggleTimeTravel()
+		}
+
+		// E = toggle size breakdown panel
+		if a.inputState.BreakdownRequested {
+			a.toggleBreakdown()
+		}
+
+		// M = toggle the fisheye around the selected directory
+		if a.inputState.FisheyeRequested {
+			a.settings.Fisheye = !a.settings.Fisheye
+			a.applySettingsAction(ui.SettingsToggleFisheye)
+		}
+
+		// X = export the scene as shown
+		if a.inputState.ExportRequested {
+			a.inputBar.Open(ui.InputBarExport, defaultExportPath)
+			return
+		}
+
 		// Search result navigation: N=next, P=prev
 		if len(a.searchResults) > 0 && !a.inputState.TextInputActive {
 			if rl.IsKeyPressed(rl.KeyN) {
@@ -310,12 +773,23 @@ func (a *App) selectNextVisible(direction int) {
 		return
 	}
 
-	// Build flat list of visible nodes
+	// Build flat list of visible nodes, siblings in the sort order
 	var visible []*scene.SceneNode
-	a.graph.Traverse(func(node *scene.SceneNode) bool {
+	var walk func(node *scene.SceneNode)
+	walk = func(node *scene.SceneNode) {
+		if !node.Visible {
+			return
+		}
 		visible = append(visible, node)
-		return true
-	})
+		if node.Expanded {
+			for _, child := range sortedNodes(node.Children, a.config.Sort) {
+				walk(child)
+			}
+		}
+	}
+	if a.graph.Root != nil {
+		walk(a.graph.Root)
+	}
 	if len(visible) == 0 {
 		return
 	}
@@ -344,25 +818,42 @@ func (a *App) selectNextVisible(direction int) {
 		if a.treeViewState != nil {
 			a.treeViewState.SelectedPath = node.Entry.Path
 		}
+	} else if node.Group != nil {
+		a.selectedPath = node.Path()
 	}
 	a.inputState.FocusOnNode(node)
 }
 
+// sortedNodes returns scene nodes ordered by their entries under mode.
+// Layouts may place children in any order (TreeV puts files before
+// subdirectories), so Tab follows the sort order instead.
+func sortedNodes(nodes []*scene.SceneNode, mode fs.SortMode) []*scene.SceneNode {
+	sorted := append([]*scene.SceneNode(nil), nodes...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i].Entry, sorted[j].Entry
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return mode.Less(a, b)
+	})
+	return sorted
+}
+
 // handleClickedPath processes a double-clicked path (expand/collapse dirs).
 func (a *App) handleClickedPath(clickedPath string) {
 	a.selectedPath = clickedPath
 	if a.treeViewState != nil {
 		a.treeViewState.SelectedPath = clickedPath
 	}
+	node := a.graph.FindByPath(clickedPath)
+	if node != nil && node.Group != nil {
+		a.expandGroup(node.Group)
+		return
+	}
 	// Expand/collapse directories on double-click
-	if node := a.graph.FindByPath(clickedPath); node != nil && node.Entry != nil && node.Entry.IsDir() {
+	if node != nil && node.Entry != nil && node.Entry.IsDir() {
 		if a.expandedPaths[clickedPath] {
-			// Collapse
-			delete(a.expandedPaths, clickedPath)
-			if a.treeViewState != nil {
-				delete(a.treeViewState.ExpandedDirs, clickedPath)
-			}
-			a.rebuildLayout(false)
+			a.collapseDir(clickedPath)
 		} else {
 			// Expand
 			a.expandDir(clickedPath, node)
@@ -378,6 +869,9 @@ func (a *App) expandDir(path string, node *scene.SceneNode) {
 	}
 	if !node.Entry.Loaded {
 		a.scanner.LoadDir(node.Entry)
+		a.layoutCache.Invalidate(path)
+		a.loadNestedRepos(node.Entry)
+		a.annotateGit(node.Entry)
 	}
 	a.selectedPath = path
 	a.rebuildLayout(false)
@@ -386,6 +880,41 @@ func (a *App) expandDir(path string, node *scene.SceneNode) {
 	}
 }
 
+// collapseDir collapses a directory, regrouping its small files.
+func (a *App) collapseDir(path string) {
+	delete(a.expandedPaths, path)
+	delete(a.expandedPaths, layout.GroupPath(path))
+	if a.treeViewState != nil {
+		delete(a.treeViewState.ExpandedDirs, path)
+	}
+	a.rebuildLayout(false)
+}
+
+// expandGroup lays out a group's small files one by one and selects their
+// directory, since the group's own node goes away.
+func (a *App) expandGroup(g *layout.Group) {
+	a.expandedPaths[g.Path()] = true
+	a.selectedPath = g.Dir.Path
+	if a.treeViewState != nil {
+		a.treeViewState.SelectedPath = g.Dir.Path
+	}
+	a.rebuildLayout(false)
+	if node := a.graph.FindByPath(g.Dir.Path); node != nil {
+		a.inputState.FocusOnNode(node)
+	}
+}
+
+// loadNestedRepos opens repositories discovered by lazily loading entry,
+// in the background like startGitStatus.
+func (a *App) loadNestedRepos(entry *fs.Entry) {
+	if !git.Available() {
+		return
+	}
+	var roots []string
+	a.collectRepoRoots(entry, &roots)
+	a.openRepos(roots)
+}
+
 // handleInputBarSubmit processes the input bar when the user presses Enter.
 func (a *App) handleInputBarSubmit() {
 	text := strings.TrimSpace(a.inputBar.Text)
@@ -401,6 +930,8 @@ func (a *App) handleInputBarSubmit() {
 		a.navigateToPath(text)
 	case ui.InputBarSearch:
 		a.searchFor(text)
+	case ui.InputBarExport:
+		a.exportView(text)
 	}
 }
 
@@ -511,53 +1042,200 @@ func (a *App) rebuildLayout(autoFrame bool) {
 	if a.tree == nil {
 		return
 	}
-	opts := layout.DefaultOptions(layout.ModeTreeV)
+	opts := layout.DefaultOptions(a.layoutMode)
 	opts.ExpandedPaths = a.expandedPaths
-	layoutRoot := layout.Compute(a.tree, opts)
-	a.graph = scene.NewGraph(layoutRoot, a.expandedPaths)
+	opts.ColorMode = a.settings.ColorMode
+	opts.AgeScale = a.ageScale()
+	opts.TimeField = a.config.TimeField
+	opts.SectorHeight = a.config.SectorHeight
+	opts.FileHeights = a.config.FileHeights
+	opts.FileFootprint = a.config.FileFootprint
+	opts.Sort = a.config.Sort
+	opts.Geometry = a.config.Geometry
+	opts.MaxDepth = a.config.MaxDepth
+	opts.Stable = a.config.Stable
+	opts.Order = a.config.Order
+	a.focusPath = a.fisheyeFocus()
+	opts.Focus = a.focusPath
+	opts.Magnify = layout.DefaultMagnify
+	layoutRoot := a.layoutCache.Compute(a.tree, opts)
+	if a.graph != nil && a.graph.Root != nil && a.graph.Root.Path() == a.tree.Root.Path {
+		// Same root, possibly rescanned deeper: update in place so node IDs
+		// persist and moved nodes glide
+		duration := float32(scene.DefaultMoveDuration)
+		if autoFrame {
+			duration = 0
+		}
+		a.graph.Apply(layoutRoot, a.expandedPaths, duration)
+	} else {
+		a.graph = scene.NewGraph(layoutRoot, a.expandedPaths)
+	}
 
-	// Restore selection pointer after rebuild
+	// Restore selection pointers after rebuild
 	if a.selectedPath != "" {
 		a.inputState.Picker.SelectedNode = a.graph.FindByPath(a.selectedPath)
 	}
+	a.inputState.Picker.Selection = a.remapNodes(a.inputState.Picker.Selection)
 	a.inputState.Picker.HoveredNode = nil
 
 	if autoFrame {
 		a.frameCamera()
 	}
+	a.applyTimeline()
 }
 
-// frameCamera positions the camera to see the entire scene.
-func (a *App) frameCamera() {
-	if a.graph == nil || a.graph.Root == nil {
+// fisheyeFocus returns the directory the fisheye enlarges: the selected
+// one, or the selected file's. It is empty while the fisheye is off.
+func (a *App) fisheyeFocus() string {
+	if !a.config.Fisheye || a.graph == nil {
+		return ""
+	}
+	node := a.graph.FindByPath(a.selectedPath)
+	if node != nil && (node.Entry == nil || !node.Entry.IsDir()) {
+		node = node.Parent
+	}
+	if node == nil {
+		return ""
+	}
+	return node.Path()
+}
+
+// refocus re-lays the scene out once the fisheye focus changes, so nodes
+// glide to their new sizes. A camera move to the selection in progress is
+// redirected to where the selection is headed.
+func (a *App) refocus() {
+	if a.graph == nil || a.fisheyeFocus() == a.focusPath {
 		return
 	}
-	minBounds := rl.NewVector3(float32(1e30), float32(1e30), float32(1e30))
-	maxBounds := rl.NewVector3(float32(-1e30), float32(-1e30), float32(-1e30))
+	a.rebuildLayout(false)
+	if sel := a.graph.FindByPath(a.selectedPath); sel != nil && a.inputState.Camera.IsAnimating() {
+		a.inputState.Camera.AnimateTo(a.graph.RestingCenter(sel))
+	}
+}
 
-	a.graph.Traverse(func(node *scene.SceneNode) bool {
-		if node.Bounds.Min.X < minBounds.X {
-			minBounds.X = node.Bounds.Min.X
+// remapNodes finds the nodes at the same paths in the current graph,
+// dropping any that no longer exist.
+func (a *App) remapNodes(nodes []*scene.SceneNode) []*scene.SceneNode {
+	var out []*scene.SceneNode
+	for _, n := range nodes {
+		if n.Entry == nil {
+			continue
 		}
-		if node.Bounds.Min.Y < minBounds.Y {
-			minBounds.Y = node.Bounds.Min.Y
+		if m := a.graph.FindByPath(n.Entry.Path); m != nil {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// selectionTotals counts the selected files and sums the selection's size,
+// skipping entries already counted through a selected ancestor directory.
+func selectionTotals(nodes []*scene.SceneNode) (files int, bytes int64) {
+	selected := make(map[*scene.SceneNode]bool, len(nodes))
+	for _, n := range nodes {
+		selected[n] = true
+	}
+	for _, n := range nodes {
+		if n.Entry == nil {
+			continue
 		}
-		if node.Bounds.Min.Z < minBounds.Z {
-			minBounds.Z = node.Bounds.Min.Z
+		covered := false
+		for p := n.Parent; p != nil; p = p.Parent {
+			if selected[p] {
+				covered = true
+				break
+			}
 		}
-		if node.Bounds.Max.X > maxBounds.X {
-			maxBounds.X = node.Bounds.Max.X
+		if !n.Entry.IsDir() {
+			files++
 		}
-		if node.Bounds.Max.Y > maxBounds.Y {
-			maxBounds.Y = node.Bounds.Max.Y
+		if !covered {
+			bytes += n.Entry.Size
 		}
-		if node.Bounds.Max.Z > maxBounds.Z {
-			maxBounds.Z = node.Bounds.Max.Z
+	}
+	return files, bytes
+}
+
+// toggleTimeTravel shows or hides the time-travel slider. The slider spans
+// from the oldest loaded entry to the scan time and starts at the scan time.
+func (a *App) toggleTimeTravel() {
+	if a.tree == nil {
+		return
+	}
+	a.timeline.Active = !a.timeline.Active
+	if !a.timeline.Active {
+		a.rebuildLayout(false) // restores colors and clears fades
+		return
+	}
+
+	a.timelineTo = a.tree.ScannedAt
+	a.timelineFrom = a.oldestTime(a.tree.Root)
+	if a.timelineFrom.IsZero() || !a.timelineFrom.Before(a.timelineTo) {
+		a.timelineFrom = a.timelineTo.Add(-24 * time.Hour)
+	}
+	a.timeline.Value = 1
+	a.animator.SetValue(unixSeconds(a.timelineTo))
+	a.rebuildLayout(false)
+}
+
+// oldestTime returns the earliest non-zero timestamp among loaded entries.
+func (a *App) oldestTime(entry *fs.Entry) time.Time {
+	oldest := entry.Time(a.config.TimeField)
+	for _, child := range entry.Children {
+		if t := a.oldestTime(child); !t.IsZero() && (oldest.IsZero() || t.Before(oldest)) {
+			oldest = t
 		}
+	}
+	return oldest
+}
+
+// virtualNow returns the (animated) moment the time-travel view shows.
+func (a *App) virtualNow() time.Time {
+	return time.Unix(0, int64(a.animator.Value.Current*1e9))
+}
+
+// applyTimeline fades out entries newer than the virtual now and recolors
+// files relative to it. No-op when time travel is off.
+func (a *App) applyTimeline() {
+	if a.graph == nil || !a.timeline.Active {
+		return
+	}
+	window := a.timelineTo.Sub(a.timelineFrom) / 40
+	if window < time.Hour {
+		window = time.Hour
+	}
+	// Only ages depend on the virtual now; other modes keep their colors
+	var recolor func(*fs.Entry) rl.Color
+	if mapping := a.colorMapping(); mapping.Mode == color.ModeAge {
+		recolor = func(e *fs.Entry) rl.Color { return mapping.Color(e, 0) }
+	}
+	a.graph.ApplyVirtualNow(a.virtualNow(), window, a.config.TimeField, recolor)
+	a.updateHighlight()
+}
+
+// unixSeconds converts t to fractional unix seconds for animation.
+func unixSeconds(t time.Time) float64 {
+	return float64(t.UnixNano()) / 1e9
+}
+
+// frameCamera positions the camera to see the entire scene.
+func (a *App) frameCamera() {
+	if a.graph == nil || a.graph.Root == nil {
+		return
+	}
+	a.inputState.Camera.FrameScene(a.sceneBounds())
+}
+
+// sceneBounds returns the box enclosing every node in the graph.
+func (a *App) sceneBounds() (rl.Vector3, rl.Vector3) {
+	minBounds := rl.NewVector3(float32(1e30), float32(1e30), float32(1e30))
+	maxBounds := rl.NewVector3(float32(-1e30), float32(-1e30), float32(-1e30))
+	a.graph.Traverse(func(node *scene.SceneNode) bool {
+		minBounds = rl.Vector3Min(minBounds, node.Bounds.Min)
+		maxBounds = rl.Vector3Max(maxBounds, node.Bounds.Max)
 		return true
 	})
-
-	a.inputState.Camera.FrameScene(minBounds, maxBounds)
+	return minBounds, maxBounds
 }
 
 // draw renders one frame.
@@ -569,10 +1247,15 @@ func (a *App) draw() {
 	rl.ClearBackground(color.Background)
 
 	// 3D viewport
+	view := scene.NewView(a.inputState.Camera.Camera, screenW, screenH)
 	rl.BeginMode3D(a.inputState.Camera.Camera)
 	renderer.DrawGround()
 	if a.graph != nil {
-		a.renderer.DrawScene(a.graph, a.inputState.Picker.SelectedNode, a.inputState.Picker.HoveredNode)
+		a.renderer.DrawScene(a.graph, view, a.inputState.Picker.SelectedNode, a.inputState.Picker.HoveredNode)
+		a.renderer.DrawSelection(a.inputState.Picker.Selection)
+		if a.settings.Labels != ui.LabelsScreen {
+			a.drawWorldLabels(view)
+		}
 	}
 	rl.EndMode3D()
 
@@ -580,17 +1263,23 @@ func (a *App) draw() {
 	// Uses shared placement tracker to prevent overlapping text/icons
 	if a.graph != nil {
 		var placed []screenRect
-		placed = a.drawSceneLabels(placed)
-		a.drawFileIcons(placed)
+		if a.settings.Labels == ui.LabelsScreen {
+			placed = a.drawSceneLabels(view, placed)
+		}
+		a.drawFileIcons(view, placed)
 	}
 
 	// Floating tooltip for hovered 3D node
-	if a.inputState.Picker.HoveredNode != nil && a.inputState.Picker.HoveredNode.Entry != nil {
-		hNode := a.inputState.Picker.HoveredNode
+	if hNode := a.inputState.Picker.HoveredNode; hNode != nil && (hNode.Entry != nil || hNode.Group != nil) {
+		hc := hNode.Center()
 		screenPos := rl.GetWorldToScreen(rl.NewVector3(
-			hNode.Position.X, hNode.Position.Y+hNode.Size.Y/2, hNode.Position.Z,
+			hc.X, hNode.Position.Y+hNode.Size.Y/2, hc.Z,
 		), a.inputState.Camera.Camera)
-		ui.DrawSelectedTooltip(hNode.Entry, screenPos.X, screenPos.Y)
+		if hNode.Group != nil {
+			ui.DrawGroupTooltip(hNode.Group, screenPos.X, screenPos.Y)
+		} else {
+			ui.DrawSelectedTooltip(hNode.Entry, screenPos.X, screenPos.Y)
+		}
 	}
 
 	// 2D UI overlay
@@ -599,6 +1288,8 @@ func (a *App) draw() {
 	breadcrumbPath := a.config.RootPath
 	if selectedEntry != nil {
 		breadcrumbPath = selectedEntry.Path
+	} else if sel := a.inputState.Picker.SelectedNode; sel != nil && sel.Group != nil {
+		breadcrumbPath = sel.Group.Dir.Path
 	}
 	clickedBreadcrumb := ui.DrawBreadcrumb(breadcrumbPath, a.config.RootPath, screenW)
 	if clickedBreadcrumb != "" {
@@ -615,7 +1306,32 @@ func (a *App) draw() {
 	}
 
 	// Info panel
-	ui.DrawInfoPanel(selectedEntry, screenH)
+	if sel := a.inputState.Picker.SelectedNode; sel != nil && sel.Group != nil {
+		ui.DrawGroupInfo(sel.Group, screenH)
+	} else {
+		ui.DrawInfoPanel(selectedEntry, screenH)
+	}
+
+	// Color legend (click an entry to highlight its bucket)
+	if a.graph != nil {
+		if ui.DrawLegend(a.legend, a.legendTitle(), a.colorMapping().Legend(), screenH) {
+			a.breakdown.Active = ""
+			a.updateHighlight()
+		}
+	}
+
+	// Size breakdown panel (click a row to highlight its files)
+	if ui.DrawBreakdownPanel(a.breakdown, a.breakdownData, screenW, screenH) {
+		a.legend.Reset()
+		a.updateHighlight()
+	}
+
+	// Time-travel slider
+	if ui.DrawTimeline(a.timeline, a.timelineFrom, a.timelineTo, a.virtualNow(), screenW, !a.inputState.TextInputActive) {
+		span := a.timelineTo.Sub(a.timelineFrom)
+		target := a.timelineFrom.Add(time.Duration(float64(span) * float64(a.timeline.Value)))
+		a.animator.StartValueMove(unixSeconds(target), 0.35)
+	}
 
 	// Input bar overlay
 	a.inputBar.Draw(screenW)
@@ -627,13 +1343,25 @@ func (a *App) draw() {
 		stw := ui.MeasureTextUI(searchText, ui.SmallFontSize)
 		sx := screenW - stw - 12
 		sy := ui.BreadcrumbHeight + 30
+		if a.timeline.Active {
+			sy += ui.TimelineHeight + 8
+		}
 		rl.DrawRectangle(sx-4, sy-1, stw+8, 15, rl.NewColor(0, 0, 0, 180))
 		ui.DrawTextUI(searchText, sx, sy, ui.SmallFontSize, color.Active.LinkAccent)
 	}
 
+	// Box selection
+	if a.inputState.Picker.BoxActive {
+		ui.DrawSelectionBox(a.inputState.Picker.Box())
+	}
+	if sel := a.inputState.Picker.Selection; len(sel) > 0 {
+		files, bytes := selectionTotals(sel)
+		ui.DrawSelectionSummary(len(sel), files, bytes, screenW, screenH)
+	}
+
 	// Inspect panel overlay
 	if a.inspectOpen && a.inspectInfo != nil {
-		ui.DrawInspectPanel(a.inspectInfo, screenW, screenH)
+		ui.DrawInspectPanel(a.inspectInfo, a.inspectRepo, screenW, screenH)
 	}
 
 	// Preview panel overlay
@@ -686,26 +1414,28 @@ func anyOverlap(r screenRect, placed []screenRect) bool {
 }
 
 // drawSceneLabels renders nearby directory names as 2D text projected from 3D positions.
+// Only nodes that survive view culling are projected.
 // Returns updated placement list for downstream consumers.
-func (a *App) drawSceneLabels(placed []screenRect) []screenRect {
+func (a *App) drawSceneLabels(view *scene.View, placed []screenRect) []screenRect {
 	cam := a.inputState.Camera.Camera
 	sw := float32(rl.GetScreenWidth())
 	sh := float32(rl.GetScreenHeight())
 	labelsDrawn := 0
 	maxLabels := 40
 
-	a.graph.Traverse(func(node *scene.SceneNode) bool {
+	a.graph.Cull(view, func(node *scene.SceneNode, _ bool) bool {
 		if labelsDrawn >= maxLabels {
 			return false
 		}
-		if node.Entry == nil || !node.Entry.IsDir() {
+		if node.Entry == nil || !node.Entry.IsDir() || node.Opacity() <= 0.5 {
 			return true
 		}
 
 		// Distance check first (cheap)
-		dx := cam.Position.X - node.Position.X
-		dy := cam.Position.Y - node.Position.Y
-		dz := cam.Position.Z - node.Position.Z
+		center := node.Center()
+		dx := cam.Position.X - center.X
+		dy := cam.Position.Y - center.Y
+		dz := cam.Position.Z - center.Z
 		dist := float32(math.Sqrt(float64(dx*dx + dy*dy + dz*dz)))
 		if dist > 50 {
 			return true
@@ -713,9 +1443,9 @@ func (a *App) drawSceneLabels(placed []screenRect) []screenRect {
 
 		// Position label above the pedestal
 		labelPos := rl.NewVector3(
-			node.Position.X,
+			center.X,
 			node.Position.Y+node.Size.Y/2+0.15,
-			node.Position.Z,
+			center.Z,
 		)
 		screenPos := rl.GetWorldToScreen(labelPos, cam)
 
@@ -732,6 +1462,10 @@ func (a *App) drawSceneLabels(placed []screenRect) []screenRect {
 		if len(name) > 18 {
 			name = name[:16] + ".."
 		}
+		// Repository roots show their branch
+		if repo := a.repoAt(node.Entry.Path); repo != nil && repo.Branch != "" {
+			name += " @" + repo.Branch
+		}
 
 		fontSize := float32(12)
 		textWidth := ui.MeasureTextUI(name, fontSize)
@@ -760,6 +1494,87 @@ func (a *App) drawSceneLabels(placed []screenRect) []screenRect {
 	return placed
 }
 
+// World-space label limits: how many are drawn, how far away directory and
+// file names stay visible, and their line heights in world units.
+const (
+	maxWorldLabels     = 400
+	worldLabelDirDist  = 120
+	worldLabelFileDist = 14
+	worldLabelDirSize  = 0.3
+	worldLabelFileSize = 0.1
+)
+
+// drawWorldLabels draws directory names, and file names up close, as text in
+// the scene: billboarded above each node, or (LabelsGround) with directory
+// names lying on the ground in front of their pedestal. Call inside BeginMode3D.
+func (a *App) drawWorldLabels(view *scene.View) {
+	font := renderer.TextFont{Font: ui.LabelFont, Shader: ui.LabelShader, SDF: ui.LabelSDF}
+	if font.Font.BaseSize == 0 {
+		return
+	}
+	eye := a.inputState.Camera.Camera.Position
+	right, up := view.Basis()
+	ground := a.settings.Labels == ui.LabelsGround
+	drawn := 0
+
+	font.BeginLabels()
+	a.graph.Cull(view, func(node *scene.SceneNode, _ bool) bool {
+		if drawn >= maxWorldLabels {
+			return false
+		}
+		if node.Entry == nil || node.Opacity() <= 0.5 {
+			return true
+		}
+		isDir := node.Entry.IsDir()
+		footprint := node.Size.X
+		if node.Sector != nil {
+			footprint = node.Sector.Outer - node.Sector.Inner
+		}
+		maxDist, height, width := float32(worldLabelDirDist), float32(worldLabelDirSize), max(footprint, 2)
+		if !isDir {
+			maxDist, height, width = worldLabelFileDist, worldLabelFileSize, max(footprint*1.6, 0.8)
+		}
+		center := node.Center()
+		dist := rl.Vector3Distance(eye, center)
+		if dist > maxDist {
+			return true
+		}
+
+		// Fade out over the last third of the range
+		tint := color.Active.TextPrimary
+		if fadeFrom := maxDist * 2 / 3; dist > fadeFrom {
+			tint.A = uint8(255 * (1 - (dist-fadeFrom)/(maxDist-fadeFrom)))
+		}
+
+		name := node.Entry.Name
+		if repo := a.repoAt(node.Entry.Path); isDir && repo != nil && repo.Branch != "" {
+			name += " @" + repo.Branch
+		}
+		lines := renderer.WrapLabel(name, width, 3, func(s string) float32 { return font.Measure(s, height) })
+
+		if isDir && ground && node.Sector == nil {
+			front := node.Position.Z + node.Size.Z/2
+			anchor := rl.NewVector3(node.Position.X, 0.01, front+0.1+renderer.LabelHeight(len(lines), height))
+			font.DrawLabel3D(lines, anchor, rl.NewVector3(1, 0, 0), rl.NewVector3(0, 0, -1), height, tint)
+		} else {
+			// Above the node, clear of any files standing on a pedestal
+			top := node.Bounds.Max.Y
+			if isDir && node.Expanded && node.Sector == nil {
+				for _, child := range node.Children {
+					if child.Entry != nil && !child.Entry.IsDir() {
+						top = max(top, child.Bounds.Max.Y)
+					}
+				}
+			}
+			anchor := rl.NewVector3(center.X, top+height/2, center.Z)
+			font.DrawLabel3D(lines, anchor, right, up, height, tint)
+		}
+		drawn++
+		return true
+	})
+	font.EndLabels()
+}
+
 // getSelectedEntry returns the fs.Entry for the currently selected node.
 func (a *App) getSelectedEntry() *fs.Entry {
 	if a.inputState.Picker.SelectedNode != nil {
@@ -773,7 +1588,7 @@ func (a *App) applySettingsAction(action ui.SettingsAction) {
 	switch action {
 	case ui.SettingsToggleHidden:
 		a.config.ShowHidden = a.settings.ShowHidden
-		a.scanner = fs.NewScanner(fs.ScannerOptions{MaxDepth: 1, ShowHidden: a.config.ShowHidden})
+		a.scanner = a.newScanner()
 		a.expandedPaths = map[string]bool{a.config.RootPath: true}
 		a.selectedPath = ""
 		a.inputState.Picker.SelectedNode = nil
@@ -788,31 +1603,298 @@ func (a *App) applySettingsAction(action ui.SettingsAction) {
 
 	case ui.SettingsDepthUp, ui.SettingsDepthDown:
 		a.config.MaxDepth = a.settings.MaxDepth
-		// Rebuild layout with new depth (no re-scan needed)
+		// The cap applies at once; a deeper depth also pre-scans further
+		a.rebuildLayout(false)
+		a.startPrescan(false)
+
+	case ui.SettingsCycleColorMode:
+		a.config.ColorMode = a.settings.ColorMode
+		a.legend.Reset()
+		a.updateHighlight()
+		a.rebuildLayout(false)
+
+	case ui.SettingsCycleTimestamp:
+		if a.settings.TimeField == fs.TimeBirth && !a.birthTimeSupported() {
+			// Nothing to color by here; the window has no status line
+			a.settings.TimeField = a.settings.TimeField.Next()
+			fmt.Fprintf(os.Stderr, "Birth times are not recorded under %s; using %s\n", a.config.RootPath, a.settings.TimeField)
+		}
+		a.config.TimeField = a.settings.TimeField
+		if !a.scanning {
+			// Lazy loads capture birth times only while they are shown
+			a.scanner = a.newScanner()
+		}
+		if a.config.TimeField == fs.TimeBirth && a.tree != nil && !a.hasBirthTimes(a.tree.Root) {
+			// The last scan skipped birth times; fill them in instead of
+			// rescanning, and restart a pre-scan that would skip them too
+			fs.LoadBirthTimes(a.tree.Root)
+			if a.prescanCancel != nil {
+				a.startPrescan(a.prescanOpen)
+			}
+		}
+		a.updateHighlight()
 		a.rebuildLayout(false)
+
+	case ui.SettingsCycleReference:
+		if i := a.settings.Reference; i >= 0 && i < len(a.references) {
+			a.config.Reference = a.references[i]
+		}
+		a.updateHighlight()
+		a.rebuildLayout(false)
+
+	case ui.SettingsCycleShading:
+		a.config.Shading = a.settings.Shading
+		a.renderer.Shading = a.settings.Shading
+
+	case ui.SettingsCycleLabels:
+		// Drawn from settings each frame; nothing to rebuild
+
+	case ui.SettingsCycleSort:
+		a.config.Sort = a.settings.Sort
+		if a.treeViewState != nil {
+			a.treeViewState.Sort = a.settings.Sort
+		}
+		a.rebuildLayout(false)
+
+	case ui.SettingsLayoutChanged:
+		a.config.Geometry = a.settings.Geometry
+		a.rebuildLayout(false)
+
+	case ui.SettingsToggleStable:
+		a.config.Stable = a.settings.Stable
+		a.rebuildLayout(false)
+
+	case ui.SettingsToggleFisheye:
+		a.config.Fisheye = a.settings.Fisheye
+		a.refocus()
+	}
+}
+
+// birthTimeSupported reports whether the platform and filesystem record
+// creation times under the root. It is checked once per root.
+func (a *App) birthTimeSupported() bool {
+	if a.birthRoot != a.config.RootPath {
+		a.birthRoot = a.config.RootPath
+		a.birthSupported = fs.BirthTimeSupported(a.config.RootPath)
+	}
+	return a.birthSupported
+}
+
+// hasBirthTimes reports whether any loaded entry carries a birth time.
+func (a *App) hasBirthTimes(entry *fs.Entry) bool {
+	if entry == nil {
+		return false
+	}
+	if !entry.BirthTime.IsZero() {
+		return true
+	}
+	for _, child := range entry.Children {
+		if a.hasBirthTimes(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// ageScale returns the age buckets and reference time for the current settings.
+func (a *App) ageScale() color.AgeScale {
+	if a.timeline.Active {
+		// Time travel measures ages against the virtual now
+		return color.AgeScale{Buckets: a.config.AgeBuckets, Reference: a.virtualNow()}
+	}
+	var scannedAt time.Time
+	if a.tree != nil {
+		scannedAt = a.tree.ScannedAt
+	}
+	return color.AgeScale{
+		Buckets:   a.config.AgeBuckets,
+		Reference: a.config.Reference.Resolve(scannedAt),
+	}
+}
+
+// colorMapping returns the active file color mapping.
+func (a *App) colorMapping() color.Mapping {
+	return color.Mapping{Mode: a.settings.ColorMode, Age: a.ageScale(), Time: a.config.TimeField}
+}
+
+// legendTitle describes the active color mapping for the legend header.
+func (a *App) legendTitle() string {
+	mode := a.settings.ColorMode
+	if mode == color.ModeSize {
+		return "Color: Size (vs largest in folder)"
+	}
+	if mode != color.ModeAge {
+		return fmt.Sprintf("Color: %s", mode)
+	}
+	if a.timeline.Active {
+		return fmt.Sprintf("Color: %s (%s vs %s)", mode, a.config.TimeField, a.virtualNow().Format("2006-01-02"))
+	}
+	return fmt.Sprintf("Color: %s (%s vs %s)", mode, a.config.TimeField, a.config.Reference)
+}
+
+// updateHighlight points the renderer at whichever overlay (legend or
+// breakdown panel) currently has an entry highlighted.
+func (a *App) updateHighlight() {
+	if hl := a.legendHighlight(); hl != nil {
+		a.renderer.SetHighlight(hl)
+		return
+	}
+	a.renderer.SetHighlight(a.breakdownHighlight())
+}
+
+// breakdownHighlight returns a node filter matching the highlighted breakdown
+// row, or nil when nothing is highlighted.
+func (a *App) breakdownHighlight() func(node *scene.SceneNode) bool {
+	key := a.breakdown.Active
+	if !a.breakdown.Open || key == "" {
+		return nil
+	}
+	group := a.breakdown.Group
+	return func(node *scene.SceneNode) bool {
+		if node.Entry == nil {
+			return false
+		}
+		if group == ui.GroupByCategory {
+			return fs.Category(node.Entry.Name) == key
+		}
+		return fs.Extension(node.Entry.Name) == key
+	}
+}
+
+// toggleBreakdown opens or closes the size breakdown panel.
+func (a *App) toggleBreakdown() {
+	a.breakdown.Open = !a.breakdown.Open
+	if !a.breakdown.Open {
+		if a.breakdownCancel != nil {
+			a.breakdownCancel()
+		}
+		a.breakdownPath = ""
+		a.breakdown.Active = ""
+		a.updateHighlight()
+		return
+	}
+	a.refreshBreakdown()
+}
+
+// breakdownDir returns the directory the breakdown panel describes:
+// the selected directory, the selected file's parent, or the root.
+func (a *App) breakdownDir() *fs.Entry {
+	if sel := a.inputState.Picker.SelectedNode; sel != nil && sel.Entry != nil {
+		if sel.Entry.IsDir() {
+			return sel.Entry
+		}
+		if sel.Parent != nil && sel.Parent.Entry != nil {
+			return sel.Parent.Entry
+		}
+	}
+	if a.tree != nil {
+		return a.tree.Root
+	}
+	return nil
+}
+
+// refreshBreakdown re-aggregates the panel's directory when it changed. A
+// directory the tree holds in full is aggregated from it; one that reaches
+// below the scan depth is scanned in full in the background. Either result
+// is kept per path until the tree is replaced, so reselecting is free.
+func (a *App) refreshBreakdown() {
+	dir := a.breakdownDir()
+	if dir == nil || dir.Path == a.breakdownPath {
+		return
+	}
+	if a.breakdownCancel != nil {
+		a.breakdownCancel()
+		a.breakdownCancel = nil
+	}
+	a.breakdownResult = nil // a scan for the previous directory is stale
+	a.breakdownPath = dir.Path
+	a.breakdown.Active = ""
+	a.updateHighlight()
+
+	bd, ok := a.breakdownCache[dir.Path]
+	if !ok && dir.FullyLoaded() {
+		bd, ok = fs.ComputeBreakdown(dir), true
+		a.cacheBreakdown(bd)
+	}
+	if ok {
+		a.breakdownData = bd
+		a.breakdown.Loading = false
+		return
+	}
+	a.breakdownData = nil
+	a.breakdown.Loading = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a.breakdownCancel = cancel
+	ch := make(chan *fs.Breakdown, 1)
+	a.breakdownResult = ch
+	path, showHidden := dir.Path, a.config.ShowHidden
+	go func() {
+		scanner := fs.NewScanner(fs.ScannerOptions{ShowHidden: showHidden})
+		tree, err := scanner.ScanSync(ctx, path)
+		if err != nil || ctx.Err() != nil {
+			ch <- nil
+			return
+		}
+		ch <- fs.ComputeBreakdown(tree.Root)
+	}()
+}
+
+// cacheBreakdown keeps an aggregation for reuse while the tree stays.
+func (a *App) cacheBreakdown(bd *fs.Breakdown) {
+	if a.breakdownCache == nil {
+		a.breakdownCache = make(map[string]*fs.Breakdown)
+	}
+	a.breakdownCache[bd.Root] = bd
+}
+
+// legendHighlight returns a node filter matching the highlighted legend entry,
+// or nil when nothing is highlighted.
+func (a *App) legendHighlight() func(node *scene.SceneNode) bool {
+	active := a.legend.Active
+	if active < 0 {
+		return nil
+	}
+	mapping := a.colorMapping()
+	largest := make(map[*scene.SceneNode]int64) // per parent, for the size mode
+	return func(node *scene.SceneNode) bool {
+		if node.Entry == nil {
+			return false
+		}
+		l := node.Entry.Size
+		if p := node.Parent; p != nil && p.Entry != nil {
+			var ok bool
+			if l, ok = largest[p]; !ok {
+				l = color.LargestFile(p.Entry.Children)
+				largest[p] = l
+			}
+		}
+		return mapping.Bucket(node.Entry, l) == active
 	}
 }
 
 // drawFileIcons renders simple unicolor 2D icons on top of file pedestals.
-func (a *App) drawFileIcons(placed []screenRect) {
+// Files inside culled or aggregated subtrees are never projected.
+func (a *App) drawFileIcons(view *scene.View, placed []screenRect) {
 	cam := a.inputState.Camera.Camera
 	sw := float32(rl.GetScreenWidth())
 	sh := float32(rl.GetScreenHeight())
 	iconsDrawn := 0
 	maxIcons := 80
 
-	a.graph.Traverse(func(node *scene.SceneNode) bool {
+	a.graph.Cull(view, func(node *scene.SceneNode, _ bool) bool {
 		if iconsDrawn >= maxIcons {
 			return false
 		}
-		if node.Entry == nil || node.Entry.IsDir() {
+		if node.Entry == nil || node.Entry.IsDir() || node.Opacity() <= 0.5 {
 			return true
 		}
 
 		// Distance check
-		dx := cam.Position.X - node.Position.X
-		dy := cam.Position.Y - node.Position.Y
-		dz := cam.Position.Z - node.Position.Z
+		center := node.Center()
+		dx := cam.Position.X - center.X
+		dy := cam.Position.Y - center.Y
+		dz := cam.Position.Z - center.Z
 		dist := float32(math.Sqrt(float64(dx*dx + dy*dy + dz*dz)))
 		if dist > 30 {
 			return true
@@ -820,9 +1902,9 @@ func (a *App) drawFileIcons(placed []screenRect) {
 
 		// Project top-center of pedestal to screen
 		labelPos := rl.NewVector3(
-			node.Position.X,
+			center.X,
 			node.Position.Y+node.Size.Y/2+0.02,
-			node.Position.Z,
+			center.Z,
 		)
 		sp := rl.GetWorldToScreen(labelPos, cam)
 		if sp.X < 0 || sp.X > sw || sp.Y < 0 || sp.Y > sh {
@@ -858,6 +1940,12 @@ func (a *App) drawFileIcons(placed []screenRect) {
 		iconColor.A = alpha
 
 		drawSimpleIcon(icon, cx, cy, iconSize, iconColor)
+
+		// Git status badge at the icon's top-right
+		if badge, badgeColor := ui.GitBadge(node.Entry.Git); badge != "" {
+			badgeColor.A = alpha
+			ui.DrawTextUI(badge, cx+iconSize, cy-iconSize-6, ui.SmallFontSize, badgeColor)
+		}
 		placed = append(placed, rect)
 		iconsDrawn++
 		return true
@@ -948,26 +2036,7 @@ func (a *App) birdseyeView() {
 	if a.graph == nil || a.graph.Root == nil {
 		return
 	}
-	minBounds := rl.NewVector3(float32(1e30), float32(1e30), float32(1e30))
-	maxBounds := rl.NewVector3(float32(-1e30), float32(-1e30), float32(-1e30))
-
-	a.graph.Traverse(func(node *scene.SceneNode) bool {
-		if node.Bounds.Min.X < minBounds.X {
-			minBounds.X = node.Bounds.Min.X
-		}
-		if node.Bounds.Min.Z < minBounds.Z {
-			minBounds.Z = node.Bounds.Min.Z
-		}
-		if node.Bounds.Max.X > maxBounds.X {
-			maxBounds.X = node.Bounds.Max.X
-		}
-		if node.Bounds.Max.Z > maxBounds.Z {
-			maxBounds.Z = node.Bounds.Max.Z
-		}
-		return true
-	})
-
-	a.inputState.Camera.Birdseye(minBounds, maxBounds)
+	a.inputState.Camera.Birdseye(a.sceneBounds())
 }
 
 // openWithDefault opens a file or directory with the OS default application.
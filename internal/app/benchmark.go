@@ -0,0 +1,97 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/color"
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+	"github.com/Crank-Git/FSNRedux/internal/renderer"
+	"github.com/Crank-Git/FSNRedux/internal/scene"
+)
+
+// benchWarmup frames are drawn before timing each pass (shader compile, batch build).
+const benchWarmup = 10
+
+// RunBenchmark renders a fully expanded synthetic tree of the given number of
+// files, once with batched and once with per-node drawing, and prints the
+// average frame time of each pass. Labels and UI are skipped so the numbers
+// reflect scene drawing only.
+func (a *App) RunBenchmark(files, frames int) {
+	a.openWindow(fmt.Sprintf("FSNRedux benchmark - %d files", files))
+	defer a.closeWindow()
+	rl.SetTargetFPS(0) // uncapped
+
+	a.tree = fs.SyntheticTree(files, 200, 8)
+	a.expandAll(a.tree.Root)
+	a.rebuildLayout(true)
+	nodes := a.graph.VisibleNodeCount()
+	fmt.Printf("benchmark scene: %d files, %d nodes, %d frames per pass\n", files, nodes, frames)
+
+	var results [2]time.Duration
+	for pass, batching := range []bool{true, false} {
+		a.renderer.Batching = batching
+		for i := 0; i < benchWarmup; i++ {
+			a.drawBenchmarkFrame()
+		}
+		start := time.Now()
+		for i := 0; i < frames; i++ {
+			if rl.WindowShouldClose() {
+				return
+			}
+			a.drawBenchmarkFrame()
+		}
+		results[pass] = time.Since(start) / time.Duration(frames)
+		draws := nodes
+		if batching {
+			draws = a.renderer.BatchCount()
+		}
+		fmt.Printf("  %-9s %8.2f ms/frame  %7.1f fps  (%d cube draw calls)\n",
+			passName(batching), ms(results[pass]), 1/results[pass].Seconds(), draws)
+	}
+	if results[0] > 0 {
+		fmt.Printf("  speedup   %.1fx\n", float64(results[1])/float64(results[0]))
+	}
+}
+
+// drawBenchmarkFrame draws just the 3D scene.
+func (a *App) drawBenchmarkFrame() {
+	rl.BeginDrawing()
+	rl.ClearBackground(color.Background)
+	a.drawSceneOnly(int32(rl.GetScreenWidth()), int32(rl.GetScreenHeight()))
+	rl.DrawFPS(10, 10)
+	rl.EndDrawing()
+}
+
+// drawSceneOnly draws the ground and scene for a viewport of the given size,
+// without labels, highlights or UI.
+func (a *App) drawSceneOnly(width, height int32) {
+	view := scene.NewView(a.inputState.Camera.Camera, width, height)
+	rl.BeginMode3D(a.inputState.Camera.Camera)
+	renderer.DrawGround()
+	a.renderer.DrawScene(a.graph, view, nil, nil)
+	rl.EndMode3D()
+}
+
+// expandAll marks every directory under entry as expanded.
+func (a *App) expandAll(entry *fs.Entry) {
+	if !entry.IsDir() {
+		return
+	}
+	a.expandedPaths[entry.Path] = true
+	for _, child := range entry.Children {
+		a.expandAll(child)
+	}
+}
+
+func passName(batching bool) string {
+	if batching {
+		return "batched"
+	}
+	return "per-node"
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
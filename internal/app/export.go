@@ -0,0 +1,69 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Crank-Git/FSNRedux/internal/export"
+)
+
+// defaultExportPath is offered when exporting from the window.
+const defaultExportPath = "fsnredux-scene.gltf"
+
+// ExportOptions controls a scene export.
+type ExportOptions struct {
+	Output string // .gltf, .obj or .html file to write
+	Expand int    // directory levels expanded below the root
+	Full   bool   // expand every directory down to the scan depth
+}
+
+// ExportScene scans the root path, lays it out as the 3D view would show
+// it and writes the visible nodes to a glTF, OBJ or HTML viewer file. No
+// window is opened.
+func (a *App) ExportScene(opts ExportOptions) error {
+	if _, ok := export.FormatFor(opts.Output); !ok {
+		return fmt.Errorf("unknown export format for %s (want .gltf, .obj or .html)", opts.Output)
+	}
+	if opts.Expand < 0 {
+		return fmt.Errorf("invalid expand depth %d", opts.Expand)
+	}
+	depth := opts.Expand + 1
+	if opts.Full {
+		depth = a.config.MaxDepth
+	}
+	tree, err := a.scanSync(depth)
+	if err != nil {
+		return err
+	}
+
+	a.tree = tree
+	if opts.Full {
+		a.expandAll(tree.Root)
+	} else {
+		a.expandToDepth(tree.Root, opts.Expand)
+	}
+	a.rebuildLayout(false)
+	if a.graph == nil || a.graph.Root == nil {
+		return fmt.Errorf("nothing to export in %s", a.config.RootPath)
+	}
+	return export.File(opts.Output, a.graph)
+}
+
+// exportView writes the scene as currently shown (expanded directories,
+// layout mode and colors) to path, relative to the working directory. The
+// outcome is reported on stderr, as the window has no status line.
+func (a *App) exportView(path string) {
+	if a.graph == nil || a.graph.Root == nil {
+		return
+	}
+	absPath, err := filepath.Abs(path)
+	if err == nil {
+		err = export.File(absPath, a.graph)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Export failed: %v\n", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Exported scene to %s\n", absPath)
+}
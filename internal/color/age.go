@@ -2,6 +2,7 @@ package color
 
 import (
 	"math"
+	"strings"
 	"time"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
@@ -29,26 +30,77 @@ var DefaultAgeBuckets = []AgeBucket{
 // AncientColor is used for files older than all defined buckets.
 var AncientColor = rl.NewColor(70, 100, 160, 255) // steel blue
 
-// ColorFromAge returns a color based on the file's modification time.
-// Uses the DefaultAgeBuckets for discrete bucket mapping.
-func ColorFromAge(modTime time.Time) rl.Color {
-	if modTime.IsZero() {
-		return OtherColor
+// AgeScale maps timestamps onto age buckets measured from a reference time.
+// The zero value uses DefaultAgeBuckets measured from time.Now().
+type AgeScale struct {
+	Buckets   []AgeBucket // sorted by MaxAge ascending; nil = DefaultAgeBuckets
+	Reference time.Time   // "now" for age purposes; zero = time.Now()
+}
+
+// buckets returns the effective bucket list.
+func (s AgeScale) buckets() []AgeBucket {
+	if len(s.Buckets) == 0 {
+		return DefaultAgeBuckets
 	}
+	return s.Buckets
+}
 
-	age := time.Since(modTime)
-	if age < 0 {
-		// Future modification time (clock skew) - treat as newest
-		return DefaultAgeBuckets[0].Color
+// age returns how old t is relative to the scale's reference time.
+func (s AgeScale) age(t time.Time) time.Duration {
+	if s.Reference.IsZero() {
+		return time.Since(t)
 	}
+	return s.Reference.Sub(t)
+}
 
-	for _, bucket := range DefaultAgeBuckets {
+// BucketIndex returns the index of the bucket containing t.
+// len(buckets) means "older than every bucket"; -1 means unknown (zero time).
+func (s AgeScale) BucketIndex(t time.Time) int {
+	if t.IsZero() {
+		return -1
+	}
+	buckets := s.buckets()
+	age := s.age(t)
+	if age < 0 {
+		// Future time (clock skew, or after the reference) - treat as newest
+		return 0
+	}
+	for i, bucket := range buckets {
 		if age <= bucket.MaxAge {
-			return bucket.Color
+			return i
 		}
 	}
+	return len(buckets)
+}
 
-	return AncientColor
+// Color returns the bucket color for t.
+func (s AgeScale) Color(t time.Time) rl.Color {
+	i := s.BucketIndex(t)
+	buckets := s.buckets()
+	switch {
+	case i < 0:
+		return OtherColor
+	case i < len(buckets):
+		return buckets[i].Color
+	default:
+		return AncientColor
+	}
+}
+
+// AncientLabel returns the legend label for entries older than every bucket.
+func (s AgeScale) AncientLabel() string {
+	buckets := s.buckets()
+	last := buckets[len(buckets)-1].Label
+	if strings.HasPrefix(last, "< ") {
+		return "> " + strings.TrimPrefix(last, "< ")
+	}
+	return "Older"
+}
+
+// ColorFromAge returns a color based on the file's modification time.
+// Uses the DefaultAgeBuckets for discrete bucket mapping.
+func ColorFromAge(modTime time.Time) rl.Color {
+	return AgeScale{}.Color(modTime)
 }
 
 // ColorFromAgeSmooth returns a smoothly interpolated color based on file age.
@@ -93,10 +145,14 @@ func ColorFromAgeSmooth(modTime time.Time) rl.Color {
 	return hsvToColor(hue, saturation, value)
 }
 
+// quantizedBuckets is how many buckets QuantizedBucket and QuantizedColor
+// divide their range into.
+const quantizedBuckets = 32
+
 // QuantizedBucket returns a bucket index (0-31) for instanced rendering batching.
 func QuantizedBucket(modTime time.Time) int {
 	if modTime.IsZero() {
-		return 31
+		return quantizedBuckets - 1
 	}
 
 	age := time.Since(modTime)
@@ -112,13 +168,21 @@ func QuantizedBucket(modTime time.Time) int {
 		t = 1.0
 	}
 
-	bucket := int(t * 31.0)
-	if bucket > 31 {
-		bucket = 31
+	bucket := int(t * (quantizedBuckets - 1))
+	if bucket > quantizedBuckets-1 {
+		bucket = quantizedBuckets - 1
 	}
 	return bucket
 }
 
+// QuantizedColor returns the instanced batching key of a draw color: each
+// channel is cut into quantizedBuckets levels, so colors that differ only
+// slightly (mid-tween or mid-fade) share a batch.
+func QuantizedColor(c rl.Color) uint32 {
+	const step = 256 / quantizedBuckets
+	return uint32(c.R/step)<<15 | uint32(c.G/step)<<10 | uint32(c.B/step)<<5 | uint32(c.A/step)
+}
+
 // BucketColor returns the pre-computed color for a given bucket index.
 func BucketColor(bucket int) rl.Color {
 	t := float64(bucket) / 31.0
@@ -173,9 +237,19 @@ func hsvToColor(h, s, v float64) rl.Color {
 // ColorFromSize returns a color based on file size relative to a maximum.
 // Small files are cool (teal), large files are warm (amber/red).
 func ColorFromSize(size int64, maxSize int64) rl.Color {
-	if maxSize <= 0 {
+	t, ok := sizeFraction(size, maxSize)
+	if !ok {
 		return FileColor
 	}
+	return sizeGradient(t)
+}
+
+// sizeFraction places size on the size color ramp between 0 and maxSize. It
+// reports false when there is no maximum to scale against.
+func sizeFraction(size int64, maxSize int64) (float64, bool) {
+	if maxSize <= 0 {
+		return 0, false
+	}
 
 	// Logarithmic scaling so small differences at the low end are visible
 	t := math.Log1p(float64(size)) / math.Log1p(float64(maxSize))
@@ -185,7 +259,11 @@ func ColorFromSize(size int64, maxSize int64) rl.Color {
 	if t < 0.0 {
 		t = 0.0
 	}
+	return t, true
+}
 
+// sizeGradient maps t in [0, 1] onto the size color ramp.
+func sizeGradient(t float64) rl.Color {
 	// HSV: hue 180 (teal/cyan, small) -> 40 (amber, medium) -> 0 (red, large)
 	var hue float64
 	if t < 0.5 {
@@ -61,6 +61,37 @@ func TestColorFromAge_EachBucket(t *testing.T) {
 	}
 }
 
+func TestAgeScale_CustomBucketsAndReference(t *testing.T) {
+	ref := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	scale := AgeScale{
+		Buckets: []AgeBucket{
+			{MaxAge: time.Hour, Color: rl.Red, Label: "< 1h"},
+			{MaxAge: 24 * time.Hour, Color: rl.Blue, Label: "< 1d"},
+		},
+		Reference: ref,
+	}
+
+	if got := scale.BucketIndex(ref.Add(-30 * time.Minute)); got != 0 {
+		t.Errorf("30m before reference: got bucket %d, want 0", got)
+	}
+	if got := scale.BucketIndex(ref.Add(-2 * time.Hour)); got != 1 {
+		t.Errorf("2h before reference: got bucket %d, want 1", got)
+	}
+	if got := scale.BucketIndex(ref.Add(-48 * time.Hour)); got != 2 {
+		t.Errorf("2d before reference: got bucket %d, want 2 (ancient)", got)
+	}
+	// Newer than the reference counts as newest
+	if got := scale.BucketIndex(ref.Add(time.Hour)); got != 0 {
+		t.Errorf("after reference: got bucket %d, want 0", got)
+	}
+	if c := scale.Color(ref.Add(-48 * time.Hour)); c != AncientColor {
+		t.Errorf("ancient color: got %v, want %v", c, AncientColor)
+	}
+	if label := scale.AncientLabel(); label != "> 1d" {
+		t.Errorf("ancient label: got %q, want \"> 1d\"", label)
+	}
+}
+
 func TestColorFromAgeSmooth_Range(t *testing.T) {
 	// Just verify it doesn't panic for various ages
 	ages := []time.Duration{
@@ -0,0 +1,273 @@
+package color
+
+import (
+	"strings"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+)
+
+// Mode selects which entry attribute drives file colors.
+type Mode uint8
+
+const (
+	ModeAge  Mode = iota // modification time buckets
+	ModeSize             // file size buckets
+	ModeType             // file type categories
+	ModeGit              // git working-tree status
+)
+
+// modeCount is the number of defined color modes (used for cycling).
+const modeCount = 4
+
+// String returns the mode name.
+func (m Mode) String() string {
+	switch m {
+	case ModeAge:
+		return "Age"
+	case ModeSize:
+		return "Size"
+	case ModeType:
+		return "Type"
+	case ModeGit:
+		return "Git"
+	default:
+		return "Unknown"
+	}
+}
+
+// ParseMode converts a mode name ("age", "size", "type", "git") to a Mode.
+func ParseMode(name string) (Mode, bool) {
+	switch strings.ToLower(name) {
+	case "age":
+		return ModeAge, true
+	case "size":
+		return ModeSize, true
+	case "type":
+		return ModeType, true
+	case "git":
+		return ModeGit, true
+	default:
+		return ModeAge, false
+	}
+}
+
+// Next returns the mode that follows m when cycling.
+func (m Mode) Next() Mode {
+	return (m + 1) % modeCount
+}
+
+// sizeStepLabels name the legend entries of the size gradient, smallest
+// first. Files are colored against the largest file beside them, so the
+// steps are relative, not fixed sizes.
+var sizeStepLabels = []string{"Smallest", "Smaller", "Small", "Medium", "Large", "Largest"}
+
+// LargestFile returns the size of the largest file among entries: the scale
+// the size mode colors each of them against.
+func LargestFile(entries []*fs.Entry) int64 {
+	var largest int64
+	for _, e := range entries {
+		if !e.IsDir() && e.Size > largest {
+			largest = e.Size
+		}
+	}
+	return largest
+}
+
+// SizeStep returns the legend step of a file size relative to the largest
+// file beside it.
+func SizeStep(size, largest int64) int {
+	t, _ := sizeFraction(size, largest)
+	return min(int(t*float64(len(sizeStepLabels))), len(sizeStepLabels)-1)
+}
+
+// TypeCategory groups file type categories (fs.Category) under a single
+// legend color.
+type TypeCategory struct {
+	Label      string
+	Color      rl.Color
+	Categories []string
+}
+
+// DefaultTypeCategories defines the category-to-color mapping. Extensions
+// are classified by fs.Category, so the sidebar and the colors agree; files
+// whose category is not listed use OtherTypeColor.
+var DefaultTypeCategories = []TypeCategory{
+	{Label: "Code", Color: rl.NewColor(0, 173, 216, 255), Categories: []string{
+		"Source Code", "Header File", "Shell Script", "PowerShell Script", "Batch Script",
+	}},
+	{Label: "Markup / Data", Color: rl.NewColor(160, 160, 80, 255), Categories: []string{
+		"Markup", "Stylesheet", "Data (JSON)", "Data (YAML)", "Data (TOML)", "Configuration",
+		"Comma-Separated", "SQL Script", "Lock File", "Checksum", "Module File",
+	}},
+	{Label: "Document", Color: rl.NewColor(192, 57, 43, 255), Categories: []string{
+		"Markdown", "Plain Text", "Markup Document", "PDF Document", "Word Document",
+		"Spreadsheet", "Presentation",
+	}},
+	{Label: "Image", Color: rl.NewColor(140, 200, 60, 255), Categories: []string{
+		"Image", "Vector Image", "Icon",
+	}},
+	{Label: "Audio", Color: rl.NewColor(230, 126, 34, 255), Categories: []string{"Audio"}},
+	{Label: "Video", Color: rl.NewColor(155, 89, 182, 255), Categories: []string{"Video"}},
+	{Label: "Archive", Color: rl.NewColor(127, 140, 141, 255), Categories: []string{"Archive"}},
+	{Label: "Binary", Color: rl.NewColor(200, 80, 120, 255), Categories: []string{
+		"Executable", "Library", "Shared Library", "Static Library", "Binary", "Object File",
+		"WebAssembly", "Database",
+	}},
+}
+
+// OtherTypeColor is used for files that match no type category.
+var OtherTypeColor = rl.NewColor(110, 115, 125, 255)
+
+// typeCategoryIndex maps an fs.Category to its index in DefaultTypeCategories.
+var typeCategoryIndex = buildTypeCategoryIndex()
+
+func buildTypeCategoryIndex() map[string]int {
+	idx := make(map[string]int)
+	for i, cat := range DefaultTypeCategories {
+		for _, c := range cat.Categories {
+			idx[c] = i
+		}
+	}
+	return idx
+}
+
+// AgeBucketIndex returns the index of the default age bucket containing modTime.
+// len(DefaultAgeBuckets) means "older than every bucket"; -1 means unknown (zero time).
+func AgeBucketIndex(modTime time.Time) int {
+	return AgeScale{}.BucketIndex(modTime)
+}
+
+// TypeCategoryIndex returns the index of the type category for a filename.
+// len(DefaultTypeCategories) means "other".
+func TypeCategoryIndex(name string) int {
+	if i, ok := typeCategoryIndex[fs.Category(name)]; ok {
+		return i
+	}
+	return len(DefaultTypeCategories)
+}
+
+// ColorFromType returns the category color for a filename.
+func ColorFromType(name string) rl.Color {
+	i := TypeCategoryIndex(name)
+	if i < len(DefaultTypeCategories) {
+		return DefaultTypeCategories[i].Color
+	}
+	return OtherTypeColor
+}
+
+// GitStatusColors maps each git status to its color, in legend order.
+var GitStatusColors = []struct {
+	Status fs.GitStatus
+	Color  rl.Color
+	Label  string
+}{
+	{fs.GitModified, rl.NewColor(255, 170, 40, 255), "Modified"},
+	{fs.GitUntracked, rl.NewColor(90, 220, 90, 255), "Untracked"},
+	{fs.GitIgnored, rl.NewColor(110, 110, 120, 255), "Ignored"},
+	{fs.GitClean, rl.NewColor(80, 140, 220, 255), "Clean"},
+}
+
+// NoRepoColor is used for entries outside any git repository.
+var NoRepoColor = rl.NewColor(60, 60, 70, 255)
+
+// GitStatusIndex returns the legend index of a git status.
+// len(GitStatusColors) means "not in a repository".
+func GitStatusIndex(status fs.GitStatus) int {
+	for i, g := range GitStatusColors {
+		if g.Status == status {
+			return i
+		}
+	}
+	return len(GitStatusColors)
+}
+
+// ColorFromGit returns the color for a git status.
+func ColorFromGit(status fs.GitStatus) rl.Color {
+	i := GitStatusIndex(status)
+	if i < len(GitStatusColors) {
+		return GitStatusColors[i].Color
+	}
+	return NoRepoColor
+}
+
+// Mapping describes how file attributes are turned into colors.
+// The zero value colors by mtime age against the default buckets and time.Now().
+// Sizes are colored relative to the largest file in the same directory,
+// which callers pass in (see LargestFile).
+type Mapping struct {
+	Mode Mode
+	Age  AgeScale     // used by ModeAge
+	Time fs.TimeField // timestamp that represents a file's age
+}
+
+// Color returns the color of a file under the mapping; largest is the size of
+// the largest file in its directory.
+func (m Mapping) Color(e *fs.Entry, largest int64) rl.Color {
+	switch m.Mode {
+	case ModeSize:
+		return ColorFromSize(e.Size, largest)
+	case ModeType:
+		return ColorFromType(e.Name)
+	case ModeGit:
+		return ColorFromGit(e.Git)
+	default:
+		return m.Age.Color(e.Time(m.Time))
+	}
+}
+
+// Bucket returns the legend entry index a file falls into under the mapping.
+// The index matches the order of entries returned by Legend.
+func (m Mapping) Bucket(e *fs.Entry, largest int64) int {
+	switch m.Mode {
+	case ModeSize:
+		return SizeStep(e.Size, largest)
+	case ModeType:
+		return TypeCategoryIndex(e.Name)
+	case ModeGit:
+		return GitStatusIndex(e.Git)
+	default:
+		return m.Age.BucketIndex(e.Time(m.Time))
+	}
+}
+
+// LegendEntry is one swatch in a color legend.
+type LegendEntry struct {
+	Label string
+	Color rl.Color
+}
+
+// Legend returns the swatches for the mapping, in bucket order.
+func (m Mapping) Legend() []LegendEntry {
+	var entries []LegendEntry
+	switch m.Mode {
+	case ModeSize:
+		// Each step shows the gradient at its middle
+		for i, label := range sizeStepLabels {
+			t := (float64(i) + 0.5) / float64(len(sizeStepLabels))
+			entries = append(entries, LegendEntry{Label: label, Color: sizeGradient(t)})
+		}
+	case ModeType:
+		for _, c := range DefaultTypeCategories {
+			entries = append(entries, LegendEntry{Label: c.Label, Color: c.Color})
+		}
+		entries = append(entries, LegendEntry{Label: "Other", Color: OtherTypeColor})
+	case ModeGit:
+		for _, g := range GitStatusColors {
+			entries = append(entries, LegendEntry{Label: g.Label, Color: g.Color})
+		}
+		entries = append(entries, LegendEntry{Label: "Not in repo", Color: NoRepoColor})
+	default:
+		for _, b := range m.Age.buckets() {
+			entries = append(entries, LegendEntry{Label: b.Label, Color: b.Color})
+		}
+		entries = append(entries, LegendEntry{Label: m.Age.AncientLabel(), Color: AncientColor})
+	}
+	return entries
+}
+
+// Legend returns the swatches for the given mode with default age buckets.
+func Legend(mode Mode) []LegendEntry {
+	return Mapping{Mode: mode}.Legend()
+}
@@ -0,0 +1,135 @@
+package color
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+)
+
+func TestLegend_MatchesBucketCount(t *testing.T) {
+	cases := []struct {
+		mode Mode
+		want int
+	}{
+		{ModeAge, len(DefaultAgeBuckets) + 1},
+		{ModeSize, len(sizeStepLabels)},
+		{ModeType, len(DefaultTypeCategories) + 1},
+		{ModeGit, len(GitStatusColors) + 1},
+	}
+	for _, c := range cases {
+		if got := len(Legend(c.mode)); got != c.want {
+			t.Errorf("%s legend: got %d entries, want %d", c.mode, got, c.want)
+		}
+	}
+}
+
+func TestMappingBucket_MatchesLegendColor(t *testing.T) {
+	files := []*fs.Entry{
+		{Name: "main.go", Size: 500, ModTime: time.Now(), Git: fs.GitModified},
+		{Name: "photo.JPG", Size: 3 << 20, ModTime: time.Now().Add(-40 * 24 * time.Hour), Git: fs.GitClean},
+		{Name: "disk.iso", Size: 4 << 30, ModTime: time.Now().Add(-10 * 365 * 24 * time.Hour), Git: fs.GitIgnored},
+		{Name: "README", Size: 0, ModTime: time.Now().Add(-2 * 365 * 24 * time.Hour)},
+	}
+
+	// The size mode is a continuous gradient; see TestSizeStep_RelativeToLargest
+	for _, mode := range []Mode{ModeAge, ModeType, ModeGit} {
+		m := Mapping{Mode: mode}
+		legend := m.Legend()
+		for _, f := range files {
+			idx := m.Bucket(f, 0)
+			if idx < 0 || idx >= len(legend) {
+				t.Fatalf("%s/%s: bucket %d out of range", mode, f.Name, idx)
+			}
+			want := legend[idx].Color
+			got := m.Color(f, 0)
+			if got != want {
+				t.Errorf("%s/%s: color %v does not match legend entry %q (%v)",
+					mode, f.Name, got, legend[idx].Label, want)
+			}
+		}
+	}
+}
+
+func TestMapping_UsesTimeField(t *testing.T) {
+	e := &fs.Entry{
+		ModTime:    time.Now(),
+		AccessTime: time.Now().Add(-10 * 365 * 24 * time.Hour),
+	}
+	if got := (Mapping{Mode: ModeAge}).Bucket(e, 0); got != 0 {
+		t.Errorf("mtime bucket: got %d, want 0", got)
+	}
+	m := Mapping{Mode: ModeAge, Time: fs.TimeAccessed}
+	if got := m.Bucket(e, 0); got != len(DefaultAgeBuckets) {
+		t.Errorf("atime bucket: got %d, want ancient (%d)", got, len(DefaultAgeBuckets))
+	}
+}
+
+func TestSizeStep_RelativeToLargest(t *testing.T) {
+	files := []*fs.Entry{
+		{Name: "empty", Size: 0},
+		{Name: "small", Size: 40},
+		{Name: "big", Size: 2 << 20},
+		{Name: "sub", Type: fs.TypeDir, Size: 8 << 30},
+	}
+	largest := LargestFile(files)
+	if largest != 2<<20 {
+		t.Fatalf("largest file: got %d, want %d (directories do not count)", largest, 2<<20)
+	}
+	m := Mapping{Mode: ModeSize}
+	steps := len(m.Legend())
+	if got := m.Bucket(files[0], largest); got != 0 {
+		t.Errorf("empty file: got step %d, want 0", got)
+	}
+	if got := m.Bucket(files[2], largest); got != steps-1 {
+		t.Errorf("largest file: got step %d, want %d", got, steps-1)
+	}
+	if got := m.Color(files[2], largest); got != ColorFromSize(largest, largest) {
+		t.Errorf("largest file: color %v, want the top of the gradient", got)
+	}
+	// The same file is colored warmer beside smaller files
+	if a, b := m.Bucket(files[1], largest), m.Bucket(files[1], 50); a >= b {
+		t.Errorf("40 bytes: step %d beside 2 MB, %d beside 50 bytes", a, b)
+	}
+}
+
+func TestTypeCategoryIndex_CaseInsensitive(t *testing.T) {
+	if TypeCategoryIndex("A.PNG") != TypeCategoryIndex("a.png") {
+		t.Error("extension matching should be case-insensitive")
+	}
+	if got := TypeCategoryIndex("Makefile"); got != len(DefaultTypeCategories) {
+		t.Errorf("no extension: got %d, want other (%d)", got, len(DefaultTypeCategories))
+	}
+}
+
+func TestTypeCategoryIndex_FollowsFileTypes(t *testing.T) {
+	seen := make(map[string]string)
+	for _, cat := range DefaultTypeCategories {
+		for _, c := range cat.Categories {
+			if prev, ok := seen[c]; ok {
+				t.Errorf("%q is in both %s and %s", c, prev, cat.Label)
+			}
+			seen[c] = cat.Label
+		}
+	}
+	for _, c := range fs.Categories() {
+		if _, ok := seen[c]; !ok {
+			t.Errorf("file type category %q has no color", c)
+		}
+	}
+	for name, want := range map[string]string{"logo.svg": "Image", "util.h": "Code", "go.sum": "Markup / Data"} {
+		if i := TypeCategoryIndex(name); i >= len(DefaultTypeCategories) || DefaultTypeCategories[i].Label != want {
+			t.Errorf("%s (%s): got category %d, want %s", name, fs.Category(name), i, want)
+		}
+	}
+}
+
+func TestMode_NextCycles(t *testing.T) {
+	m := ModeAge
+	for i := 0; i < modeCount; i++ {
+		m = m.Next()
+	}
+	if m != ModeAge {
+		t.Errorf("cycling %d times should return to ModeAge, got %s", modeCount, m)
+	}
+}
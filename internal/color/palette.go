@@ -132,6 +132,10 @@ var (
 	WireframeColor = Active.WireframeColor
 )
 
+// Ground is the ground plane under the scene, dark muted teal-gray in every
+// theme. The renderer and the HTML export both draw it.
+var Ground = rl.NewColor(22, 38, 36, 255)
+
 // InitTheme sets the active theme. Pass "" to auto-detect, "dark", or "light".
 func InitTheme(preference string) {
 	switch preference {
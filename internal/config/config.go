@@ -0,0 +1,288 @@
+// Package config loads user preferences from ~/.config/fsnredux/config.json.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/color"
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+	"github.com/Crank-Git/FSNRedux/internal/layout"
+	"github.com/Crank-Git/FSNRedux/internal/shading"
+)
+
+// File is the on-disk configuration. Every field is optional.
+type File struct {
+	// AgeBuckets replaces color.DefaultAgeBuckets when non-empty.
+	AgeBuckets []AgeBucket `json:"age_buckets,omitempty"`
+
+	// ReferenceTime is what ages are measured against:
+	// "now", "scan" (the time the scan finished), or a date such as "2023-06-30".
+	ReferenceTime string `json:"reference_time,omitempty"`
+
+	// Timestamp selects which time drives the age: "mtime", "ctime", "atime" or "birth".
+	Timestamp string `json:"timestamp,omitempty"`
+
+	// Lighting selects the shading pipeline: "flat", "lit" or "shadows" (default).
+	Lighting string `json:"lighting,omitempty"`
+
+	// FileHeights and FileFootprint scale file tiles on pedestals by the log
+	// of their size; by default all tiles are the same.
+	FileHeights   bool `json:"file_heights,omitempty"`
+	FileFootprint bool `json:"file_footprint,omitempty"`
+
+	// SortOrder is the starting order of children in the sidebar, TreeV and
+	// Tab cycling: "size" (default), "name", "mtime", "extension" or "type".
+	SortOrder string `json:"sort_order,omitempty"`
+
+	// FileOrder is the older name of SortOrder, from when it only arranged
+	// files on pedestals: "size", "name", "mtime" or "type" (grouped by
+	// extension). It applies when SortOrder is not set.
+	FileOrder string `json:"file_order,omitempty"`
+
+	// Layout overrides layout sizes and spacings; see Geometry.
+	Layout *Geometry `json:"layout,omitempty"`
+
+	// StableLayout keeps TreeV and MapV children where they were last seen
+	// across rescans and sessions (see layout.Order).
+	StableLayout bool `json:"stable_layout,omitempty"`
+
+	// Fisheye starts with the selected directory's subtree enlarged and
+	// the rest of the scene squeezed around it.
+	Fisheye bool `json:"fisheye,omitempty"`
+}
+
+// Geometry is the config form of layout.Geometry. Every field is optional
+// and unset ones keep their defaults.
+type Geometry struct {
+	PaddingRatio *float32 `json:"padding_ratio,omitempty"` // MapV gap between siblings, fraction of their rect
+	HeightScale  *float32 `json:"height_scale,omitempty"`
+	MinHeight    *float32 `json:"min_height,omitempty"`
+	MaxHeight    *float32 `json:"max_height,omitempty"`
+	FileSize     *float32 `json:"file_size,omitempty"`
+	FileSpacing  *float32 `json:"file_spacing,omitempty"`
+	FileHeight   *float32 `json:"file_height,omitempty"`
+	DirSize      *float32 `json:"dir_size,omitempty"`
+	DirSpacing   *float32 `json:"dir_spacing,omitempty"`
+	DirHeight    *float32 `json:"dir_height,omitempty"`
+	DirDistance  *float32 `json:"dir_distance,omitempty"`
+	WrapDirs     *int     `json:"wrap_dirs,omitempty"`
+	MinFootprint *float32 `json:"min_footprint,omitempty"`
+}
+
+// AgeBucket is the config form of color.AgeBucket.
+type AgeBucket struct {
+	MaxAge string `json:"max_age"`         // e.g. "36h", "7d", "2w", "6mo", "1y"
+	Color  string `json:"color"`           // "#rrggbb" or "#rrggbbaa"
+	Label  string `json:"label,omitempty"` // defaults to "< " + MaxAge
+}
+
+// Path returns the location of the config file.
+func Path() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "fsnredux", "config.json"), nil
+}
+
+// Load reads the config file from its default location.
+// A missing file is not an error and yields an empty File.
+func Load() (*File, error) {
+	path, err := Path()
+	if err != nil {
+		return &File{}, nil
+	}
+	return LoadFile(path)
+}
+
+// LoadFile reads and validates a config file.
+// A missing file is not an error and yields an empty File.
+func LoadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &File{}, nil
+	}
+	if err != nil {
+		return &File{}, err
+	}
+
+	var f File
+	if err := json.Unmarshal(data, &f); err != nil {
+		return &File{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if _, err := f.Buckets(); err != nil {
+		return &File{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if _, err := f.Reference(); err != nil {
+		return &File{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if _, ok := fs.ParseTimeField(f.Timestamp); !ok {
+		return &File{}, fmt.Errorf("%s: unknown timestamp %q", path, f.Timestamp)
+	}
+	if _, ok := shading.Parse(f.Lighting); !ok {
+		return &File{}, fmt.Errorf("%s: unknown lighting %q", path, f.Lighting)
+	}
+	if _, ok := fs.ParseSortMode(f.SortOrder); !ok {
+		return &File{}, fmt.Errorf("%s: unknown sort order %q", path, f.SortOrder)
+	}
+	if _, ok := parseFileOrder(f.FileOrder); !ok {
+		return &File{}, fmt.Errorf("%s: unknown file order %q", path, f.FileOrder)
+	}
+	if _, err := f.Geometry(); err != nil {
+		return &File{}, fmt.Errorf("%s: layout: %w", path, err)
+	}
+	return &f, nil
+}
+
+// Buckets converts the configured age buckets, sorted by MaxAge ascending.
+// Returns nil (use the defaults) when none are configured.
+func (f *File) Buckets() ([]color.AgeBucket, error) {
+	if len(f.AgeBuckets) == 0 {
+		return nil, nil
+	}
+	buckets := make([]color.AgeBucket, 0, len(f.AgeBuckets))
+	for _, b := range f.AgeBuckets {
+		maxAge, err := ParseAge(b.MaxAge)
+		if err != nil {
+			return nil, err
+		}
+		c, err := ParseColor(b.Color)
+		if err != nil {
+			return nil, err
+		}
+		label := b.Label
+		if label == "" {
+			label = "< " + b.MaxAge
+		}
+		buckets = append(buckets, color.AgeBucket{MaxAge: maxAge, Color: c, Label: label})
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i].MaxAge < buckets[j].MaxAge
+	})
+	return buckets, nil
+}
+
+// Reference parses the configured reference time (default "now").
+func (f *File) Reference() (Reference, error) {
+	return ParseReference(f.ReferenceTime)
+}
+
+// TimeField returns the configured timestamp field (default mtime).
+func (f *File) TimeField() fs.TimeField {
+	field, _ := fs.ParseTimeField(f.Timestamp)
+	return field
+}
+
+// Shading returns the configured lighting pipeline (default shadows).
+func (f *File) Shading() shading.Mode {
+	m, _ := shading.Parse(f.Lighting)
+	return m
+}
+
+// Sort returns the configured order of children (default size), from
+// sort_order or else the older file_order.
+func (f *File) Sort() fs.SortMode {
+	if f.SortOrder == "" {
+		mode, _ := parseFileOrder(f.FileOrder)
+		return mode
+	}
+	mode, _ := fs.ParseSortMode(f.SortOrder)
+	return mode
+}
+
+// parseFileOrder converts a file_order value to a sort mode. Its "type"
+// grouped files by extension, which sort_order calls "extension".
+func parseFileOrder(name string) (fs.SortMode, bool) {
+	if strings.ToLower(name) == "type" {
+		return fs.SortExtension, true
+	}
+	return fs.ParseSortMode(name)
+}
+
+// Geometry returns the layout geometry with the configured overrides.
+func (f *File) Geometry() (layout.Geometry, error) {
+	g := layout.DefaultGeometry()
+	if c := f.Layout; c != nil {
+		for _, o := range []struct {
+			from *float32
+			to   *float32
+		}{
+			{c.PaddingRatio, &g.PaddingRatio},
+			{c.HeightScale, &g.HeightScale},
+			{c.MinHeight, &g.MinHeight},
+			{c.MaxHeight, &g.MaxHeight},
+			{c.FileSize, &g.FileSize},
+			{c.FileSpacing, &g.FileSpacing},
+			{c.FileHeight, &g.FileHeight},
+			{c.DirSize, &g.DirSize},
+			{c.DirSpacing, &g.DirSpacing},
+			{c.DirHeight, &g.DirHeight},
+			{c.DirDistance, &g.DirDist},
+			{c.MinFootprint, &g.MinFootprint},
+		} {
+			if o.from != nil {
+				*o.to = *o.from
+			}
+		}
+		if c.WrapDirs != nil {
+			g.WrapDirs = *c.WrapDirs
+		}
+	}
+	return g, g.Validate()
+}
+
+// ageUnits maps the day-or-longer suffixes ParseAge accepts to their length.
+var ageUnits = []struct {
+	suffix string
+	unit   time.Duration
+}{
+	{"mo", 30 * 24 * time.Hour},
+	{"d", 24 * time.Hour},
+	{"w", 7 * 24 * time.Hour},
+	{"y", 365 * 24 * time.Hour},
+}
+
+// ParseAge parses a duration, accepting the time.ParseDuration units plus
+// d (day), w (week), mo (30 days) and y (365 days), e.g. "36h", "2w", "1.5y".
+func ParseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	for _, u := range ageUnits {
+		if num, ok := strings.CutSuffix(s, u.suffix); ok {
+			n, err := strconv.ParseFloat(num, 64)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid age %q", s)
+			}
+			return time.Duration(n * float64(u.unit)), nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid age %q", s)
+	}
+	return d, nil
+}
+
+// ParseColor parses "#rrggbb" or "#rrggbbaa".
+func ParseColor(s string) (rl.Color, error) {
+	hex := strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(hex) != 6 && len(hex) != 8 {
+		return rl.Color{}, fmt.Errorf("invalid color %q", s)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return rl.Color{}, fmt.Errorf("invalid color %q", s)
+	}
+	if len(hex) == 6 {
+		v = v<<8 | 0xff
+	}
+	return rl.NewColor(uint8(v>>24), uint8(v>>16), uint8(v>>8), uint8(v)), nil
+}
@@ -0,0 +1,238 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+	"github.com/Crank-Git/FSNRedux/internal/layout"
+	"github.com/Crank-Git/FSNRedux/internal/shading"
+)
+
+func TestParseAge_Units(t *testing.T) {
+	cases := map[string]time.Duration{
+		"36h":  36 * time.Hour,
+		"7d":   7 * 24 * time.Hour,
+		"2w":   14 * 24 * time.Hour,
+		"6mo":  180 * 24 * time.Hour,
+		"1y":   365 * 24 * time.Hour,
+		"1.5y": time.Duration(1.5 * float64(365*24*time.Hour)),
+	}
+	for in, want := range cases {
+		got, err := ParseAge(in)
+		if err != nil {
+			t.Errorf("ParseAge(%q) failed: %v", in, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ParseAge(%q): got %v, want %v", in, got, want)
+		}
+	}
+
+	for _, bad := range []string{"", "abc", "-3d", "0y"} {
+		if _, err := ParseAge(bad); err == nil {
+			t.Errorf("ParseAge(%q): expected error", bad)
+		}
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	c, err := ParseColor("#ff8000")
+	if err != nil {
+		t.Fatalf("ParseColor failed: %v", err)
+	}
+	if c.R != 255 || c.G != 128 || c.B != 0 || c.A != 255 {
+		t.Errorf("got %v, want {255 128 0 255}", c)
+	}
+
+	c, err = ParseColor("#10203040")
+	if err != nil {
+		t.Fatalf("ParseColor failed: %v", err)
+	}
+	if c.A != 0x40 {
+		t.Errorf("alpha: got %d, want 64", c.A)
+	}
+
+	if _, err := ParseColor("red"); err == nil {
+		t.Error("expected error for named color")
+	}
+}
+
+func TestParseReference(t *testing.T) {
+	r, err := ParseReference("")
+	if err != nil || r.Kind != RefNow {
+		t.Errorf("empty reference: got %v, %v", r, err)
+	}
+	r, err = ParseReference("scan")
+	if err != nil || r.Kind != RefScan {
+		t.Errorf("scan reference: got %v, %v", r, err)
+	}
+	r, err = ParseReference("2021-03-04")
+	if err != nil || r.Kind != RefDate {
+		t.Fatalf("date reference: got %v, %v", r, err)
+	}
+	if r.String() != "2021-03-04" {
+		t.Errorf("date round-trip: got %s", r.String())
+	}
+	r, err = ParseReference("2021-03-04 17:30")
+	if err != nil || r.Kind != RefDate {
+		t.Fatalf("date and time reference: got %v, %v", r, err)
+	}
+	if back, err := ParseReference(r.String()); err != nil || !back.Date.Equal(r.Date) {
+		t.Errorf("date and time round-trip: %s parsed back as %v, %v", r, back.Date, err)
+	}
+
+	scanned := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	if got := (Reference{Kind: RefScan}).Resolve(scanned); !got.Equal(scanned) {
+		t.Errorf("scan resolve: got %v, want %v", got, scanned)
+	}
+	if got := (Reference{Kind: RefNow}).Resolve(scanned); !got.IsZero() {
+		t.Errorf("now resolve: got %v, want zero", got)
+	}
+
+	if _, err := ParseReference("yesterday-ish"); err == nil {
+		t.Error("expected error for unparseable reference")
+	}
+}
+
+func TestLoadFile_Missing(t *testing.T) {
+	f, err := LoadFile(filepath.Join(t.TempDir(), "nope.json"))
+	if err != nil {
+		t.Fatalf("missing file should not be an error: %v", err)
+	}
+	if b, _ := f.Buckets(); b != nil {
+		t.Error("missing file should use default buckets")
+	}
+}
+
+func TestLoadFile_Buckets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{
+		"age_buckets": [
+			{"max_age": "1y", "color": "#0000ff"},
+			{"max_age": "30d", "color": "#00ff00", "label": "this month"}
+		],
+		"reference_time": "scan",
+		"timestamp": "ctime",
+		"lighting": "flat",
+		"file_heights": true,
+		"sort_order": "name",
+		"layout": {"max_height": 40, "dir_distance": 8, "padding_ratio": 0, "wrap_dirs": 4}
+	}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+	buckets, err := f.Buckets()
+	if err != nil {
+		t.Fatalf("Buckets failed: %v", err)
+	}
+	if len(buckets) != 2 {
+		t.Fatalf("expected 2 buckets, got %d", len(buckets))
+	}
+	// Sorted ascending regardless of file order
+	if buckets[0].Label != "this month" || buckets[1].Label != "< 1y" {
+		t.Errorf("unexpected bucket order/labels: %q, %q", buckets[0].Label, buckets[1].Label)
+	}
+	if ref, _ := f.Reference(); ref.Kind != RefScan {
+		t.Errorf("reference: got %v, want scan", ref)
+	}
+	if f.TimeField() != fs.TimeChanged {
+		t.Errorf("timestamp: got %s, want ctime", f.TimeField())
+	}
+	if f.Shading() != shading.Flat {
+		t.Errorf("lighting: got %s, want flat", f.Shading())
+	}
+	if !f.FileHeights || f.FileFootprint {
+		t.Errorf("file tiles: heights %v, footprint %v; want only heights", f.FileHeights, f.FileFootprint)
+	}
+	if f.Sort() != fs.SortName {
+		t.Errorf("sort order: got %s, want name", f.Sort())
+	}
+	want := layout.DefaultGeometry()
+	want.MaxHeight, want.DirDist, want.PaddingRatio, want.WrapDirs = 40, 8, 0, 4
+	if g, err := f.Geometry(); err != nil || g != want {
+		t.Errorf("layout: got %+v (%v), want %+v", g, err, want)
+	}
+}
+
+func TestLoadFile_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(path, []byte(`{"age_buckets": [{"max_age": "soon", "color": "#fff"}]}`), 0644)
+	if _, err := LoadFile(path); err == nil {
+		t.Error("expected error for invalid bucket")
+	}
+
+	os.WriteFile(path, []byte(`{"lighting": "raytraced"}`), 0644)
+	if _, err := LoadFile(path); err == nil {
+		t.Error("expected error for unknown lighting")
+	}
+
+	os.WriteFile(path, []byte(`{"sort_order": "random"}`), 0644)
+	if _, err := LoadFile(path); err == nil {
+		t.Error("expected error for unknown sort order")
+	}
+
+	os.WriteFile(path, []byte(`{"file_order": "random"}`), 0644)
+	if _, err := LoadFile(path); err == nil {
+		t.Error("expected error for unknown file order")
+	}
+
+	os.WriteFile(path, []byte(`{"layout": {"min_height": 5, "max_height": 2}}`), 0644)
+	if _, err := LoadFile(path); err == nil {
+		t.Error("expected error for min height above max height")
+	}
+}
+
+func TestOrder_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "order.json")
+	if order, err := LoadOrder(path); err != nil || len(order) != 0 {
+		t.Fatalf("missing order file: got %v (%v), want empty", order, err)
+	}
+
+	want := layout.Order{"TreeV": {
+		"/home":     {Names: []string{"src", "docs", "a.txt"}, Seen: 2},
+		"/home/src": {Names: []string{"main.go"}, Seen: 1},
+	}}
+	if err := SaveOrder(path, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadOrder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got["TreeV"]) != 2 || fmt.Sprint(got.Names(layout.ModeTreeV, "/home")) != "[src docs a.txt]" {
+		t.Errorf("loaded %v, want %v", got, want)
+	}
+}
+
+func TestLoadFile_FileOrderAlias(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	for _, tc := range []struct {
+		data string
+		want fs.SortMode
+	}{
+		{`{"file_order": "mtime"}`, fs.SortMTime},
+		{`{"file_order": "type"}`, fs.SortExtension},
+		{`{"file_order": "name", "sort_order": "size"}`, fs.SortSize},
+		{`{}`, fs.SortSize},
+	} {
+		if err := os.WriteFile(path, []byte(tc.data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		f, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.data, err)
+		}
+		if got := f.Sort(); got != tc.want {
+			t.Errorf("%s: sort %s, want %s", tc.data, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Crank-Git/FSNRedux/internal/layout"
+)
+
+// OrderPath returns where stable layouts remember their order between
+// sessions (~/.cache/fsnredux/order.json on Linux).
+func OrderPath() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "fsnredux", "order.json"), nil
+}
+
+// LoadOrder reads a saved layout order.
+// A missing file is not an error and yields an empty Order.
+func LoadOrder(path string) (layout.Order, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return layout.Order{}, nil
+	}
+	if err != nil {
+		return layout.Order{}, err
+	}
+	var order layout.Order
+	if err := json.Unmarshal(data, &order); err != nil {
+		return layout.Order{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if order == nil {
+		order = layout.Order{}
+	}
+	return order, nil
+}
+
+// maxOrderDirs caps how many directories a saved order remembers.
+const maxOrderDirs = 10000
+
+// SaveOrder writes a layout order to path, creating its directory. Only the
+// maxOrderDirs most recently laid out directories are kept.
+func SaveOrder(path string, order layout.Order) error {
+	order.Prune(maxOrderDirs)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(order)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ReferenceKind selects what file ages are measured against.
+type ReferenceKind uint8
+
+const (
+	RefNow  ReferenceKind = iota // the current wall-clock time
+	RefScan                      // the time the scan finished
+	RefDate                      // a fixed, user-chosen date
+)
+
+// Reference is a resolved reference-time choice.
+type Reference struct {
+	Kind ReferenceKind
+	Date time.Time // only used by RefDate
+}
+
+// referenceLayouts are the date formats accepted for a fixed reference.
+var referenceLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ParseReference parses "now", "scan", or a date/time.
+func ParseReference(s string) (Reference, error) {
+	s = strings.TrimSpace(s)
+	switch strings.ToLower(s) {
+	case "", "now":
+		return Reference{Kind: RefNow}, nil
+	case "scan":
+		return Reference{Kind: RefScan}, nil
+	}
+	for _, layout := range referenceLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return Reference{Kind: RefDate, Date: t}, nil
+		}
+	}
+	return Reference{}, fmt.Errorf("invalid reference time %q", s)
+}
+
+// String returns the reference in the same form ParseReference accepts: a
+// date alone at midnight, RFC 3339 otherwise so the time of day survives.
+func (r Reference) String() string {
+	switch r.Kind {
+	case RefScan:
+		return "scan"
+	case RefDate:
+		if r.Date.Equal(startOfDay(r.Date)) {
+			return r.Date.Format("2006-01-02")
+		}
+		return r.Date.Format(time.RFC3339)
+	default:
+		return "now"
+	}
+}
+
+// Resolve returns the reference instant, or the zero time for "now"
+// (which color.AgeScale treats as time.Now()).
+func (r Reference) Resolve(scannedAt time.Time) time.Time {
+	switch r.Kind {
+	case RefScan:
+		return scannedAt
+	case RefDate:
+		return r.Date
+	default:
+		return time.Time{}
+	}
+}
+
+// startOfDay returns midnight at the start of t's day, in t's location.
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
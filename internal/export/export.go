@@ -0,0 +1,146 @@
+// Package export writes the 3D scene to interchange formats (glTF 2.0 and
+// Wavefront OBJ) for use in other tools, and to a standalone HTML viewer.
+package export
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/scene"
+)
+
+// Format is a scene file format.
+type Format uint8
+
+const (
+	FormatGLTF Format = iota // glTF 2.0 JSON with an embedded buffer
+	FormatOBJ                // Wavefront OBJ plus an .mtl material library
+	FormatHTML               // self-contained WebGL viewer page
+)
+
+// FormatFor picks the format from a file extension (.gltf, .obj or .html).
+func FormatFor(path string) (Format, bool) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".gltf":
+		return FormatGLTF, true
+	case ".obj":
+		return FormatOBJ, true
+	case ".html", ".htm":
+		return FormatHTML, true
+	default:
+		return FormatGLTF, false
+	}
+}
+
+// Nodes returns the nodes of the graph that are shown on screen, in
+// traversal order: visible nodes under expanded directories with a solid
+// box. Build the graph with every directory expanded to export the full tree.
+func Nodes(graph *scene.Graph) []*scene.SceneNode {
+	var nodes []*scene.SceneNode
+	graph.Traverse(func(node *scene.SceneNode) bool {
+		if !scene.Pickable(node) {
+			return false
+		}
+		if node.Size.X > 0 && node.Size.Y > 0 && node.Size.Z > 0 && node.Opacity() > 0 {
+			nodes = append(nodes, node)
+		}
+		return true
+	})
+	return nodes
+}
+
+// File writes the exported nodes of graph to path, in the format given by
+// its extension. OBJ output also writes a material library next to it.
+func File(path string, graph *scene.Graph) error {
+	format, ok := FormatFor(path)
+	if !ok {
+		return fmt.Errorf("unknown export format %q (want .gltf, .obj or .html)", filepath.Ext(path))
+	}
+	nodes := Nodes(graph)
+	if format == FormatHTML && hasSectors(nodes) {
+		return errHTMLSunburst
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	switch format {
+	case FormatOBJ:
+		mtlPath := strings.TrimSuffix(path, filepath.Ext(path)) + ".mtl"
+		if err := writeFile(mtlPath, func(m *os.File) error { return WriteMTL(m, nodes) }); err != nil {
+			return err
+		}
+		err = WriteOBJ(f, filepath.Base(mtlPath), nodes)
+	case FormatHTML:
+		title := "scan"
+		if graph.Root != nil && graph.Root.Entry != nil {
+			title = graph.Root.Entry.Path
+		}
+		err = WriteHTML(f, title, nodes)
+	default:
+		err = WriteGLTF(f, nodes)
+	}
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func writeFile(path string, write func(*os.File) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := write(f); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// hasSectors reports whether any node is a sunburst segment.
+func hasSectors(nodes []*scene.SceneNode) bool {
+	for _, n := range nodes {
+		if n.Sector != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// materials assigns each distinct node color an index, in first-use order.
+func materials(nodes []*scene.SceneNode) ([]rl.Color, map[rl.Color]int) {
+	var colors []rl.Color
+	index := make(map[rl.Color]int)
+	for _, n := range nodes {
+		c := opaque(n.Color)
+		if _, ok := index[c]; !ok {
+			index[c] = len(colors)
+			colors = append(colors, c)
+		}
+	}
+	return colors, index
+}
+
+// opaque drops the alpha channel; exported boxes are always solid.
+func opaque(c rl.Color) rl.Color {
+	c.A = 255
+	return c
+}
+
+// nodeName is the display name of a node in the exported file.
+func nodeName(n *scene.SceneNode) string {
+	switch {
+	case n.Entry != nil:
+		return n.Entry.Name
+	case n.Group != nil:
+		return fmt.Sprintf("%d small files", len(n.Group.Members))
+	}
+	return fmt.Sprintf("node%d", n.ID)
+}
@@ -0,0 +1,300 @@
+package export
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+	"github.com/Crank-Git/FSNRedux/internal/layout"
+	"github.com/Crank-Git/FSNRedux/internal/scene"
+)
+
+func graphFor(tree *fs.Tree, expanded map[string]bool) *scene.Graph {
+	opts := layout.DefaultOptions(layout.ModeTreeV)
+	opts.ExpandedPaths = expanded
+	return scene.NewGraph(layout.Compute(tree, opts), expanded)
+}
+
+// allDirs expands every directory in the tree, as the full-tree export does.
+func allDirs(tree *fs.Tree) map[string]bool {
+	expanded := make(map[string]bool)
+	var walk func(e *fs.Entry)
+	walk = func(e *fs.Entry) {
+		if e.IsDir() {
+			expanded[e.Path] = true
+		}
+		for _, c := range e.Children {
+			walk(c)
+		}
+	}
+	walk(tree.Root)
+	return expanded
+}
+
+func TestNodes_OnlyVisibleUnlessFullTree(t *testing.T) {
+	tree := fs.SyntheticTree(200, 10, 3)
+	collapsed := Nodes(graphFor(tree, map[string]bool{tree.Root.Path: true}))
+	if len(collapsed) != len(tree.Root.Children)+1 {
+		t.Errorf("root expanded: exported %d nodes, want root plus %d children", len(collapsed), len(tree.Root.Children))
+	}
+
+	full := Nodes(graphFor(tree, allDirs(tree)))
+	entries := 0
+	var count func(e *fs.Entry)
+	count = func(e *fs.Entry) {
+		entries++
+		for _, c := range e.Children {
+			count(c)
+		}
+	}
+	count(tree.Root)
+	if len(full) != entries {
+		t.Errorf("full tree: exported %d nodes, want %d", len(full), entries)
+	}
+}
+
+func TestWriteGLTF_NodesMaterialsAndExtras(t *testing.T) {
+	tree := fs.SyntheticTree(100, 10, 3)
+	nodes := Nodes(graphFor(tree, allDirs(tree)))
+	var out bytes.Buffer
+	if err := WriteGLTF(&out, nodes); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc gltfDoc
+	if err := json.Unmarshal(out.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(doc.Nodes) != len(nodes) || len(doc.Scenes[0].Nodes) != len(nodes) {
+		t.Fatalf("%d glTF nodes for %d scene nodes", len(doc.Nodes), len(nodes))
+	}
+	colors, _ := materials(nodes)
+	if len(doc.Materials) != len(colors) || len(doc.Meshes) != len(colors) {
+		t.Errorf("%d materials and %d meshes for %d colors", len(doc.Materials), len(doc.Meshes), len(colors))
+	}
+	for i, gn := range doc.Nodes {
+		n := nodes[i]
+		if gn.Extras == nil || gn.Extras.Path != n.Entry.Path || gn.Extras.Size != n.Entry.Size {
+			t.Fatalf("node %d extras = %+v, want %s (%d bytes)", i, gn.Extras, n.Entry.Path, n.Entry.Size)
+		}
+		if gn.Scale != [3]float32{n.Size.X, n.Size.Y, n.Size.Z} {
+			t.Errorf("%s: scale %v, size %v", n.Entry.Path, gn.Scale, n.Size)
+		}
+		if c := n.Color; doc.Materials[doc.Meshes[gn.Mesh].Primitives[0].Material].Name != colorName(opaque(c)) {
+			t.Errorf("%s: wrong material for color %v", n.Entry.Path, c)
+		}
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(doc.Buffers[0].URI, "data:application/octet-stream;base64,"))
+	if err != nil || len(data) != doc.Buffers[0].ByteLength {
+		t.Fatalf("buffer: %d bytes, declared %d (%v)", len(data), doc.Buffers[0].ByteLength, err)
+	}
+	for _, v := range doc.BufferViews {
+		if v.ByteOffset+v.ByteLength > len(data) {
+			t.Errorf("buffer view %+v overruns the %d byte buffer", v, len(data))
+		}
+	}
+	var first [3]float32
+	binary.Read(bytes.NewReader(data), binary.LittleEndian, &first)
+	if first != [3]float32{cubeCorners[0].X, cubeCorners[0].Y, cubeCorners[0].Z} {
+		t.Errorf("first position %v, want %v", first, cubeCorners[0])
+	}
+}
+
+func TestCubeFaces_WindOutward(t *testing.T) {
+	for i, face := range cubeFaces {
+		c := face.corners
+		n := rl.Vector3CrossProduct(rl.Vector3Subtract(c[1], c[0]), rl.Vector3Subtract(c[2], c[0]))
+		if rl.Vector3DotProduct(n, face.normal) <= 0 {
+			t.Errorf("glTF face %d winds inward", i)
+		}
+	}
+	corner := func(c int) rl.Vector3 {
+		return rl.NewVector3(float32(c&1)-0.5, float32(c>>1&1)-0.5, float32(c>>2&1)-0.5)
+	}
+	for i, f := range objFaces {
+		a, b, c := corner(f[0]), corner(f[1]), corner(f[2])
+		n := rl.Vector3CrossProduct(rl.Vector3Subtract(b, a), rl.Vector3Subtract(c, a))
+		mid := rl.Vector3Scale(rl.Vector3Add(a, c), 0.5)
+		if rl.Vector3DotProduct(n, mid) <= 0 {
+			t.Errorf("OBJ face %d winds inward", i)
+		}
+	}
+}
+
+func TestFile_WritesOBJAndMaterials(t *testing.T) {
+	tree := fs.SyntheticTree(50, 10, 2)
+	graph := graphFor(tree, allDirs(tree))
+	path := filepath.Join(t.TempDir(), "scene.obj")
+	if err := File(path, graph); err != nil {
+		t.Fatal(err)
+	}
+
+	obj, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes := Nodes(graph)
+	text := string(obj)
+	if got := strings.Count(text, "\no "); got != len(nodes) {
+		t.Errorf("%d objects, want %d", got, len(nodes))
+	}
+	if got := strings.Count(text, "\nv "); got != 8*len(nodes) {
+		t.Errorf("%d vertices, want %d", got, 8*len(nodes))
+	}
+	if !strings.Contains(text, "mtllib scene.mtl\n") || !strings.Contains(text, "# path="+tree.Root.Path) {
+		t.Error("missing material library or entry comment")
+	}
+
+	mtl, err := os.ReadFile(filepath.Join(filepath.Dir(path), "scene.mtl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	colors, _ := materials(nodes)
+	for i := range colors {
+		if !strings.Contains(string(mtl), fmt.Sprintf("newmtl m%d\n", i)) {
+			t.Errorf("material m%d missing", i)
+		}
+	}
+
+	if err := File(filepath.Join(t.TempDir(), "scene.stl"), graph); err == nil {
+		t.Error("expected an unknown extension to be rejected")
+	}
+}
+
+func TestWriteHTML_EmbedsLayoutPositions(t *testing.T) {
+	tree := fs.SyntheticTree(100, 10, 3)
+	tree.Root.Children[0].Name = "</script><b>x</b>"
+	nodes := Nodes(graphFor(tree, allDirs(tree)))
+	var out bytes.Buffer
+	if err := WriteHTML(&out, tree.Root.Path, nodes); err != nil {
+		t.Fatal(err)
+	}
+	page := out.String()
+	if strings.Count(page, "</script>") != 1 {
+		t.Fatal("a node name closed the script element")
+	}
+
+	// The data is the first JSON object after "const DATA = "
+	start := strings.Index(page, "const DATA = ")
+	if start < 0 {
+		t.Fatal("scene data missing from page")
+	}
+	var scene htmlScene
+	if err := json.NewDecoder(strings.NewReader(page[start+len("const DATA = "):])).Decode(&scene); err != nil {
+		t.Fatalf("scene data is not JSON: %v", err)
+	}
+	if len(scene.Nodes) != len(nodes) {
+		t.Fatalf("%d nodes in page, want %d", len(scene.Nodes), len(nodes))
+	}
+	for i, hn := range scene.Nodes {
+		n := nodes[i]
+		if hn.Position != [3]float32{n.Position.X, n.Position.Y, n.Position.Z} || hn.Box != [3]float32{n.Size.X, n.Size.Y, n.Size.Z} {
+			t.Fatalf("%s: page box %v %v, layout %v %v", n.Entry.Path, hn.Position, hn.Box, n.Position, n.Size)
+		}
+		if hn.Path != n.Entry.Path || hn.Name != n.Entry.Name {
+			t.Fatalf("node %d is %q (%s), want %q (%s)", i, hn.Name, hn.Path, n.Entry.Name, n.Entry.Path)
+		}
+	}
+}
+
+func TestExport_SunburstSegments(t *testing.T) {
+	tree := fs.SyntheticTree(100, 10, 3)
+	expanded := allDirs(tree)
+	opts := layout.DefaultOptions(layout.ModeSunburst)
+	opts.ExpandedPaths = expanded
+	graph := scene.NewGraph(layout.Compute(tree, opts), expanded)
+	nodes := Nodes(graph)
+
+	// onSegment reports whether p lies on the extruded segment of n
+	const eps = 1e-3
+	onSegment := func(n *scene.SceneNode, p [3]float32) bool {
+		s := n.Sector
+		if p[1] < n.Bounds.Min.Y-eps || p[1] > n.Bounds.Max.Y+eps {
+			return false
+		}
+		dx, dz := float64(p[0]-s.CenterX), float64(p[2]-s.CenterZ)
+		r, a := math.Hypot(dx, dz), math.Atan2(dz, dx)
+		if r < float64(s.Inner)-eps || r > float64(s.Outer)+eps {
+			return false
+		}
+		return r < eps || s.InSweep(a+eps) || s.InSweep(a-eps)
+	}
+
+	var out bytes.Buffer
+	if err := WriteGLTF(&out, nodes); err != nil {
+		t.Fatal(err)
+	}
+	var doc gltfDoc
+	if err := json.Unmarshal(out.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(doc.Buffers[0].URI, "data:application/octet-stream;base64,"))
+	if err != nil || len(data) != doc.Buffers[0].ByteLength {
+		t.Fatalf("buffer: %d bytes, declared %d (%v)", len(data), doc.Buffers[0].ByteLength, err)
+	}
+	for i, gn := range doc.Nodes {
+		n := nodes[i]
+		if n.Sector == nil {
+			t.Fatalf("%s has no sector", nodeName(n))
+		}
+		prim := doc.Meshes[gn.Mesh].Primitives[0]
+		acc := doc.Accessors[prim.Attributes["POSITION"]]
+		view := doc.BufferViews[acc.BufferView]
+		if prim.Indices != nil || acc.Count <= 8 || acc.Count%3 != 0 || view.ByteOffset+view.ByteLength > len(data) {
+			t.Fatalf("%s: %d positions in view %+v", nodeName(n), acc.Count, view)
+		}
+		positions := make([][3]float32, acc.Count)
+		binary.Read(bytes.NewReader(data[view.ByteOffset:view.ByteOffset+view.ByteLength]), binary.LittleEndian, positions)
+		for _, p := range positions {
+			if !onSegment(n, p) {
+				t.Fatalf("%s: vertex %v lies off its segment %+v", nodeName(n), p, *n.Sector)
+			}
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "sunburst.obj")
+	if err := File(path, graph); err != nil {
+		t.Fatal(err)
+	}
+	obj, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	objects := strings.Split(string(obj), "\no ")[1:]
+	if len(objects) != len(nodes) {
+		t.Fatalf("%d objects, want %d", len(objects), len(nodes))
+	}
+	vertices := 0
+	for i, object := range objects {
+		for _, line := range strings.Split(object, "\n") {
+			var p [3]float32
+			if n, _ := fmt.Sscanf(line, "v %g %g %g", &p[0], &p[1], &p[2]); n != 3 {
+				continue
+			}
+			vertices++
+			if !onSegment(nodes[i], p) {
+				t.Fatalf("%s: OBJ vertex %v lies off its segment", nodeName(nodes[i]), p)
+			}
+		}
+		for _, line := range strings.Split(object, "\n") {
+			var a, b, c int
+			if n, _ := fmt.Sscanf(line, "f %d %d %d", &a, &b, &c); n == 3 && max(a, b, c) > vertices {
+				t.Fatalf("%s: face %q refers past vertex %d", nodeName(nodes[i]), line, vertices)
+			}
+		}
+	}
+
+	if err := File(filepath.Join(t.TempDir(), "sunburst.html"), graph); err == nil {
+		t.Error("expected the HTML viewer to refuse a sunburst scene")
+	}
+}
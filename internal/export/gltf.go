@@ -0,0 +1,288 @@
+package export
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/scene"
+)
+
+// glTF component and target constants.
+const (
+	gltfFloat        = 5126
+	gltfUnsignedByte = 5121
+	gltfArrayBuffer  = 34962
+	gltfElementArray = 34963
+	gltfTriangles    = 4
+)
+
+// The glTF document subset the exporter writes.
+type gltfDoc struct {
+	Asset       gltfAsset        `json:"asset"`
+	Scene       int              `json:"scene"`
+	Scenes      []gltfScene      `json:"scenes"`
+	Nodes       []gltfNode       `json:"nodes"`
+	Meshes      []gltfMesh       `json:"meshes"`
+	Materials   []gltfMaterial   `json:"materials"`
+	Accessors   []gltfAccessor   `json:"accessors"`
+	BufferViews []gltfBufferView `json:"bufferViews"`
+	Buffers     []gltfBuffer     `json:"buffers"`
+}
+
+type gltfAsset struct {
+	Version   string `json:"version"`
+	Generator string `json:"generator"`
+}
+
+type gltfScene struct {
+	Name  string `json:"name"`
+	Nodes []int  `json:"nodes"`
+}
+
+type gltfNode struct {
+	Name        string       `json:"name"`
+	Mesh        int          `json:"mesh"`
+	Translation [3]float32   `json:"translation"`
+	Scale       [3]float32   `json:"scale"`
+	Extras      *entryExtras `json:"extras,omitempty"`
+}
+
+// entryExtras carries the filesystem entry behind a node.
+type entryExtras struct {
+	Path  string `json:"path"`
+	Type  string `json:"type"`
+	Size  int64  `json:"size"`
+	MTime string `json:"mtime,omitempty"` // RFC 3339
+}
+
+type gltfMesh struct {
+	Primitives []gltfPrimitive `json:"primitives"`
+}
+
+type gltfPrimitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    *int           `json:"indices,omitempty"`
+	Material   int            `json:"material"`
+	Mode       int            `json:"mode"`
+}
+
+type gltfMaterial struct {
+	Name string  `json:"name"`
+	PBR  gltfPBR `json:"pbrMetallicRoughness"`
+}
+
+type gltfPBR struct {
+	BaseColorFactor [4]float32 `json:"baseColorFactor"`
+	MetallicFactor  float32    `json:"metallicFactor"`
+	RoughnessFactor float32    `json:"roughnessFactor"`
+}
+
+type gltfAccessor struct {
+	BufferView    int       `json:"bufferView"`
+	ComponentType int       `json:"componentType"`
+	Count         int       `json:"count"`
+	Type          string    `json:"type"`
+	Min           []float32 `json:"min,omitempty"`
+	Max           []float32 `json:"max,omitempty"`
+}
+
+type gltfBufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset"`
+	ByteLength int `json:"byteLength"`
+	Target     int `json:"target"`
+}
+
+type gltfBuffer struct {
+	ByteLength int    `json:"byteLength"`
+	URI        string `json:"uri"`
+}
+
+// WriteGLTF writes nodes as a glTF 2.0 document. Every box node instances a
+// unit cube, translated to its position and scaled to its size; there is
+// one cube mesh per distinct color, each with its own material. Sunburst
+// segments get a mesh of their own, tessellated in place. The entry path,
+// type, size and mtime are stored in the node's extras.
+func WriteGLTF(w io.Writer, nodes []*scene.SceneNode) error {
+	colors, index := materials(nodes)
+	var buf bytes.Buffer
+	buf.Write(cubeBuffer())
+	cubeIndexAccessor := 2
+
+	doc := gltfDoc{
+		Asset:  gltfAsset{Version: "2.0", Generator: "FSNRedux"},
+		Scenes: []gltfScene{{Name: "FSNRedux"}},
+		Accessors: []gltfAccessor{
+			{BufferView: 0, ComponentType: gltfFloat, Count: len(cubeCorners), Type: "VEC3",
+				Min: []float32{-0.5, -0.5, -0.5}, Max: []float32{0.5, 0.5, 0.5}},
+			{BufferView: 1, ComponentType: gltfFloat, Count: len(cubeCorners), Type: "VEC3"},
+			{BufferView: 2, ComponentType: gltfUnsignedByte, Count: len(cubeIndices), Type: "SCALAR"},
+		},
+		BufferViews: []gltfBufferView{
+			{Buffer: 0, ByteOffset: 0, ByteLength: 12 * len(cubeCorners), Target: gltfArrayBuffer},
+			{Buffer: 0, ByteOffset: 12 * len(cubeCorners), ByteLength: 12 * len(cubeCorners), Target: gltfArrayBuffer},
+			{Buffer: 0, ByteOffset: 24 * len(cubeCorners), ByteLength: len(cubeIndices), Target: gltfElementArray},
+		},
+		Nodes:     []gltfNode{},
+		Meshes:    []gltfMesh{},
+		Materials: []gltfMaterial{},
+	}
+	for i, c := range colors {
+		doc.Materials = append(doc.Materials, gltfMaterial{
+			Name: colorName(c),
+			PBR: gltfPBR{
+				BaseColorFactor: [4]float32{linear(c.R), linear(c.G), linear(c.B), 1},
+				RoughnessFactor: 0.8,
+			},
+		})
+		doc.Meshes = append(doc.Meshes, gltfMesh{Primitives: []gltfPrimitive{{
+			Attributes: map[string]int{"POSITION": 0, "NORMAL": 1},
+			Indices:    &cubeIndexAccessor,
+			Material:   i,
+			Mode:       gltfTriangles,
+		}}})
+	}
+	doc.Scenes[0].Nodes = make([]int, 0, len(nodes))
+	for i, n := range nodes {
+		node := gltfNode{
+			Name:        nodeName(n),
+			Mesh:        index[opaque(n.Color)],
+			Translation: [3]float32{n.Position.X, n.Position.Y, n.Position.Z},
+			Scale:       [3]float32{n.Size.X, n.Size.Y, n.Size.Z},
+			Extras:      extrasOf(n),
+		}
+		if n.Sector != nil {
+			node.Mesh = doc.sectorMesh(&buf, n, index[opaque(n.Color)])
+			node.Translation = [3]float32{}
+			node.Scale = [3]float32{1, 1, 1}
+		}
+		doc.Nodes = append(doc.Nodes, node)
+		doc.Scenes[0].Nodes = append(doc.Scenes[0].Nodes, i)
+	}
+	doc.Buffers = []gltfBuffer{{
+		ByteLength: buf.Len(),
+		URI:        "data:application/octet-stream;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()),
+	}}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", " ")
+	return enc.Encode(doc)
+}
+
+// sectorMesh appends the tessellated segment of a sunburst node to buf, as
+// unindexed triangles in world coordinates, and returns the index of a new
+// mesh drawing them with material.
+func (doc *gltfDoc) sectorMesh(buf *bytes.Buffer, n *scene.SceneNode, material int) int {
+	positions, normals := nodeTriangles(n)
+	inf := float32(math.Inf(1))
+	lo, hi := []float32{inf, inf, inf}, []float32{-inf, -inf, -inf}
+	for _, p := range positions {
+		for a, v := range [3]float32{p.X, p.Y, p.Z} {
+			lo[a], hi[a] = min(lo[a], v), max(hi[a], v)
+		}
+	}
+	attributes := make(map[string]int)
+	for _, attr := range []struct {
+		name string
+		vs   []rl.Vector3
+	}{{"POSITION", positions}, {"NORMAL", normals}} {
+		doc.BufferViews = append(doc.BufferViews, gltfBufferView{
+			Buffer: 0, ByteOffset: buf.Len(), ByteLength: 12 * len(attr.vs), Target: gltfArrayBuffer,
+		})
+		accessor := gltfAccessor{BufferView: len(doc.BufferViews) - 1, ComponentType: gltfFloat, Count: len(attr.vs), Type: "VEC3"}
+		if attr.name == "POSITION" {
+			accessor.Min, accessor.Max = lo, hi
+		}
+		doc.Accessors = append(doc.Accessors, accessor)
+		attributes[attr.name] = len(doc.Accessors) - 1
+		for _, v := range attr.vs {
+			binary.Write(buf, binary.LittleEndian, [3]float32{v.X, v.Y, v.Z})
+		}
+	}
+	doc.Meshes = append(doc.Meshes, gltfMesh{Primitives: []gltfPrimitive{{
+		Attributes: attributes,
+		Material:   material,
+		Mode:       gltfTriangles,
+	}}})
+	return len(doc.Meshes) - 1
+}
+
+func extrasOf(n *scene.SceneNode) *entryExtras {
+	if n.Entry == nil {
+		return nil
+	}
+	e := &entryExtras{Path: n.Entry.Path, Type: n.Entry.Type.String(), Size: n.Entry.Size}
+	if !n.Entry.ModTime.IsZero() {
+		e.MTime = n.Entry.ModTime.UTC().Format(time.RFC3339)
+	}
+	return e
+}
+
+// cubeCorners and cubeNormals are the 24 vertices of a unit cube centered on
+// the origin (four per face so each face has its own normal).
+var cubeCorners, cubeNormals = cubeVertices()
+
+// cubeIndices are two counter-clockwise triangles per face.
+var cubeIndices = func() []uint8 {
+	var idx []uint8
+	for f := uint8(0); f < 6; f++ {
+		b := f * 4
+		idx = append(idx, b, b+1, b+2, b, b+2, b+3)
+	}
+	return idx
+}()
+
+func cubeVertices() ([]rl.Vector3, []rl.Vector3) {
+	var corners, normals []rl.Vector3
+	for _, face := range cubeFaces {
+		for _, c := range face.corners {
+			corners = append(corners, c)
+			normals = append(normals, face.normal)
+		}
+	}
+	return corners, normals
+}
+
+// cubeFaces lists each face's outward normal and its corners in
+// counter-clockwise order seen from outside.
+var cubeFaces = []struct {
+	normal  rl.Vector3
+	corners [4]rl.Vector3
+}{
+	{rl.Vector3{X: 1}, [4]rl.Vector3{{X: .5, Y: -.5, Z: .5}, {X: .5, Y: -.5, Z: -.5}, {X: .5, Y: .5, Z: -.5}, {X: .5, Y: .5, Z: .5}}},
+	{rl.Vector3{X: -1}, [4]rl.Vector3{{X: -.5, Y: -.5, Z: -.5}, {X: -.5, Y: -.5, Z: .5}, {X: -.5, Y: .5, Z: .5}, {X: -.5, Y: .5, Z: -.5}}},
+	{rl.Vector3{Y: 1}, [4]rl.Vector3{{X: -.5, Y: .5, Z: .5}, {X: .5, Y: .5, Z: .5}, {X: .5, Y: .5, Z: -.5}, {X: -.5, Y: .5, Z: -.5}}},
+	{rl.Vector3{Y: -1}, [4]rl.Vector3{{X: -.5, Y: -.5, Z: -.5}, {X: .5, Y: -.5, Z: -.5}, {X: .5, Y: -.5, Z: .5}, {X: -.5, Y: -.5, Z: .5}}},
+	{rl.Vector3{Z: 1}, [4]rl.Vector3{{X: -.5, Y: -.5, Z: .5}, {X: .5, Y: -.5, Z: .5}, {X: .5, Y: .5, Z: .5}, {X: -.5, Y: .5, Z: .5}}},
+	{rl.Vector3{Z: -1}, [4]rl.Vector3{{X: .5, Y: -.5, Z: -.5}, {X: -.5, Y: -.5, Z: -.5}, {X: -.5, Y: .5, Z: -.5}, {X: .5, Y: .5, Z: -.5}}},
+}
+
+// cubeBuffer packs positions, normals and indices little-endian, as glTF requires.
+func cubeBuffer() []byte {
+	var b bytes.Buffer
+	for _, vs := range [][]rl.Vector3{cubeCorners, cubeNormals} {
+		for _, v := range vs {
+			binary.Write(&b, binary.LittleEndian, [3]float32{v.X, v.Y, v.Z})
+		}
+	}
+	b.Write(cubeIndices)
+	for b.Len()%4 != 0 {
+		b.WriteByte(0)
+	}
+	return b.Bytes()
+}
+
+// linear converts an sRGB channel to the linear value glTF colors use.
+func linear(c uint8) float32 {
+	v := float64(c) / 255
+	if v <= 0.04045 {
+		return float32(v / 12.92)
+	}
+	return float32(math.Pow((v+0.055)/1.055, 2.4))
+}
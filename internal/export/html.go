@@ -0,0 +1,93 @@
+package export
+
+import (
+	_ "embed"
+	"errors"
+	"html/template"
+	"io"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/color"
+	"github.com/Crank-Git/FSNRedux/internal/scene"
+)
+
+//go:embed viewer.html
+var viewerHTML string
+
+// errHTMLSunburst is returned for sunburst scenes: the viewer draws boxes
+// only, and a segment's bounding box is not its shape.
+var errHTMLSunburst = errors.New("the HTML viewer cannot show sunburst segments; export to .gltf or .obj instead")
+
+// viewerTemplate renders the viewer page; the scene is embedded as JSON.
+var viewerTemplate = template.Must(template.New("viewer").Parse(viewerHTML))
+
+// htmlScene is the data embedded in the viewer page.
+type htmlScene struct {
+	Title      string     `json:"title"`
+	Background [3]uint8   `json:"background"`
+	Text       [3]uint8   `json:"text"`
+	Panel      [3]uint8   `json:"panel"`
+	Border     [3]uint8   `json:"border"`
+	Ground     [3]uint8   `json:"ground"`
+	Nodes      []htmlNode `json:"nodes"`
+}
+
+// htmlNode is one box of the scene with the entry details the viewer shows.
+type htmlNode struct {
+	Name     string     `json:"n"`
+	Path     string     `json:"path"`
+	Type     string     `json:"t"`
+	Size     int64      `json:"z"`
+	ModTime  int64      `json:"m,omitempty"` // unix seconds
+	Items    int        `json:"k,omitempty"` // directory children
+	Position [3]float32 `json:"p"`
+	Box      [3]float32 `json:"s"`
+	Color    [3]uint8   `json:"c"`
+}
+
+// WriteHTML writes a single self-contained HTML page with the nodes and a
+// WebGL viewer: orbit camera, hover tooltips and click selection with an
+// info panel. Positions are taken from the scene as laid out, so the page
+// shows exactly what the desktop app does. The page uses the active theme.
+// Sunburst scenes are refused.
+func WriteHTML(w io.Writer, title string, nodes []*scene.SceneNode) error {
+	if hasSectors(nodes) {
+		return errHTMLSunburst
+	}
+	page := htmlScene{
+		Title:      title,
+		Background: rgb(color.Background),
+		Text:       rgb(color.TextPrimary),
+		Panel:      rgb(color.SidebarBg),
+		Border:     rgb(color.BorderColor),
+		Ground:     rgb(color.Ground),
+		Nodes:      make([]htmlNode, 0, len(nodes)),
+	}
+	for _, n := range nodes {
+		hn := htmlNode{
+			Name:     nodeName(n),
+			Position: [3]float32{n.Position.X, n.Position.Y, n.Position.Z},
+			Box:      [3]float32{n.Size.X, n.Size.Y, n.Size.Z},
+			Color:    rgb(n.Color),
+		}
+		if e := n.Entry; e != nil {
+			hn.Path = e.Path
+			hn.Type = e.Type.String()
+			hn.Size = e.Size
+			hn.Items = len(e.Children)
+			if !e.ModTime.IsZero() {
+				hn.ModTime = e.ModTime.Unix()
+			}
+		} else if g := n.Group; g != nil {
+			hn.Path = g.Dir.Path
+			hn.Size = g.Size
+			hn.Items = len(g.Members)
+		}
+		page.Nodes = append(page.Nodes, hn)
+	}
+	return viewerTemplate.Execute(w, page)
+}
+
+func rgb(c rl.Color) [3]uint8 {
+	return [3]uint8{c.R, c.G, c.B}
+}
@@ -0,0 +1,96 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/scene"
+)
+
+// WriteOBJ writes nodes as Wavefront OBJ: one object per node with its own
+// eight corners and six quads (or, for a sunburst segment, its tessellated
+// triangles), using the materials written by WriteMTL to mtlName. Entry details go in a comment above each object, since OBJ has
+// no place for metadata.
+func WriteOBJ(w io.Writer, mtlName string, nodes []*scene.SceneNode) error {
+	_, index := materials(nodes)
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# FSNRedux scene, %d nodes\nmtllib %s\n", len(nodes), mtlName)
+	base := 1 // OBJ vertex numbers start at 1
+	for _, n := range nodes {
+		if n.Entry != nil {
+			fmt.Fprintf(bw, "# path=%s type=%s size=%d", n.Entry.Path, n.Entry.Type, n.Entry.Size)
+			if !n.Entry.ModTime.IsZero() {
+				fmt.Fprintf(bw, " mtime=%s", n.Entry.ModTime.UTC().Format(time.RFC3339))
+			}
+			bw.WriteByte('\n')
+		}
+		fmt.Fprintf(bw, "o %s\nusemtl m%d\n", objName(nodeName(n)), index[opaque(n.Color)])
+		if n.Sector != nil {
+			positions, _ := nodeTriangles(n)
+			for _, p := range positions {
+				fmt.Fprintf(bw, "v %g %g %g\n", p.X, p.Y, p.Z)
+			}
+			for v := 0; v < len(positions); v += 3 {
+				fmt.Fprintf(bw, "f %d %d %d\n", base+v, base+v+1, base+v+2)
+			}
+			base += len(positions)
+			continue
+		}
+		min := rl.Vector3Subtract(n.Position, rl.Vector3Scale(n.Size, 0.5))
+		max := rl.Vector3Add(n.Position, rl.Vector3Scale(n.Size, 0.5))
+		for c := 0; c < 8; c++ {
+			x, y, z := min.X, min.Y, min.Z
+			if c&1 != 0 {
+				x = max.X
+			}
+			if c&2 != 0 {
+				y = max.Y
+			}
+			if c&4 != 0 {
+				z = max.Z
+			}
+			fmt.Fprintf(bw, "v %g %g %g\n", x, y, z)
+		}
+		// Corner c has bits (x, y, z); faces wind counter-clockwise from outside
+		for _, f := range objFaces {
+			fmt.Fprintf(bw, "f %d %d %d %d\n", base+f[0], base+f[1], base+f[2], base+f[3])
+		}
+		base += 8
+	}
+	return bw.Flush()
+}
+
+// objFaces are the corner indices of each cube face.
+var objFaces = [6][4]int{
+	{1, 3, 7, 5}, // +X
+	{0, 4, 6, 2}, // -X
+	{2, 6, 7, 3}, // +Y
+	{0, 1, 5, 4}, // -Y
+	{4, 5, 7, 6}, // +Z
+	{0, 2, 3, 1}, // -Z
+}
+
+// WriteMTL writes the material library for WriteOBJ: one diffuse material
+// per distinct node color.
+func WriteMTL(w io.Writer, nodes []*scene.SceneNode) error {
+	colors, _ := materials(nodes)
+	bw := bufio.NewWriter(w)
+	for i, c := range colors {
+		fmt.Fprintf(bw, "newmtl m%d\n# %s\nKd %.4f %.4f %.4f\nd 1\n\n", i, colorName(c), linear(c.R), linear(c.G), linear(c.B))
+	}
+	return bw.Flush()
+}
+
+// objName makes a name safe for an OBJ statement (no whitespace).
+func objName(name string) string {
+	return strings.Join(strings.Fields(name), "_")
+}
+
+// colorName is a color as #rrggbb.
+func colorName(c rl.Color) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
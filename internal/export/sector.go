@@ -0,0 +1,37 @@
+package export
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/layout"
+	"github.com/Crank-Git/FSNRedux/internal/scene"
+)
+
+// sectorTriangles tessellates a sunburst segment into a closed solid, in
+// world coordinates: three positions per triangle, wound counter-clockwise
+// seen from outside, each with the normal of its face. Degenerate triangles
+// (at the axis of a segment without an inner radius) are dropped.
+func sectorTriangles(s *layout.Sector, y0, y1 float32) (positions, normals []rl.Vector3) {
+	triangle := func(a, b, c, normal rl.Vector3) {
+		facing := rl.Vector3DotProduct(rl.Vector3CrossProduct(rl.Vector3Subtract(b, a), rl.Vector3Subtract(c, a)), normal)
+		if facing == 0 {
+			return
+		}
+		if facing < 0 {
+			b, c = c, b
+		}
+		positions = append(positions, a, b, c)
+		normals = append(normals, normal, normal, normal)
+	}
+	s.Faces(y0, y1, true, func(a, b, c, d, normal rl.Vector3) {
+		triangle(a, b, c, normal)
+		triangle(a, c, d, normal)
+	})
+	return positions, normals
+}
+
+// nodeTriangles tessellates a sunburst node's segment, extruded over the
+// height of its box.
+func nodeTriangles(n *scene.SceneNode) (positions, normals []rl.Vector3) {
+	y0 := n.Position.Y - n.Size.Y/2
+	return sectorTriangles(n.Sector, y0, y0+n.Size.Y)
+}
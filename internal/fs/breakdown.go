@@ -0,0 +1,116 @@
+package fs
+
+import (
+	"sort"
+	"strings"
+)
+
+// BreakdownRow aggregates the files sharing one extension or category.
+type BreakdownRow struct {
+	Key   string // extension (".go", "" for none) or category name
+	Bytes int64
+	Files int
+}
+
+// Breakdown groups the files below a directory by extension and by category.
+type Breakdown struct {
+	Root        string         // path of the directory that was aggregated
+	ByExtension []BreakdownRow // sorted by Bytes descending
+	ByCategory  []BreakdownRow // sorted by Bytes descending
+	TotalBytes  int64
+	TotalFiles  int
+}
+
+// BreakdownSort selects the column breakdown rows are ordered by.
+type BreakdownSort uint8
+
+const (
+	SortByBytes BreakdownSort = iota
+	SortByFiles
+	SortByKey
+)
+
+// ComputeBreakdown aggregates every loaded file at or below root.
+// Symlinks and special files are counted; directories are not.
+func ComputeBreakdown(root *Entry) *Breakdown {
+	b := &Breakdown{}
+	if root == nil {
+		return b
+	}
+	b.Root = root.Path
+
+	byExt := make(map[string]*BreakdownRow)
+	byCat := make(map[string]*BreakdownRow)
+	var walk func(e *Entry)
+	walk = func(e *Entry) {
+		if e.IsDir() {
+			for _, child := range e.Children {
+				walk(child)
+			}
+			return
+		}
+		b.TotalBytes += e.Size
+		b.TotalFiles++
+		addToRow(byExt, Extension(e.Name), e.Size)
+		addToRow(byCat, Category(e.Name), e.Size)
+	}
+	walk(root)
+
+	b.ByExtension = collectRows(byExt)
+	b.ByCategory = collectRows(byCat)
+	return b
+}
+
+func addToRow(rows map[string]*BreakdownRow, key string, size int64) {
+	row := rows[key]
+	if row == nil {
+		row = &BreakdownRow{Key: key}
+		rows[key] = row
+	}
+	row.Bytes += size
+	row.Files++
+}
+
+func collectRows(rows map[string]*BreakdownRow) []BreakdownRow {
+	out := make([]BreakdownRow, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, *row)
+	}
+	SortBreakdown(out, SortByBytes, true)
+	return out
+}
+
+// SortBreakdown orders rows by the given column. Ties fall back to Key
+// ascending so the order is stable across calls.
+func SortBreakdown(rows []BreakdownRow, by BreakdownSort, desc bool) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		var cmp int
+		switch by {
+		case SortByFiles:
+			cmp = compareInt64(int64(a.Files), int64(b.Files))
+		case SortByKey:
+			cmp = strings.Compare(a.Key, b.Key)
+		default:
+			cmp = compareInt64(a.Bytes, b.Bytes)
+		}
+		if cmp == 0 {
+			return a.Key < b.Key
+		}
+		if desc {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+}
+
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
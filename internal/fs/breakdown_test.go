@@ -0,0 +1,104 @@
+package fs
+
+import "testing"
+
+func breakdownTree() *Entry {
+	return &Entry{
+		Name: "root",
+		Path: "/root",
+		Type: TypeDir,
+		Children: []*Entry{
+			{Name: "main.go", Type: TypeFile, Size: 300},
+			{Name: "util.GO", Type: TypeFile, Size: 100},
+			{Name: "logo.png", Type: TypeFile, Size: 1000},
+			{Name: "Makefile", Type: TypeFile, Size: 50},
+			{Name: "src", Type: TypeDir, Children: []*Entry{
+				{Name: "lib.py", Type: TypeFile, Size: 200},
+				{Name: "photo.jpg", Type: TypeFile, Size: 500},
+			}},
+		},
+	}
+}
+
+func findRow(rows []BreakdownRow, key string) *BreakdownRow {
+	for i := range rows {
+		if rows[i].Key == key {
+			return &rows[i]
+		}
+	}
+	return nil
+}
+
+func TestComputeBreakdown_ByExtension(t *testing.T) {
+	b := ComputeBreakdown(breakdownTree())
+
+	if b.TotalFiles != 6 || b.TotalBytes != 2150 {
+		t.Errorf("totals: got %d files / %d bytes, want 6 / 2150", b.TotalFiles, b.TotalBytes)
+	}
+
+	goRow := findRow(b.ByExtension, ".go")
+	if goRow == nil || goRow.Files != 2 || goRow.Bytes != 400 {
+		t.Errorf(".go row (case-insensitive): got %+v", goRow)
+	}
+	if none := findRow(b.ByExtension, ""); none == nil || none.Files != 1 {
+		t.Errorf("extensionless row: got %+v", none)
+	}
+	if b.ByExtension[0].Key != ".png" {
+		t.Errorf("largest extension first: got %s", b.ByExtension[0].Key)
+	}
+}
+
+func TestComputeBreakdown_ByCategory(t *testing.T) {
+	b := ComputeBreakdown(breakdownTree())
+
+	code := findRow(b.ByCategory, "Source Code")
+	if code == nil || code.Files != 3 || code.Bytes != 600 {
+		t.Errorf("Source Code: got %+v", code)
+	}
+	img := findRow(b.ByCategory, "Image")
+	if img == nil || img.Files != 2 || img.Bytes != 1500 {
+		t.Errorf("Image: got %+v", img)
+	}
+	if other := findRow(b.ByCategory, OtherCategory); other == nil || other.Files != 1 {
+		t.Errorf("other category: got %+v", other)
+	}
+
+	var sum int64
+	for _, row := range b.ByCategory {
+		sum += row.Bytes
+	}
+	if sum != b.TotalBytes {
+		t.Errorf("category bytes sum %d != total %d", sum, b.TotalBytes)
+	}
+}
+
+func TestSortBreakdown(t *testing.T) {
+	rows := []BreakdownRow{
+		{Key: ".b", Bytes: 10, Files: 5},
+		{Key: ".a", Bytes: 30, Files: 1},
+		{Key: ".c", Bytes: 20, Files: 5},
+	}
+
+	SortBreakdown(rows, SortByFiles, true)
+	// Ties on Files break by Key ascending
+	if rows[0].Key != ".b" || rows[1].Key != ".c" || rows[2].Key != ".a" {
+		t.Errorf("by files desc: got %v", rows)
+	}
+
+	SortBreakdown(rows, SortByKey, false)
+	if rows[0].Key != ".a" || rows[2].Key != ".c" {
+		t.Errorf("by key asc: got %v", rows)
+	}
+
+	SortBreakdown(rows, SortByBytes, false)
+	if rows[0].Bytes != 10 || rows[2].Bytes != 30 {
+		t.Errorf("by bytes asc: got %v", rows)
+	}
+}
+
+func TestComputeBreakdown_Nil(t *testing.T) {
+	b := ComputeBreakdown(nil)
+	if b.TotalFiles != 0 || len(b.ByExtension) != 0 {
+		t.Errorf("nil root should produce an empty breakdown, got %+v", b)
+	}
+}
@@ -30,17 +30,112 @@ func (t EntryType) String() string {
 	}
 }
 
+// TimeField selects which timestamp represents an entry's age.
+type TimeField uint8
+
+const (
+	TimeModified TimeField = iota // mtime: last content modification
+	TimeChanged                   // ctime: last inode/metadata change
+	TimeAccessed                  // atime: last access
+	TimeBirth                     // creation time, where the platform records it
+)
+
+// timeFieldCount is the number of defined time fields (used for cycling).
+const timeFieldCount = 4
+
+// String returns the conventional short name for the time field.
+func (f TimeField) String() string {
+	switch f {
+	case TimeModified:
+		return "mtime"
+	case TimeChanged:
+		return "ctime"
+	case TimeAccessed:
+		return "atime"
+	case TimeBirth:
+		return "birth"
+	default:
+		return "unknown"
+	}
+}
+
+// Next returns the time field that follows f when cycling.
+func (f TimeField) Next() TimeField {
+	return (f + 1) % timeFieldCount
+}
+
+// ParseTimeField converts a name ("mtime", "ctime", "atime", "birth") to a TimeField.
+func ParseTimeField(name string) (TimeField, bool) {
+	switch name {
+	case "mtime", "modified", "":
+		return TimeModified, true
+	case "ctime", "changed":
+		return TimeChanged, true
+	case "atime", "accessed":
+		return TimeAccessed, true
+	case "birth", "btime", "created":
+		return TimeBirth, true
+	default:
+		return TimeModified, false
+	}
+}
+
+// GitStatus is an entry's state in its enclosing git repository.
+type GitStatus uint8
+
+const (
+	GitNone      GitStatus = iota // not inside a repository, or not yet checked
+	GitClean                      // tracked and unmodified
+	GitModified                   // tracked with staged or unstaged changes (dirs: contains changes)
+	GitUntracked                  // not tracked and not ignored
+	GitIgnored                    // matched by .gitignore
+)
+
+func (g GitStatus) String() string {
+	switch g {
+	case GitClean:
+		return "clean"
+	case GitModified:
+		return "modified"
+	case GitUntracked:
+		return "untracked"
+	case GitIgnored:
+		return "ignored"
+	default:
+		return "none"
+	}
+}
+
 // Entry is an immutable node in the scanned filesystem tree.
 type Entry struct {
-	Name     string
-	Path     string    // absolute path
-	Type     EntryType
-	Size     int64     // for files: file size; for dirs: recursive sum
-	ModTime  time.Time // last modification time
-	Children []*Entry  // nil for files; sorted by Size descending for layout
-	Depth    int       // distance from scan root
-	Error    string    // non-empty if this entry had a scan error
-	Loaded   bool      // true if this dir's children have been scanned
+	Name       string
+	Path       string    // absolute path
+	Type       EntryType
+	Size       int64     // for files: file size; for dirs: recursive sum
+	ModTime    time.Time // last modification time
+	ChangeTime time.Time // last metadata change (zero if unavailable)
+	AccessTime time.Time // last access (zero if unavailable)
+	BirthTime  time.Time // creation time (zero if unavailable or not captured)
+	Children   []*Entry  // nil for files; sorted by Size descending for layout
+	Depth      int       // distance from scan root
+	Error      string    // non-empty if this entry had a scan error
+	Loaded     bool      // true if this dir's children have been scanned
+	RepoRoot   bool      // true if this dir contains a .git directory or file
+	Git        GitStatus // set by the git package after scanning
+}
+
+// Time returns the timestamp selected by field (zero if it was not captured).
+func (e *Entry) Time(field TimeField) time.Time {
+	switch field {
+	case TimeChanged:
+		return e.ChangeTime
+	case TimeAccessed:
+		return e.AccessTime
+	case TimeBirth:
+		return e.BirthTime
+	default:
+		return e.ModTime
+	}
 }
 
 // IsDir returns true if this entry is a directory.
@@ -72,6 +167,23 @@ func (e *Entry) DirCount() int {
 	return count
 }
 
+// FullyLoaded reports whether every directory in this subtree has its
+// children loaded, so the subtree holds all of its files.
+func (e *Entry) FullyLoaded() bool {
+	if !e.IsDir() {
+		return true
+	}
+	if !e.Loaded {
+		return false
+	}
+	for _, child := range e.Children {
+		if !child.FullyLoaded() {
+			return false
+		}
+	}
+	return true
+}
+
 // InspectInfo holds detailed metadata gathered on-demand when the user inspects a node.
 type InspectInfo struct {
 	Name       string
@@ -81,6 +193,9 @@ type InspectInfo struct {
 	Size       int64
 	Perms      string // e.g. "-rwxr-xr-x"
 	ModTime    time.Time
+	ChangeTime time.Time
+	AccessTime time.Time
+	BirthTime  time.Time
 	IsDir      bool
 	FileCount  int
 	DirCount   int
@@ -94,10 +209,13 @@ func (e *Entry) Inspect() InspectInfo {
 		Name:    e.Name,
 		Path:    e.Path,
 		TypeStr: e.Type.String(),
-		Size:    e.Size,
-		ModTime: e.ModTime,
-		IsDir:   e.IsDir(),
-		Loaded:  e.Loaded,
+		Size:       e.Size,
+		ModTime:    e.ModTime,
+		ChangeTime: e.ChangeTime,
+		AccessTime: e.AccessTime,
+		BirthTime:  e.BirthTime,
+		IsDir:      e.IsDir(),
+		Loaded:     e.Loaded,
 	}
 
 	// Get permissions from filesystem
@@ -0,0 +1,166 @@
+package fs
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FileType is the icon label and category for a file extension.
+type FileType struct {
+	Icon     string
+	Category string
+}
+
+// fileTypeMap maps lowercase extensions to their file type.
+var fileTypeMap = map[string]FileType{
+	// Source code
+	".go":    {"Go", "Source Code"},
+	".py":    {"Py", "Source Code"},
+	".js":    {"JS", "Source Code"},
+	".ts":    {"TS", "Source Code"},
+	".tsx":   {"TSX", "Source Code"},
+	".jsx":   {"JSX", "Source Code"},
+	".rs":    {"Rs", "Source Code"},
+	".c":     {"C", "Source Code"},
+	".cpp":   {"C++", "Source Code"},
+	".cc":    {"C++", "Source Code"},
+	".h":     {"H", "Header File"},
+	".hpp":   {"H++", "Header File"},
+	".java":  {"Jv", "Source Code"},
+	".kt":    {"Kt", "Source Code"},
+	".swift": {"Sw", "Source Code"},
+	".rb":    {"Rb", "Source Code"},
+	".php":   {"PHP", "Source Code"},
+	".cs":    {"C#", "Source Code"},
+	".lua":   {"Lua", "Source Code"},
+	".zig":   {"Zig", "Source Code"},
+	".dart":  {"Drt", "Source Code"},
+	".scala": {"Scl", "Source Code"},
+	".ex":    {"Ex", "Source Code"},
+	".exs":   {"Exs", "Source Code"},
+	".erl":   {"Erl", "Source Code"},
+	".hs":    {"Hs", "Source Code"},
+	".ml":    {"ML", "Source Code"},
+	".r":     {"R", "Source Code"},
+	".m":     {"OC", "Source Code"},
+	// Shell / Scripts
+	".sh":   {"Sh", "Shell Script"},
+	".bash": {"Sh", "Shell Script"},
+	".zsh":  {"Sh", "Shell Script"},
+	".fish": {"Sh", "Shell Script"},
+	".ps1":  {"PS", "PowerShell Script"},
+	".bat":  {"Bat", "Batch Script"},
+	// Markup / Config
+	".html": {"HTM", "Markup"},
+	".htm":  {"HTM", "Markup"},
+	".xml":  {"XML", "Markup"},
+	".svg":  {"SVG", "Vector Image"},
+	".css":  {"CSS", "Stylesheet"},
+	".scss": {"SCS", "Stylesheet"},
+	".less": {"Les", "Stylesheet"},
+	".json": {"JSN", "Data (JSON)"},
+	".yaml": {"YML", "Data (YAML)"},
+	".yml":  {"YML", "Data (YAML)"},
+	".toml": {"TML", "Data (TOML)"},
+	".ini":  {"INI", "Configuration"},
+	".cfg":  {"CFG", "Configuration"},
+	".env":  {"ENV", "Configuration"},
+	// Documents
+	".md":   {"MD", "Markdown"},
+	".txt":  {"TXT", "Plain Text"},
+	".rst":  {"RST", "Markup Document"},
+	".pdf":  {"PDF", "PDF Document"},
+	".doc":  {"DOC", "Word Document"},
+	".docx": {"DOC", "Word Document"},
+	".xls":  {"XLS", "Spreadsheet"},
+	".xlsx": {"XLS", "Spreadsheet"},
+	".csv":  {"CSV", "Comma-Separated"},
+	".ppt":  {"PPT", "Presentation"},
+	".pptx": {"PPT", "Presentation"},
+	// Images
+	".png":  {"PNG", "Image"},
+	".jpg":  {"JPG", "Image"},
+	".jpeg": {"JPG", "Image"},
+	".gif":  {"GIF", "Image"},
+	".bmp":  {"BMP", "Image"},
+	".webp": {"WBP", "Image"},
+	".ico":  {"ICO", "Icon"},
+	".tiff": {"TIF", "Image"},
+	// Audio
+	".mp3":  {"MP3", "Audio"},
+	".wav":  {"WAV", "Audio"},
+	".flac": {"FLC", "Audio"},
+	".ogg":  {"OGG", "Audio"},
+	".aac":  {"AAC", "Audio"},
+	".m4a":  {"M4A", "Audio"},
+	// Video
+	".mp4":  {"MP4", "Video"},
+	".mkv":  {"MKV", "Video"},
+	".avi":  {"AVI", "Video"},
+	".mov":  {"MOV", "Video"},
+	".webm": {"WBM", "Video"},
+	".wmv":  {"WMV", "Video"},
+	// Archives
+	".zip":  {"ZIP", "Archive"},
+	".tar":  {"TAR", "Archive"},
+	".gz":   {"GZ", "Archive"},
+	".bz2":  {"BZ2", "Archive"},
+	".xz":   {"XZ", "Archive"},
+	".7z":   {"7Z", "Archive"},
+	".rar":  {"RAR", "Archive"},
+	".zst":  {"ZST", "Archive"},
+	// Binary / Executable
+	".exe":  {"EXE", "Executable"},
+	".dll":  {"DLL", "Library"},
+	".so":   {"SO", "Shared Library"},
+	".dylib": {"DYL", "Shared Library"},
+	".bin":  {"BIN", "Binary"},
+	".o":    {"OBJ", "Object File"},
+	".a":    {"LIB", "Static Library"},
+	".wasm": {"WSM", "WebAssembly"},
+	// Database
+	".db":     {"DB", "Database"},
+	".sqlite": {"SQL", "Database"},
+	".sql":    {"SQL", "SQL Script"},
+	// Build / Lock
+	".lock": {"LCK", "Lock File"},
+	".sum":  {"SUM", "Checksum"},
+	".mod":  {"MOD", "Module File"},
+}
+
+// OtherCategory is the category of files whose extension is not recognized.
+const OtherCategory = "File"
+
+// Extension returns the lowercase extension of name, including the dot ("" if none).
+func Extension(name string) string {
+	return strings.ToLower(filepath.Ext(name))
+}
+
+// LookupFileType returns the file type for name's extension.
+func LookupFileType(name string) (FileType, bool) {
+	ft, ok := fileTypeMap[Extension(name)]
+	return ft, ok
+}
+
+// Category returns the file type category for name (OtherCategory if unknown).
+func Category(name string) string {
+	if ft, ok := LookupFileType(name); ok {
+		return ft.Category
+	}
+	return OtherCategory
+}
+
+// Categories returns every category a recognized extension belongs to, sorted.
+func Categories() []string {
+	seen := make(map[string]bool)
+	var cats []string
+	for _, ft := range fileTypeMap {
+		if !seen[ft.Category] {
+			seen[ft.Category] = true
+			cats = append(cats, ft.Category)
+		}
+	}
+	sort.Strings(cats)
+	return cats
+}
@@ -0,0 +1,144 @@
+package fs
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SortMode selects the order a directory's children are shown in. Scanning
+// always sorts Children by size (the layouts' canonical order); other modes
+// are applied to copies with Sorted.
+type SortMode uint8
+
+const (
+	SortSize      SortMode = iota // largest first (the canonical order)
+	SortName                      // natural name order: "file2" before "file10"
+	SortMTime                     // most recently modified first
+	SortExtension                 // grouped by extension, then by name
+	SortTypeFirst                 // directories first, then by name
+)
+
+// sortModeCount is the number of sort modes (used for cycling).
+const sortModeCount = 5
+
+// String returns the sort mode name.
+func (m SortMode) String() string {
+	switch m {
+	case SortSize:
+		return "Size"
+	case SortName:
+		return "Name"
+	case SortMTime:
+		return "Modified"
+	case SortExtension:
+		return "Extension"
+	case SortTypeFirst:
+		return "Folders First"
+	default:
+		return "Unknown"
+	}
+}
+
+// Next returns the sort mode that follows m when cycling.
+func (m SortMode) Next() SortMode {
+	return (m + 1) % sortModeCount
+}
+
+// ParseSortMode converts a name ("size", "name", "mtime", "extension",
+// "type") to a SortMode. The empty string selects SortSize, the default.
+func ParseSortMode(name string) (SortMode, bool) {
+	switch strings.ToLower(name) {
+	case "size", "":
+		return SortSize, true
+	case "name":
+		return SortName, true
+	case "mtime", "modified":
+		return SortMTime, true
+	case "extension", "ext":
+		return SortExtension, true
+	case "type", "folders":
+		return SortTypeFirst, true
+	default:
+		return SortSize, false
+	}
+}
+
+// Less reports whether a is shown before b.
+func (m SortMode) Less(a, b *Entry) bool {
+	switch m {
+	case SortName:
+		return NaturalLess(a.Name, b.Name)
+	case SortMTime:
+		return a.ModTime.After(b.ModTime)
+	case SortExtension:
+		ea, eb := strings.ToLower(filepath.Ext(a.Name)), strings.ToLower(filepath.Ext(b.Name))
+		if ea != eb {
+			return ea < eb
+		}
+		return NaturalLess(a.Name, b.Name)
+	case SortTypeFirst:
+		if da, db := a.Type == TypeDir, b.Type == TypeDir; da != db {
+			return da
+		}
+		return NaturalLess(a.Name, b.Name)
+	default:
+		return a.Size > b.Size
+	}
+}
+
+// Sorted returns entries in mode's order. Entries already in the canonical
+// size order are returned as is; other modes sort a copy, so Children is
+// never reordered.
+func Sorted(entries []*Entry, mode SortMode) []*Entry {
+	if mode == SortSize || len(entries) < 2 {
+		return entries
+	}
+	sorted := append([]*Entry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool { return mode.Less(sorted[i], sorted[j]) })
+	return sorted
+}
+
+// NaturalLess compares names case-insensitively, treating runs of digits as
+// numbers, so "img9.png" sorts before "img10.png".
+func NaturalLess(a, b string) bool {
+	la, lb := strings.ToLower(a), strings.ToLower(b)
+	i, j := 0, 0
+	for i < len(la) && j < len(lb) {
+		ca, cb := la[i], lb[j]
+		if isDigit(ca) && isDigit(cb) {
+			// Compare the digit runs as numbers: skip leading zeros, then the
+			// longer run is larger, then compare digit by digit
+			si, sj := i, j
+			for i < len(la) && isDigit(la[i]) {
+				i++
+			}
+			for j < len(lb) && isDigit(lb[j]) {
+				j++
+			}
+			na := strings.TrimLeft(la[si:i], "0")
+			nb := strings.TrimLeft(lb[sj:j], "0")
+			if len(na) != len(nb) {
+				return len(na) < len(nb)
+			}
+			if na != nb {
+				return na < nb
+			}
+			continue
+		}
+		if ca != cb {
+			return ca < cb
+		}
+		i++
+		j++
+	}
+	if len(la)-i != len(lb)-j {
+		return len(la)-i < len(lb)-j
+	}
+	// Equal ignoring case and zero padding: fall back to a total order
+	return a < b
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,67 @@
+package fs
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNaturalLess(t *testing.T) {
+	ordered := []string{"a", "B", "file2", "file10", "file010b", "img9.png", "img10.png", "z"}
+	for i := 0; i+1 < len(ordered); i++ {
+		a, b := ordered[i], ordered[i+1]
+		if !NaturalLess(a, b) || NaturalLess(b, a) {
+			t.Errorf("want %q before %q", a, b)
+		}
+	}
+	if NaturalLess("x", "x") {
+		t.Error("a name should not sort before itself")
+	}
+	// Names equal but for case or zero padding still get a total order
+	if NaturalLess("File1", "file1") == NaturalLess("file1", "File1") {
+		t.Error("File1 and file1 should order one way")
+	}
+}
+
+func TestSorted(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	children := []*Entry{
+		{Name: "b10.txt", Type: TypeFile, Size: 300, ModTime: now},
+		{Name: "src", Type: TypeDir, Size: 200, ModTime: now.Add(-time.Hour)},
+		{Name: "a.go", Type: TypeFile, Size: 100, ModTime: now.Add(time.Hour)},
+		{Name: "b9.txt", Type: TypeFile, Size: 50, ModTime: now.Add(-2 * time.Hour)},
+	}
+	cases := map[SortMode]string{
+		SortSize:      "b10.txt src a.go b9.txt",
+		SortName:      "a.go b9.txt b10.txt src",
+		SortMTime:     "a.go b10.txt src b9.txt",
+		SortExtension: "src a.go b9.txt b10.txt",
+		SortTypeFirst: "src a.go b9.txt b10.txt",
+	}
+	for mode, want := range cases {
+		var names []string
+		for _, e := range Sorted(children, mode) {
+			names = append(names, e.Name)
+		}
+		if got := strings.Join(names, " "); got != want {
+			t.Errorf("%s: got %s, want %s", mode, got, want)
+		}
+	}
+	if children[0].Name != "b10.txt" || children[3].Name != "b9.txt" {
+		t.Error("Sorted reordered the canonical slice")
+	}
+}
+
+func TestParseSortMode(t *testing.T) {
+	for m := SortMode(0); m < sortModeCount; m++ {
+		if m.Next() == m {
+			t.Errorf("%s: Next does not advance", m)
+		}
+	}
+	if m, ok := ParseSortMode(""); !ok || m != SortSize {
+		t.Errorf(`ParseSortMode(""): %s, %v; want Size`, m, ok)
+	}
+	if _, ok := ParseSortMode("random"); ok {
+		t.Error("unknown sort mode accepted")
+	}
+}
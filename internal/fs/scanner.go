@@ -31,6 +31,7 @@ type ScannerOptions struct {
 	MaxDepth       int      // maximum recursion depth (0 = unlimited)
 	IgnorePatterns []string // glob patterns to skip
 	ShowHidden     bool     // if false, skip dotfiles/dotdirs (default: false)
+	BirthTime      bool     // capture creation times where it costs an extra syscall (Linux statx)
 }
 
 // Scanner performs concurrent filesystem scanning.
@@ -39,6 +40,7 @@ type Scanner struct {
 	maxDepth       int
 	ignorePatterns []string
 	showHidden     bool
+	birthTime      bool
 
 	// Atomic counters for progress
 	dirsScanned atomic.Int64
@@ -63,6 +65,41 @@ func NewScanner(opts ScannerOptions) *Scanner {
 		maxDepth:       opts.MaxDepth,
 		ignorePatterns: patterns,
 		showHidden:     opts.ShowHidden,
+		birthTime:      opts.BirthTime,
+	}
+}
+
+// DepthCovers reports whether a scan to depth have (0 = unlimited) holds
+// everything a scan to depth want would.
+func DepthCovers(have, want int) bool {
+	return have == 0 || (want != 0 && want <= have)
+}
+
+// setTimes copies the timestamps from info onto the entry.
+func (s *Scanner) setTimes(entry *Entry, info os.FileInfo) {
+	entry.ModTime = info.ModTime()
+	entry.AccessTime, entry.ChangeTime, entry.BirthTime = statTimes(entry.Path, info, s.birthTime)
+}
+
+// BirthTimeSupported reports whether creation times are recorded for path,
+// by the platform and by the filesystem it is on.
+func BirthTimeSupported(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	_, _, btime := statTimes(path, info, true)
+	return !btime.IsZero()
+}
+
+// LoadBirthTimes fills in the birth times of entry and its loaded
+// descendants, for a tree scanned without ScannerOptions.BirthTime.
+func LoadBirthTimes(entry *Entry) {
+	if info, err := os.Lstat(entry.Path); err == nil {
+		_, _, entry.BirthTime = statTimes(entry.Path, info, true)
+	}
+	for _, child := range entry.Children {
+		LoadBirthTimes(child)
 	}
 }
 
@@ -141,12 +178,12 @@ func (s *Scanner) scanSync(ctx context.Context, root string) (*Tree, error) {
 	}
 
 	rootEntry := &Entry{
-		Name:    filepath.Base(absRoot),
-		Path:    absRoot,
-		Type:    TypeDir,
-		ModTime: info.ModTime(),
-		Depth:   0,
+		Name:  filepath.Base(absRoot),
+		Path:  absRoot,
+		Type:  TypeDir,
+		Depth: 0,
 	}
+	s.setTimes(rootEntry, info)
 
 	sem := make(chan struct{}, s.workerCount)
 	var wg sync.WaitGroup
@@ -195,6 +232,11 @@ func (s *Scanner) walkDir(ctx context.Context, parent *Entry, sem chan struct{},
 			return
 		}
 
+		// .git is ignored by default, but its presence marks a repository root
+		if de.Name() == ".git" {
+			parent.RepoRoot = true
+		}
+
 		if s.shouldIgnore(de.Name()) {
 			continue
 		}
@@ -216,11 +258,11 @@ func (s *Scanner) walkDir(ctx context.Context, parent *Entry, sem chan struct{},
 			// Try to get symlink target info for size
 			if info, err := os.Stat(child.Path); err == nil {
 				child.Size = info.Size()
-				child.ModTime = info.ModTime()
+				s.setTimes(child, info)
 			} else {
 				// Broken symlink - use lstat info
 				if linfo, lerr := os.Lstat(child.Path); lerr == nil {
-					child.ModTime = linfo.ModTime()
+					s.setTimes(child, linfo)
 				}
 			}
 			s.filesFound.Add(1)
@@ -228,7 +270,7 @@ func (s *Scanner) walkDir(ctx context.Context, parent *Entry, sem chan struct{},
 		case de.IsDir():
 			child.Type = TypeDir
 			if info, err := de.Info(); err == nil {
-				child.ModTime = info.ModTime()
+				s.setTimes(child, info)
 			}
 			wg.Add(1)
 			go s.walkDir(ctx, child, sem, wg)
@@ -237,7 +279,7 @@ func (s *Scanner) walkDir(ctx context.Context, parent *Entry, sem chan struct{},
 			child.Type = TypeFile
 			if info, err := de.Info(); err == nil {
 				child.Size = info.Size()
-				child.ModTime = info.ModTime()
+				s.setTimes(child, info)
 				s.bytesTotal.Add(child.Size)
 			}
 			s.filesFound.Add(1)
@@ -245,7 +287,7 @@ func (s *Scanner) walkDir(ctx context.Context, parent *Entry, sem chan struct{},
 		default:
 			child.Type = TypeOther
 			if info, err := de.Info(); err == nil {
-				child.ModTime = info.ModTime()
+				s.setTimes(child, info)
 			}
 			s.filesFound.Add(1)
 		}
@@ -273,6 +315,9 @@ func (s *Scanner) LoadDir(entry *Entry) error {
 
 	children := make([]*Entry, 0, len(dirEntries))
 	for _, de := range dirEntries {
+		if de.Name() == ".git" {
+			entry.RepoRoot = true
+		}
 		if s.shouldIgnore(de.Name()) {
 			continue
 		}
@@ -291,18 +336,18 @@ func (s *Scanner) LoadDir(entry *Entry) error {
 			child.Type = TypeSymlink
 			if info, err := os.Stat(child.Path); err == nil {
 				child.Size = info.Size()
-				child.ModTime = info.ModTime()
+				s.setTimes(child, info)
 			}
 		case de.IsDir():
 			child.Type = TypeDir
 			if info, err := de.Info(); err == nil {
-				child.ModTime = info.ModTime()
+				s.setTimes(child, info)
 			}
 		case de.Type().IsRegular():
 			child.Type = TypeFile
 			if info, err := de.Info(); err == nil {
 				child.Size = info.Size()
-				child.ModTime = info.ModTime()
+				s.setTimes(child, info)
 			}
 		default:
 			child.Type = TypeOther
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
+	"time"
 )
 
 func TestScanSync_BasicTree(t *testing.T) {
@@ -224,6 +226,162 @@ func TestEntryDirCount(t *testing.T) {
 	}
 }
 
+func TestEntryFullyLoaded(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "a", "b"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(tmpDir, "a", "b", "c.txt"), 10)
+
+	shallow, err := NewScanner(ScannerOptions{MaxDepth: 1}).ScanSync(context.Background(), tmpDir)
+	if err != nil {
+		t.Fatalf("ScanSync failed: %v", err)
+	}
+	if shallow.Root.FullyLoaded() {
+		t.Error("a scan to depth 1 should leave a/b unloaded")
+	}
+	full, err := NewScanner(ScannerOptions{}).ScanSync(context.Background(), tmpDir)
+	if err != nil {
+		t.Fatalf("ScanSync failed: %v", err)
+	}
+	if !full.Root.FullyLoaded() {
+		t.Error("an unlimited scan should load every directory")
+	}
+}
+
+func TestScanSync_Timestamps(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "stamped.txt")
+	writeFile(t, path, 10)
+
+	atime := time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)
+	mtime := time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(path, atime, mtime); err != nil {
+		t.Fatalf("Chtimes failed: %v", err)
+	}
+
+	scanner := NewScanner(ScannerOptions{BirthTime: true})
+	tree, err := scanner.ScanSync(context.Background(), tmpDir)
+	if err != nil {
+		t.Fatalf("ScanSync failed: %v", err)
+	}
+	if len(tree.Root.Children) != 1 {
+		t.Fatalf("expected 1 child, got %d", len(tree.Root.Children))
+	}
+	entry := tree.Root.Children[0]
+
+	if !entry.Time(TimeModified).Equal(mtime) {
+		t.Errorf("mtime: got %v, want %v", entry.Time(TimeModified), mtime)
+	}
+	if !entry.Time(TimeAccessed).Equal(atime) {
+		t.Errorf("atime: got %v, want %v", entry.Time(TimeAccessed), atime)
+	}
+	// ctime can't be set, but it is always newer than the backdated mtime
+	if runtime.GOOS != "windows" && !entry.Time(TimeChanged).After(mtime) {
+		t.Errorf("ctime %v should be after mtime %v", entry.Time(TimeChanged), mtime)
+	}
+	// Birth time support depends on the filesystem; when present it must be recent
+	if bt := entry.Time(TimeBirth); !bt.IsZero() && time.Since(bt) > time.Hour {
+		t.Errorf("birth time %v is not recent", bt)
+	}
+}
+
+func TestLoadBirthTimes_FillsScannedTree(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(tmpDir, "sub", "a.txt"), 10)
+	if !BirthTimeSupported(tmpDir) {
+		t.Skip("filesystem does not record birth times")
+	}
+
+	tree, err := NewScanner(ScannerOptions{}).ScanSync(context.Background(), tmpDir)
+	if err != nil {
+		t.Fatalf("ScanSync failed: %v", err)
+	}
+	LoadBirthTimes(tree.Root)
+	var check func(e *Entry)
+	check = func(e *Entry) {
+		if e.BirthTime.IsZero() || time.Since(e.BirthTime) > time.Hour {
+			t.Errorf("%s: birth time %v, want a recent one", e.Path, e.BirthTime)
+		}
+		for _, c := range e.Children {
+			check(c)
+		}
+	}
+	check(tree.Root)
+}
+
+func TestScanSync_DetectsRepoRoot(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.MkdirAll(filepath.Join(tmpDir, "repo", ".git"), 0755)
+	os.MkdirAll(filepath.Join(tmpDir, "plain"), 0755)
+	writeFile(t, filepath.Join(tmpDir, "repo", "main.go"), 10)
+
+	scanner := NewScanner(ScannerOptions{})
+	tree, err := scanner.ScanSync(context.Background(), tmpDir)
+	if err != nil {
+		t.Fatalf("ScanSync failed: %v", err)
+	}
+
+	for _, child := range tree.Root.Children {
+		switch child.Name {
+		case "repo":
+			if !child.RepoRoot {
+				t.Error("repo should be detected as a repository root")
+			}
+			for _, gc := range child.Children {
+				if gc.Name == ".git" {
+					t.Error(".git should still be ignored")
+				}
+			}
+		case "plain":
+			if child.RepoRoot {
+				t.Error("plain should not be a repository root")
+			}
+		}
+	}
+}
+
+func TestEntryTime_Fields(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	entry := &Entry{
+		ModTime:    base,
+		ChangeTime: base.Add(time.Hour),
+		AccessTime: base.Add(2 * time.Hour),
+		BirthTime:  base.Add(-time.Hour),
+	}
+	want := map[TimeField]time.Time{
+		TimeModified: entry.ModTime,
+		TimeChanged:  entry.ChangeTime,
+		TimeAccessed: entry.AccessTime,
+		TimeBirth:    entry.BirthTime,
+	}
+	for field, w := range want {
+		if got := entry.Time(field); !got.Equal(w) {
+			t.Errorf("%s: got %v, want %v", field, got, w)
+		}
+	}
+}
+
+func TestParseTimeField_RoundTrip(t *testing.T) {
+	field := TimeModified
+	for i := 0; i < 4; i++ {
+		parsed, ok := ParseTimeField(field.String())
+		if !ok || parsed != field {
+			t.Errorf("round-trip %s: got %s, %v", field, parsed, ok)
+		}
+		field = field.Next()
+	}
+	if field != TimeModified {
+		t.Errorf("Next should cycle back to mtime, got %s", field)
+	}
+	if _, ok := ParseTimeField("bogus"); ok {
+		t.Error("expected bogus to be rejected")
+	}
+}
+
 // writeFile creates a file with exactly the specified size.
 func writeFile(t *testing.T, path string, size int) {
 	t.Helper()
@@ -232,3 +390,22 @@ func writeFile(t *testing.T, path string, size int) {
 		t.Fatalf("failed to write %s: %v", path, err)
 	}
 }
+
+func TestDepthCovers(t *testing.T) {
+	for _, tc := range []struct {
+		have, want int
+		covers     bool
+	}{
+		{0, 0, true}, // unlimited covers unlimited
+		{0, 5, true},
+		{5, 0, false}, // nothing limited covers unlimited
+		{5, 5, true},
+		{5, 3, true},
+		{3, 5, false},
+		{1, 2, false},
+	} {
+		if got := DepthCovers(tc.have, tc.want); got != tc.covers {
+			t.Errorf("DepthCovers(%d, %d) = %v, want %v", tc.have, tc.want, got, tc.covers)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+//go:build darwin || freebsd
+
+package fs
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// statTimes extracts access, change and birth times from info.
+// Birth time is part of struct stat on these platforms, so it is always captured.
+func statTimes(path string, info os.FileInfo, birth bool) (atime, ctime, btime time.Time) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return atime, ctime, btime
+	}
+	atime = time.Unix(st.Atimespec.Unix())
+	ctime = time.Unix(st.Ctimespec.Unix())
+	btime = time.Unix(st.Birthtimespec.Unix())
+	return atime, ctime, btime
+}
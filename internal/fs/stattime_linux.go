@@ -0,0 +1,43 @@
+//go:build linux
+
+package fs
+
+import (
+	"os"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// statTimes extracts access, change and (optionally) birth times from info.
+// Linux only exposes birth time through statx(2), which costs an extra
+// syscall per entry, so it is only queried when birth is true.
+func statTimes(path string, info os.FileInfo, birth bool) (atime, ctime, btime time.Time) {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		atime = time.Unix(st.Atim.Unix())
+		ctime = time.Unix(st.Ctim.Unix())
+	}
+	if birth {
+		btime = statxBirthTime(path, info.Mode()&os.ModeSymlink == 0)
+	}
+	return atime, ctime, btime
+}
+
+// statxBirthTime returns the file's birth time, or zero if the kernel or
+// filesystem does not record one. follow resolves a symlink at path as
+// os.Stat does; otherwise the link itself is queried, as os.Lstat does.
+func statxBirthTime(path string, follow bool) time.Time {
+	flags := 0
+	if !follow {
+		flags = unix.AT_SYMLINK_NOFOLLOW
+	}
+	var st unix.Statx_t
+	if err := unix.Statx(unix.AT_FDCWD, path, flags, unix.STATX_BTIME, &st); err != nil {
+		return time.Time{}
+	}
+	if st.Mask&unix.STATX_BTIME == 0 {
+		return time.Time{}
+	}
+	return time.Unix(st.Btime.Sec, int64(st.Btime.Nsec))
+}
@@ -0,0 +1,32 @@
+//go:build linux
+
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStatxBirthTime_FollowsSymlinksLikeStat(t *testing.T) {
+	tmpDir := t.TempDir()
+	target := filepath.Join(tmpDir, "target.txt")
+	writeFile(t, target, 10)
+	want := statxBirthTime(target, true)
+	if want.IsZero() {
+		t.Skip("filesystem does not record birth times")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	link := filepath.Join(tmpDir, "link")
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatal(err)
+	}
+	if got := statxBirthTime(link, true); !got.Equal(want) {
+		t.Errorf("followed link born %v, want the target's %v", got, want)
+	}
+	if got := statxBirthTime(link, false); !got.After(want) {
+		t.Errorf("link itself born %v, want after the target's %v", got, want)
+	}
+}
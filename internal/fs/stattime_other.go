@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !freebsd && !windows
+
+package fs
+
+import (
+	"os"
+	"time"
+)
+
+// statTimes reports no extra timestamps on platforms without a known stat layout.
+func statTimes(path string, info os.FileInfo, birth bool) (atime, ctime, btime time.Time) {
+	return atime, ctime, btime
+}
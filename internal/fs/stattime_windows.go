@@ -0,0 +1,21 @@
+//go:build windows
+
+package fs
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// statTimes extracts access and creation times from info.
+// Windows has no inode change time, so ctime is always zero.
+func statTimes(path string, info os.FileInfo, birth bool) (atime, ctime, btime time.Time) {
+	d, ok := info.Sys().(*syscall.Win32FileAttributeData)
+	if !ok {
+		return atime, ctime, btime
+	}
+	atime = time.Unix(0, d.LastAccessTime.Nanoseconds())
+	btime = time.Unix(0, d.CreationTime.Nanoseconds())
+	return atime, ctime, btime
+}
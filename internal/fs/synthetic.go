@@ -0,0 +1,71 @@
+package fs
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+)
+
+// syntheticExts gives synthetic files a mix of type categories.
+var syntheticExts = []string{".go", ".json", ".md", ".png", ".mp3", ".mp4", ".zip", ".bin", ".dat"}
+
+// SyntheticTree builds a deterministic in-memory tree with the given number
+// of files, filesPerDir files in each directory and up to fanout
+// subdirectories per directory. Sizes span bytes to gigabytes and
+// modification times the five years before ScannedAt, so every color bucket
+// is populated. Used by the rendering benchmark scene and tests.
+func SyntheticTree(files, filesPerDir, fanout int) *Tree {
+	if filesPerDir < 1 {
+		filesPerDir = 1
+	}
+	if fanout < 1 {
+		fanout = 1
+	}
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	span := int64(5 * 365 * 24 * time.Hour)
+	seed := uint64(0x9e3779b97f4a7c15)
+	next := func() uint64 {
+		// xorshift64: cheap and reproducible across platforms
+		seed ^= seed << 13
+		seed ^= seed >> 7
+		seed ^= seed << 17
+		return seed
+	}
+
+	root := &Entry{Name: "synthetic", Path: "/synthetic", Type: TypeDir, ModTime: now, Loaded: true}
+	queue := []*Entry{root}
+	made := 0
+	for len(queue) > 0 && made < files {
+		dir := queue[0]
+		queue = queue[1:]
+		for i := 0; i < filesPerDir && made < files; i++ {
+			name := fmt.Sprintf("file%d%s", made, syntheticExts[next()%uint64(len(syntheticExts))])
+			dir.Children = append(dir.Children, &Entry{
+				Name:    name,
+				Path:    filepath.Join(dir.Path, name),
+				Type:    TypeFile,
+				Size:    int64(1) << (next() % 32),
+				ModTime: now.Add(-time.Duration(next() % uint64(span))),
+				Depth:   dir.Depth + 1,
+			})
+			made++
+		}
+		for i := 0; i < fanout && made < files; i++ {
+			name := fmt.Sprintf("dir%d", i)
+			sub := &Entry{
+				Name:    name,
+				Path:    filepath.Join(dir.Path, name),
+				Type:    TypeDir,
+				ModTime: now,
+				Depth:   dir.Depth + 1,
+				Loaded:  true,
+			}
+			dir.Children = append(dir.Children, sub)
+			queue = append(queue, sub)
+		}
+	}
+
+	tree := buildTree(root)
+	tree.ScannedAt = now
+	return tree
+}
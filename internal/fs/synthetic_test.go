@@ -0,0 +1,24 @@
+package fs
+
+import "testing"
+
+func TestSyntheticTree_Counts(t *testing.T) {
+	tree := SyntheticTree(1000, 20, 4)
+	if tree.FileCount != 1000 {
+		t.Errorf("FileCount = %d, want 1000", tree.FileCount)
+	}
+	if tree.Root.FileCount() != 1000 {
+		t.Errorf("Root.FileCount() = %d, want 1000", tree.Root.FileCount())
+	}
+	if tree.MaxDepth < 2 {
+		t.Errorf("MaxDepth = %d, want a nested tree", tree.MaxDepth)
+	}
+}
+
+func TestSyntheticTree_Deterministic(t *testing.T) {
+	a := SyntheticTree(500, 10, 3)
+	b := SyntheticTree(500, 10, 3)
+	if a.TotalSize != b.TotalSize || a.DirCount != b.DirCount {
+		t.Errorf("trees differ: %d/%d bytes, %d/%d dirs", a.TotalSize, b.TotalSize, a.DirCount, b.DirCount)
+	}
+}
@@ -0,0 +1,239 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+)
+
+func TestParsePorcelain(t *testing.T) {
+	out := "## main...origin/main [ahead 1]\x00" +
+		" M src/app.go\x00" +
+		"A  new.go\x00" +
+		"R  renamed.go\x00old.go\x00" +
+		"?? notes/\x00" +
+		"!! build/\x00"
+
+	branch, entries := ParsePorcelain([]byte(out))
+	if BranchName(branch) != "main" {
+		t.Errorf("branch: got %q, want main", BranchName(branch))
+	}
+
+	want := []struct {
+		path   string
+		status fs.GitStatus
+	}{
+		{"src/app.go", fs.GitModified},
+		{"new.go", fs.GitModified},
+		{"renamed.go", fs.GitModified},
+		{"notes/", fs.GitUntracked},
+		{"build/", fs.GitIgnored},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("expected %d entries, got %d: %+v", len(want), len(entries), entries)
+	}
+	for i, w := range want {
+		if entries[i].Path != w.path || entries[i].Status != w.status {
+			t.Errorf("entry %d: got %s %s, want %s %s",
+				i, entries[i].Path, entries[i].Status, w.path, w.status)
+		}
+	}
+}
+
+func TestBranchName(t *testing.T) {
+	cases := map[string]string{
+		"main":                        "main",
+		"main...origin/main":          "main",
+		"feature/x...up/x [behind 2]": "feature/x",
+		"No commits yet on trunk":     "trunk",
+	}
+	for in, want := range cases {
+		if got := BranchName(in); got != want {
+			t.Errorf("BranchName(%q): got %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestReadOffset(t *testing.T) {
+	// Values from git's varint encoding: 0x7f fits in one byte, 0x80 needs two
+	if v, n := readOffset([]byte{0x7f}); v != 127 || n != 1 {
+		t.Errorf("0x7f: got %d (%d bytes)", v, n)
+	}
+	if v, n := readOffset([]byte{0x80, 0x00}); v != 128 || n != 2 {
+		t.Errorf("0x80 0x00: got %d (%d bytes)", v, n)
+	}
+	if _, n := readOffset([]byte{0x80}); n != 0 {
+		t.Error("truncated offset should fail")
+	}
+}
+
+func TestParseIndex_BadSignature(t *testing.T) {
+	if _, err := parseIndex([]byte("NOPE\x00\x00\x00\x02\x00\x00\x00\x00")); err == nil {
+		t.Error("expected error for bad signature")
+	}
+}
+
+// fixtureRepo creates a repository with one clean, modified, untracked and
+// ignored path of each kind.
+func fixtureRepo(t *testing.T) string {
+	t.Helper()
+	if !Available() {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	gitCmd(t, dir, "init", "-q", "-b", "main")
+	gitCmd(t, dir, "config", "user.email", "test@example.com")
+	gitCmd(t, dir, "config", "user.name", "Test")
+	gitCmd(t, dir, "config", "commit.gpgsign", "false")
+
+	write(t, dir, "clean.txt", "clean")
+	write(t, dir, "modified.txt", "v1")
+	write(t, dir, "src/lib/deep.go", "package lib")
+	write(t, dir, ".gitignore", "*.log\nbuild/\n")
+	gitCmd(t, dir, "add", "-A")
+	gitCmd(t, dir, "commit", "-q", "-m", "Initial commit")
+
+	write(t, dir, "modified.txt", "v2")
+	write(t, dir, "untracked.txt", "new")
+	write(t, dir, "notes/todo.md", "- x")
+	write(t, dir, "debug.log", "noise")
+	write(t, dir, "build/out.bin", "bin")
+	return dir
+}
+
+func TestOpen_FixtureRepo(t *testing.T) {
+	dir := fixtureRepo(t)
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if repo.Branch != "main" {
+		t.Errorf("branch: got %q, want main", repo.Branch)
+	}
+	if repo.Head.Subject != "Initial commit" || repo.Head.Hash == "" {
+		t.Errorf("head: got %+v", repo.Head)
+	}
+	if repo.Tracked != 4 {
+		t.Errorf("tracked: got %d, want 4", repo.Tracked)
+	}
+	if repo.Modified != 1 || repo.Untracked != 2 || repo.Ignored != 2 {
+		t.Errorf("counts: modified=%d untracked=%d ignored=%d, want 1/2/2",
+			repo.Modified, repo.Untracked, repo.Ignored)
+	}
+
+	cases := []struct {
+		rel    string
+		isDir  bool
+		status fs.GitStatus
+	}{
+		{"clean.txt", false, fs.GitClean},
+		{"modified.txt", false, fs.GitModified},
+		{"untracked.txt", false, fs.GitUntracked},
+		{"notes", true, fs.GitUntracked},
+		{"notes/todo.md", false, fs.GitUntracked},
+		{"debug.log", false, fs.GitIgnored},
+		{"build/out.bin", false, fs.GitIgnored},
+		{"src", true, fs.GitClean},
+		{"src/lib/deep.go", false, fs.GitClean},
+		{"", true, fs.GitModified}, // root contains changes
+	}
+	for _, c := range cases {
+		abs := filepath.Join(dir, filepath.FromSlash(c.rel))
+		if got := repo.StatusOf(abs, c.isDir); got != c.status {
+			t.Errorf("%q: got %s, want %s", c.rel, got, c.status)
+		}
+	}
+
+	if got := repo.StatusOf(filepath.Dir(dir), true); got != fs.GitNone {
+		t.Errorf("outside repo: got %s, want none", got)
+	}
+}
+
+func TestAnnotate_ScannedTree(t *testing.T) {
+	dir := fixtureRepo(t)
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	root := &fs.Entry{Name: "repo", Path: dir, Type: fs.TypeDir, Children: []*fs.Entry{
+		{Name: "modified.txt", Path: filepath.Join(dir, "modified.txt"), Type: fs.TypeFile},
+		{Name: "src", Path: filepath.Join(dir, "src"), Type: fs.TypeDir},
+	}}
+	repo.Annotate(root)
+
+	if root.Git != fs.GitModified {
+		t.Errorf("root: got %s, want modified", root.Git)
+	}
+	if root.Children[0].Git != fs.GitModified {
+		t.Errorf("modified.txt: got %s", root.Children[0].Git)
+	}
+	if root.Children[1].Git != fs.GitClean {
+		t.Errorf("src: got %s", root.Children[1].Git)
+	}
+
+	// A tree scanned from above the repository reaches it through its parent
+	outer := &fs.Entry{Name: "outer", Path: filepath.Dir(dir), Type: fs.TypeDir,
+		Children: []*fs.Entry{root}}
+	root.Git = fs.GitNone
+	repo.Annotate(outer)
+	if outer.Git != fs.GitNone {
+		t.Errorf("outer dir: got %s, want none", outer.Git)
+	}
+	if root.Git != fs.GitModified {
+		t.Errorf("nested root: got %s, want modified", root.Git)
+	}
+}
+
+func TestReadIndex_MatchesLsFiles(t *testing.T) {
+	dir := fixtureRepo(t)
+
+	for _, version := range []string{"2", "3", "4"} {
+		gitCmd(t, dir, "update-index", "--index-version", version)
+		got, err := ReadIndex(filepath.Join(dir, ".git", "index"))
+		if err != nil {
+			t.Fatalf("v%s: ReadIndex failed: %v", version, err)
+		}
+		want := strings.Fields(gitCmd(t, dir, "ls-files"))
+		sort.Strings(got)
+		sort.Strings(want)
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("v%s: got %v, want %v", version, got, want)
+		}
+	}
+}
+
+func TestFindRoot(t *testing.T) {
+	dir := fixtureRepo(t)
+	if got := FindRoot(filepath.Join(dir, "src", "lib")); got != dir {
+		t.Errorf("FindRoot: got %q, want %q", got, dir)
+	}
+}
+
+func gitCmd(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_NOSYSTEM=1")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return string(out)
+}
+
+func write(t *testing.T, dir, rel, content string) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,100 @@
+package git
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Index entry layout (see git's Documentation/gitformat-index.txt).
+const (
+	indexHeaderSize   = 12
+	indexEntryFixed   = 62 // ctime..flags, before the path
+	indexFlagExtended = 0x4000
+)
+
+// ReadIndex returns the paths tracked in a git index file (slash-separated,
+// relative to the repository root). Index versions 2, 3 and 4 are supported.
+func ReadIndex(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseIndex(data)
+}
+
+func parseIndex(data []byte) ([]string, error) {
+	if len(data) < indexHeaderSize || string(data[:4]) != "DIRC" {
+		return nil, errors.New("git index: bad signature")
+	}
+	version := binary.BigEndian.Uint32(data[4:8])
+	if version < 2 || version > 4 {
+		return nil, fmt.Errorf("git index: unsupported version %d", version)
+	}
+	count := binary.BigEndian.Uint32(data[8:12])
+
+	paths := make([]string, 0, count)
+	pos := indexHeaderSize
+	prev := ""
+	for i := uint32(0); i < count; i++ {
+		start := pos
+		if pos+indexEntryFixed > len(data) {
+			return nil, errors.New("git index: truncated entry")
+		}
+		flags := binary.BigEndian.Uint16(data[pos+60 : pos+62])
+		pos += indexEntryFixed
+		if version >= 3 && flags&indexFlagExtended != 0 {
+			pos += 2
+		}
+
+		var name string
+		if version == 4 {
+			// Path is prefix-compressed against the previous entry
+			strip, n := readOffset(data[pos:])
+			if n == 0 || int(strip) > len(prev) {
+				return nil, errors.New("git index: bad path prefix")
+			}
+			pos += n
+			end := bytes.IndexByte(data[pos:], 0)
+			if end < 0 {
+				return nil, errors.New("git index: unterminated path")
+			}
+			name = prev[:len(prev)-int(strip)] + string(data[pos:pos+end])
+			pos += end + 1
+		} else {
+			end := bytes.IndexByte(data[pos:], 0)
+			if end < 0 {
+				return nil, errors.New("git index: unterminated path")
+			}
+			name = string(data[pos : pos+end])
+			// Entries are NUL-padded to a multiple of 8 bytes
+			pos = start + (pos+end-start+8)&^7
+		}
+		paths = append(paths, name)
+		prev = name
+	}
+	return paths, nil
+}
+
+// readOffset decodes git's variable-length offset encoding.
+// Returns the value and the number of bytes consumed (0 on error).
+func readOffset(buf []byte) (uint64, int) {
+	if len(buf) == 0 {
+		return 0, 0
+	}
+	c := buf[0]
+	val := uint64(c & 0x7f)
+	n := 1
+	for c&0x80 != 0 {
+		if n >= len(buf) {
+			return 0, 0
+		}
+		val++
+		c = buf[n]
+		n++
+		val = val<<7 | uint64(c&0x7f)
+	}
+	return val, n
+}
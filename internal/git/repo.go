@@ -0,0 +1,256 @@
+// Package git reads working-tree status for repositories found while scanning.
+package git
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+)
+
+// commandTimeout bounds each git invocation so a huge repo can't stall the UI.
+const commandTimeout = 10 * time.Second
+
+// Commit summarizes the HEAD commit.
+type Commit struct {
+	Hash    string // abbreviated
+	Subject string
+	Author  string
+	Time    time.Time
+}
+
+// Repo holds the status of one repository's working tree.
+type Repo struct {
+	Root      string // absolute path of the working tree
+	Branch    string // empty when HEAD is detached
+	Head      Commit // zero if there are no commits yet
+	Tracked   int    // paths in the index
+	Modified  int
+	Untracked int
+	Ignored   int
+
+	status  map[string]fs.GitStatus // exact paths from git status
+	prefix  map[string]fs.GitStatus // untracked/ignored dirs reported as "dir/"
+	tracked map[string]bool         // tracked files and every ancestor dir
+	dirty   map[string]bool         // dirs containing modified or untracked paths
+}
+
+// Available reports whether a git executable is on PATH.
+func Available() bool {
+	_, err := exec.LookPath("git")
+	return err == nil
+}
+
+// FindRoot returns the working-tree root containing dir, walking upward
+// until a .git entry is found. Returns "" if dir is not inside a repository.
+func FindRoot(dir string) string {
+	for {
+		if _, err := os.Lstat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// Open reads the index and status of the repository rooted at root.
+func Open(root string) (*Repo, error) {
+	out, err := run(root, "status", "--porcelain=v1", "-z", "--branch", "--ignored")
+	if err != nil {
+		return nil, err
+	}
+	header, entries := ParsePorcelain(out)
+	r := newRepo(root, header, entries)
+
+	if paths, err := ReadIndex(indexPath(root)); err == nil {
+		r.setTracked(paths)
+	}
+
+	if log, err := run(root, "log", "-1", "--format=%h%x00%s%x00%an%x00%ct"); err == nil {
+		r.Head = parseCommit(log)
+	}
+	return r, nil
+}
+
+// newRepo builds a Repo from parsed porcelain output.
+func newRepo(root, header string, entries []StatusEntry) *Repo {
+	r := &Repo{
+		Root:    root,
+		status:  make(map[string]fs.GitStatus),
+		prefix:  make(map[string]fs.GitStatus),
+		tracked: make(map[string]bool),
+		dirty:   make(map[string]bool),
+	}
+	if !strings.HasPrefix(header, "HEAD ") {
+		r.Branch = BranchName(header)
+	}
+
+	for _, e := range entries {
+		switch e.Status {
+		case fs.GitModified:
+			r.Modified++
+		case fs.GitUntracked:
+			r.Untracked++
+		case fs.GitIgnored:
+			r.Ignored++
+		}
+
+		p := strings.TrimSuffix(e.Path, "/")
+		if strings.HasSuffix(e.Path, "/") {
+			r.prefix[p] = e.Status
+		} else {
+			r.status[p] = e.Status
+		}
+		if e.Status == fs.GitModified || e.Status == fs.GitUntracked {
+			for dir := path.Dir(p); dir != "."; dir = path.Dir(dir) {
+				r.dirty[dir] = true
+			}
+			r.dirty[""] = true
+		}
+	}
+	return r
+}
+
+// setTracked records the index paths and their ancestor directories.
+func (r *Repo) setTracked(paths []string) {
+	r.Tracked = len(paths)
+	for _, p := range paths {
+		r.tracked[p] = true
+		for dir := path.Dir(p); dir != "."; dir = path.Dir(dir) {
+			if r.tracked[dir] {
+				break
+			}
+			r.tracked[dir] = true
+		}
+	}
+	if len(paths) > 0 {
+		r.tracked[""] = true
+	}
+}
+
+// Dirty reports whether the working tree has modified or untracked files.
+func (r *Repo) Dirty() bool {
+	return r.Modified > 0 || r.Untracked > 0
+}
+
+// Contains reports whether an absolute path lies inside the working tree.
+func (r *Repo) Contains(absPath string) bool {
+	_, ok := r.rel(absPath)
+	return ok
+}
+
+// rel converts an absolute path to the slash-separated repo-relative form.
+func (r *Repo) rel(absPath string) (string, bool) {
+	rel, ok := relPath(r.Root, absPath)
+	if !ok {
+		return "", false
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == "." {
+		rel = ""
+	}
+	return rel, true
+}
+
+// relPath returns target relative to base, or false if target is outside base.
+func relPath(base, target string) (string, bool) {
+	rel, err := filepath.Rel(base, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
+
+// StatusOf returns the status of an absolute path inside the repository.
+func (r *Repo) StatusOf(absPath string, isDir bool) fs.GitStatus {
+	rel, ok := r.rel(absPath)
+	if !ok {
+		return fs.GitNone
+	}
+	if st, ok := r.status[rel]; ok {
+		return st
+	}
+	// Inside an untracked or ignored directory?
+	for p := rel; ; p = path.Dir(p) {
+		if st, ok := r.prefix[p]; ok {
+			return st
+		}
+		if p == "." || p == "" || !strings.Contains(p, "/") {
+			break
+		}
+	}
+	if isDir && r.dirty[rel] {
+		return fs.GitModified
+	}
+	if r.tracked[rel] {
+		return fs.GitClean
+	}
+	return fs.GitNone
+}
+
+// Annotate sets Git on entry and its loaded descendants that lie inside the repo.
+// Entries above the repository root are walked but left untouched.
+func (r *Repo) Annotate(entry *fs.Entry) {
+	if entry == nil {
+		return
+	}
+	if r.Contains(entry.Path) {
+		entry.Git = r.StatusOf(entry.Path, entry.IsDir())
+	} else if _, above := relPath(entry.Path, r.Root); !above {
+		return
+	}
+	for _, child := range entry.Children {
+		r.Annotate(child)
+	}
+}
+
+// run executes a git command in dir and returns its stdout.
+func run(dir string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_OPTIONAL_LOCKS=0")
+	return cmd.Output()
+}
+
+// indexPath locates the index file, following a gitdir: pointer for
+// worktrees and submodules where .git is a file.
+func indexPath(root string) string {
+	dotGit := filepath.Join(root, ".git")
+	data, err := os.ReadFile(dotGit)
+	if err != nil {
+		return filepath.Join(dotGit, "index") // a directory (or unreadable)
+	}
+	gitDir := strings.TrimSpace(strings.TrimPrefix(string(data), "gitdir:"))
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(root, gitDir)
+	}
+	return filepath.Join(gitDir, "index")
+}
+
+// parseCommit parses `git log -1 --format=%h%x00%s%x00%an%x00%ct` output.
+func parseCommit(out []byte) Commit {
+	fields := bytes.Split(bytes.TrimRight(out, "\n"), []byte{0})
+	if len(fields) < 4 {
+		return Commit{}
+	}
+	c := Commit{
+		Hash:    string(fields[0]),
+		Subject: string(fields[1]),
+		Author:  string(fields[2]),
+	}
+	if ts, err := strconv.ParseInt(string(fields[3]), 10, 64); err == nil {
+		c.Time = time.Unix(ts, 0)
+	}
+	return c
+}
@@ -0,0 +1,63 @@
+package git
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+)
+
+// StatusEntry is one path from `git status --porcelain -z`.
+type StatusEntry struct {
+	Path   string // slash-separated, relative to the repo root; dirs end in "/"
+	Index  byte   // X column: staged state
+	Tree   byte   // Y column: worktree state
+	Status fs.GitStatus
+}
+
+// ParsePorcelain parses the output of
+// `git status --porcelain=v1 -z --branch --ignored`.
+// Returns the branch header (e.g. "main...origin/main [ahead 1]") and entries.
+func ParsePorcelain(data []byte) (branch string, entries []StatusEntry) {
+	records := bytes.Split(data, []byte{0})
+	for i := 0; i < len(records); i++ {
+		rec := string(records[i])
+		if len(rec) < 3 {
+			continue
+		}
+		if strings.HasPrefix(rec, "## ") {
+			branch = rec[3:]
+			continue
+		}
+
+		e := StatusEntry{Index: rec[0], Tree: rec[1], Path: rec[3:]}
+		switch {
+		case e.Index == '?' && e.Tree == '?':
+			e.Status = fs.GitUntracked
+		case e.Index == '!' && e.Tree == '!':
+			e.Status = fs.GitIgnored
+		default:
+			e.Status = fs.GitModified
+		}
+		// Renames and copies are followed by the original path
+		if e.Index == 'R' || e.Index == 'C' {
+			i++
+		}
+		entries = append(entries, e)
+	}
+	return branch, entries
+}
+
+// BranchName extracts the local branch from a porcelain branch header.
+func BranchName(header string) string {
+	if name, ok := strings.CutPrefix(header, "No commits yet on "); ok {
+		return name
+	}
+	if i := strings.Index(header, "..."); i >= 0 {
+		return header[:i]
+	}
+	if i := strings.IndexByte(header, ' '); i >= 0 {
+		return header[:i]
+	}
+	return header
+}
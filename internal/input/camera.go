@@ -25,6 +25,9 @@ type OrbitalCamera struct {
 	// When false, skip WASD/arrow/+/- keyboard input (text input active)
 	KeyboardEnabled bool
 
+	// When true, left-drag does not orbit (a box selection is being dragged)
+	RotateLocked bool
+
 	// Reference to keymap for configurable bindings
 	Keys *KeyMap
 }
@@ -60,7 +63,7 @@ func (c *OrbitalCamera) Update() {
 	}
 
 	// Left drag: rotate (matching fsnav: cam_theta += dx * 0.5)
-	if rl.IsMouseButtonDown(rl.MouseButtonLeft) {
+	if !c.RotateLocked && rl.IsMouseButtonDown(rl.MouseButtonLeft) {
 		delta := rl.GetMouseDelta()
 		c.Theta += delta.X * 0.5
 		c.Phi += delta.Y * 0.5
@@ -214,6 +217,32 @@ func (c *OrbitalCamera) Birdseye(minBounds, maxBounds rl.Vector3) {
 	c.updatePosition()
 }
 
+// CameraPresets are the names accepted by ApplyPreset.
+var CameraPresets = []string{"overview", "birdseye", "front", "iso"}
+
+// ApplyPreset frames the scene bounds from a named viewpoint: "overview"
+// (the default framing), "birdseye" (overhead), "front" (low, head-on) or
+// "iso" (diagonal, from above). It returns false for an unknown name.
+func (c *OrbitalCamera) ApplyPreset(name string, minBounds, maxBounds rl.Vector3) bool {
+	switch name {
+	case "overview", "":
+		c.FrameScene(minBounds, maxBounds)
+	case "birdseye":
+		c.Birdseye(minBounds, maxBounds)
+	case "front":
+		c.FrameScene(minBounds, maxBounds)
+		c.Phi = 8
+	case "iso":
+		c.FrameScene(minBounds, maxBounds)
+		c.Theta = 45
+		c.Phi = 35
+	default:
+		return false
+	}
+	c.updatePosition()
+	return true
+}
+
 func lerpVec3(a, b rl.Vector3, t float32) rl.Vector3 {
 	return rl.NewVector3(
 		a.X+(b.X-a.X)*t,
@@ -27,20 +27,28 @@ type InputState struct {
 	leftDragged bool
 
 	// Signals to app.go
-	ExpandRequested bool   // Enter was pressed on selected dir
-	BackRequested   bool   // Escape was pressed
-	HomeRequested   bool   // Home key pressed
-	SearchRequested bool   // F key pressed
-	PathBarRequested bool  // Ctrl+L pressed
-	NextNodeRequested bool // Tab pressed
-	PrevNodeRequested bool // Shift+Tab pressed
-	InspectRequested  bool // Space pressed
-	SettingsRequested bool // Comma pressed
-	OpenFileRequested bool // O pressed
-	BirdseyeRequested bool // B pressed
+	ExpandRequested     bool // Enter was pressed on selected dir
+	BackRequested       bool // Escape was pressed
+	HomeRequested       bool // Home key pressed
+	SearchRequested     bool // F key pressed
+	PathBarRequested    bool // Ctrl+L pressed
+	NextNodeRequested   bool // Tab pressed
+	PrevNodeRequested   bool // Shift+Tab pressed
+	InspectRequested    bool // Space pressed
+	SettingsRequested   bool // Comma pressed
+	OpenFileRequested   bool // O pressed
+	BirdseyeRequested   bool // B pressed
+	CycleColorRequested bool // C pressed
+	TimeTravelRequested bool // T pressed
+	BreakdownRequested  bool // E pressed
+	FisheyeRequested    bool // M pressed
+	ExportRequested     bool // X pressed
 
 	// When true, keyboard input goes to a text field - skip camera/shortcut keys
 	TextInputActive bool
+
+	// When true, a 2D overlay (e.g. the timeline slider) owns the mouse this frame
+	OverlayCaptured bool
 }
 
 // NewInputState creates the input handler.
@@ -70,9 +78,14 @@ func (s *InputState) Update(graph *scene.Graph, sidebarWidth int32) string {
 	s.SettingsRequested = false
 	s.OpenFileRequested = false
 	s.BirdseyeRequested = false
+	s.CycleColorRequested = false
+	s.TimeTravelRequested = false
+	s.BreakdownRequested = false
+	s.FisheyeRequested = false
+	s.ExportRequested = false
 
 	mousePos := rl.GetMousePosition()
-	inViewport := mousePos.X > float32(sidebarWidth)
+	inViewport := mousePos.X > float32(sidebarWidth) && !s.OverlayCaptured
 
 	if inViewport {
 		// Camera always updates (handles animation + user input, matching fsnav)
@@ -89,6 +102,16 @@ func (s *InputState) Update(graph *scene.Graph, sidebarWidth int32) string {
 			s.leftPressX = mousePos.X
 			s.leftPressY = mousePos.Y
 			s.leftDragged = false
+
+			// Shift+drag draws a selection box instead of orbiting
+			if rl.IsKeyDown(rl.KeyLeftShift) || rl.IsKeyDown(rl.KeyRightShift) {
+				s.Picker.BoxActive = true
+				s.Picker.BoxStart = mousePos
+				s.Camera.RotateLocked = true
+			}
+		}
+		if s.Picker.BoxActive {
+			s.Picker.BoxEnd = mousePos
 		}
 		if rl.IsMouseButtonDown(rl.MouseButtonLeft) {
 			dx := mousePos.X - s.leftPressX
@@ -98,8 +121,15 @@ func (s *InputState) Update(graph *scene.Graph, sidebarWidth int32) string {
 			}
 		}
 
-		// Click = select on release without drag
-		if rl.IsMouseButtonReleased(rl.MouseButtonLeft) && !s.leftDragged {
+		// Box selection completes on release
+		if rl.IsMouseButtonReleased(rl.MouseButtonLeft) && s.Picker.BoxActive && s.leftDragged {
+			if graph != nil {
+				view := scene.NewView(s.Camera.Camera, int32(rl.GetScreenWidth()), int32(rl.GetScreenHeight()))
+				s.Picker.Selection = graph.SelectInRect(view, s.Picker.Box())
+			}
+		} else if rl.IsMouseButtonReleased(rl.MouseButtonLeft) && !s.leftDragged {
+			// Click = select on release without drag
+			s.Picker.Selection = nil
 			if graph != nil && s.Picker.HoveredNode != nil {
 				hit := s.Picker.HoveredNode
 				now := time.Now()
@@ -121,6 +151,11 @@ func (s *InputState) Update(graph *scene.Graph, sidebarWidth int32) string {
 		}
 	}
 
+	if !rl.IsMouseButtonDown(rl.MouseButtonLeft) {
+		s.Picker.BoxActive = false
+		s.Camera.RotateLocked = false
+	}
+
 	// Keyboard shortcuts (disabled when text input is active)
 	if !s.TextInputActive {
 		if s.Keys.IsPressed(ActionToggleHelp) {
@@ -164,14 +199,27 @@ func (s *InputState) Update(graph *scene.Graph, sidebarWidth int32) string {
 		if s.Keys.IsPressed(ActionBirdseye) {
 			s.BirdseyeRequested = true
 		}
+		if s.Keys.IsPressed(ActionCycleColor) {
+			s.CycleColorRequested = true
+		}
+		if s.Keys.IsPressed(ActionTimeTravel) {
+			s.TimeTravelRequested = true
+		}
+		if s.Keys.IsPressed(ActionBreakdown) {
+			s.BreakdownRequested = true
+		}
+		if s.Keys.IsPressed(ActionFisheye) {
+			s.FisheyeRequested = true
+		}
+		if s.Keys.IsPressed(ActionExport) {
+			s.ExportRequested = true
+		}
 	}
 
 	// Double-click: navigate to node
 	if s.doubleClicked && s.Picker.SelectedNode != nil {
 		s.FocusOnNode(s.Picker.SelectedNode)
-		if s.Picker.SelectedNode.Entry != nil {
-			return s.Picker.SelectedNode.Entry.Path
-		}
+		return s.Picker.SelectedNode.Path()
 	}
 
 	return ""
@@ -182,7 +230,7 @@ func (s *InputState) FocusOnNode(node *scene.SceneNode) {
 	if node == nil {
 		return
 	}
-	s.Camera.AnimateTo(node.Position)
+	s.Camera.AnimateTo(node.Center())
 }
 
 // FocusOnPath finds a node by path and animates to it.
@@ -30,6 +30,11 @@ const (
 	ActionSettings    Action = "settings"    // Comma: open settings menu
 	ActionOpenFile    Action = "open_file"   // O: open file with default app
 	ActionBirdseye    Action = "birdseye"   // B: birdseye view of all expanded dirs
+	ActionCycleColor  Action = "cycle_color" // C: cycle color mode (age/size/type/git)
+	ActionTimeTravel  Action = "time_travel" // T: toggle the time-travel slider
+	ActionBreakdown   Action = "breakdown"   // E: toggle the size breakdown panel
+	ActionFisheye     Action = "fisheye"     // M: toggle the fisheye around the selection
+	ActionExport      Action = "export"      // X: export the scene to a glTF, OBJ or HTML file
 )
 
 // KeyMap maps actions to raylib key codes.
@@ -59,6 +64,11 @@ func DefaultKeyMap() *KeyMap {
 			ActionSettings:   {rl.KeyComma},
 			ActionOpenFile:   {rl.KeyO},
 			ActionBirdseye:   {rl.KeyB},
+			ActionCycleColor: {rl.KeyC},
+			ActionTimeTravel: {rl.KeyT},
+			ActionBreakdown:  {rl.KeyE},
+			ActionFisheye:    {rl.KeyM},
+			ActionExport:     {rl.KeyX},
 		},
 	}
 }
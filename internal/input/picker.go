@@ -1,6 +1,7 @@
 package input
 
 import (
+	rl "github.com/gen2brain/raylib-go/raylib"
 	"github.com/Crank-Git/FSNRedux/internal/scene"
 )
 
@@ -8,6 +9,12 @@ import (
 type Picker struct {
 	SelectedNode *scene.SceneNode
 	HoveredNode  *scene.SceneNode
+
+	// Box selection (Shift+left-drag): nodes whose centers fall in the box
+	Selection []*scene.SceneNode
+	BoxActive bool
+	BoxStart  rl.Vector2
+	BoxEnd    rl.Vector2
 }
 
 // NewPicker creates a picker.
@@ -19,3 +26,9 @@ func NewPicker() *Picker {
 func (p *Picker) ClearSelection() {
 	p.SelectedNode = nil
 }
+
+// Box returns the normalized screen rectangle of the box selection.
+func (p *Picker) Box() rl.Rectangle {
+	x, y := min(p.BoxStart.X, p.BoxEnd.X), min(p.BoxStart.Y, p.BoxEnd.Y)
+	return rl.NewRectangle(x, y, max(p.BoxStart.X, p.BoxEnd.X)-x, max(p.BoxStart.Y, p.BoxEnd.Y)-y)
+}
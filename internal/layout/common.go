@@ -1,6 +1,12 @@
 package layout
 
-import "math"
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/color"
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+)
 
 // scaleHeight converts a file size to a visual height using logarithmic scaling.
 // This prevents massive files from dominating the view and tiny files from being invisible.
@@ -21,3 +27,10 @@ func scaleHeight(size int64, opts Options) float32 {
 	}
 	return h
 }
+
+// fileColor returns the color for a non-directory entry under the options' color
+// mapping; largest is the size of the largest file in its directory.
+func fileColor(entry *fs.Entry, largest int64, opts Options) rl.Color {
+	m := color.Mapping{Mode: opts.ColorMode, Age: opts.AgeScale, Time: opts.TimeField}
+	return m.Color(entry, largest)
+}
@@ -0,0 +1,196 @@
+package layout
+
+import (
+	"math"
+)
+
+// DefaultMagnify is how many times Fisheye enlarges the focused subtree
+// when there is room.
+const DefaultMagnify = 3
+
+// fisheyeRoom is the share of the scene on each side of the focus that the
+// magnified subtree may take, leaving the rest for its context.
+const fisheyeRoom = 0.8
+
+// Fisheye distorts a computed layout in place for focus+context: the
+// subtree at path focus is enlarged up to magnify times on the ground plane
+// and the rest of the scene is squeezed into the space left, the more the
+// further it lies from the focus. The scene keeps its extent. Box layouts
+// warp X and Z separately, sunburst sectors their angles and radii; both
+// warps preserve order, so nothing that was apart comes to overlap. Heights
+// are unchanged. It reports whether focus was found.
+func Fisheye(root *Node, focus string, magnify float32) bool {
+	if root == nil || focus == "" {
+		return false
+	}
+	f := findNode(root, focus)
+	if f == nil {
+		return false
+	}
+	if magnify <= 1 {
+		return true
+	}
+	if f.Sector != nil {
+		fisheyeSectors(root, f, magnify)
+	} else {
+		fisheyeBoxes(root, f, magnify)
+	}
+	return true
+}
+
+// findNode returns the node at path under n, or nil.
+func findNode(n *Node, path string) *Node {
+	if n.Path() == path {
+		return n
+	}
+	for _, c := range n.Children {
+		if isPathWithin(path, c.Path()) {
+			if found := findNode(c, path); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+// fisheyeBoxes warps box layouts along X and Z.
+func fisheyeBoxes(root, focus *Node, magnify float32) {
+	lo, hi := footprint(root)
+	flo, fhi := footprint(focus)
+	wx := newWarp(lo[0], hi[0], flo[0], fhi[0], magnify)
+	wz := newWarp(lo[1], hi[1], flo[1], fhi[1], magnify)
+	if wx.scale == 1 && wz.scale == 1 {
+		return
+	}
+	walk(root, func(n *Node) {
+		minX, maxX := wx.at(n.Position.X-n.Size.X/2), wx.at(n.Position.X+n.Size.X/2)
+		minZ, maxZ := wz.at(n.Position.Z-n.Size.Z/2), wz.at(n.Position.Z+n.Size.Z/2)
+		n.Position.X, n.Size.X = (minX+maxX)/2, maxX-minX
+		n.Position.Z, n.Size.Z = (minZ+maxZ)/2, maxZ-minZ
+	})
+}
+
+// fisheyeSectors warps sunburst sectors around the focus segment: angles
+// over the full turn centered on its middle, radii from the axis out.
+func fisheyeSectors(root, focus *Node, magnify float32) {
+	s := focus.Sector
+	var outer, focusOuter float32
+	walk(root, func(n *Node) {
+		if n.Sector != nil {
+			outer = max(outer, n.Sector.Outer)
+		}
+	})
+	walk(focus, func(n *Node) {
+		if n.Sector != nil {
+			focusOuter = max(focusOuter, n.Sector.Outer)
+		}
+	})
+
+	mid := s.Start + s.Sweep/2
+	angle := newWarp(mid-math.Pi, mid+math.Pi, s.Start, s.Start+s.Sweep, magnify)
+	radius := newWarp(0, outer, s.Inner, focusOuter, magnify)
+	if angle.scale == 1 && radius.scale == 1 {
+		return
+	}
+
+	walk(root, func(n *Node) {
+		if n.Sector == nil {
+			return
+		}
+		w := *n.Sector
+		if w.Sweep < 2*math.Pi && angle.scale != 1 {
+			start := angle.around(w.Start)
+			w.Sweep = angle.around(w.Start+w.Sweep) - start
+			w.Start = start
+		}
+		w.Inner, w.Outer = radius.at(w.Inner), radius.at(w.Outer)
+		n.Sector = &w
+		minX, minZ, maxX, maxZ := w.Extent()
+		n.Position.X, n.Size.X = (minX+maxX)/2, maxX-minX
+		n.Position.Z, n.Size.Z = (minZ+maxZ)/2, maxZ-minZ
+	})
+}
+
+// footprint returns the ground rectangle enclosing n and its descendants.
+func footprint(n *Node) (lo, hi [2]float32) {
+	lo = [2]float32{float32(math.Inf(1)), float32(math.Inf(1))}
+	hi = [2]float32{float32(math.Inf(-1)), float32(math.Inf(-1))}
+	walk(n, func(n *Node) {
+		lo[0] = min(lo[0], n.Position.X-n.Size.X/2)
+		lo[1] = min(lo[1], n.Position.Z-n.Size.Z/2)
+		hi[0] = max(hi[0], n.Position.X+n.Size.X/2)
+		hi[1] = max(hi[1], n.Position.Z+n.Size.Z/2)
+	})
+	return lo, hi
+}
+
+// walk calls fn for n and every node below it.
+func walk(n *Node, fn func(*Node)) {
+	fn(n)
+	for _, c := range n.Children {
+		walk(c, fn)
+	}
+}
+
+// warp is a monotonic map of one axis onto itself that keeps [lo, hi]
+// fixed, scales the focus interval [a, b] and squeezes the context on
+// either side of it into the space left, in proportion to its length.
+type warp struct {
+	lo, hi, a, b float32
+	scale        float32 // focus magnification (1 = identity)
+	a2           float32 // where a goes
+}
+
+// newWarp scales [a, b] by up to magnify, leaving at least 1-fisheyeRoom of
+// the axis for the context.
+func newWarp(lo, hi, a, b, magnify float32) warp {
+	w := warp{lo: lo, hi: hi, a: a, b: b, scale: 1}
+	length, focus := hi-lo, b-a
+	if !(focus > 0 && length > focus) { // also catches NaN from empty extents
+		return w
+	}
+	focus2 := min(magnify*focus, fisheyeRoom*length)
+	if focus2 <= focus {
+		return w
+	}
+	w.scale = focus2 / focus
+	w.a2 = lo + (a-lo)*(length-focus2)/(length-focus)
+	return w
+}
+
+// at maps x, which lies within [lo, hi].
+func (w warp) at(x float32) float32 {
+	if w.scale == 1 {
+		return x
+	}
+	b2 := w.a2 + (w.b-w.a)*w.scale
+	switch {
+	case x >= w.a && x <= w.b:
+		return w.a2 + (x-w.a)*w.scale
+	case x < w.a:
+		return w.a2 - squeeze(w.a-x, w.a-w.lo, w.a2-w.lo)
+	default:
+		return b2 + squeeze(x-w.b, w.hi-w.b, w.hi-b2)
+	}
+}
+
+// around maps an angle on a warp spanning one full turn, extended
+// periodically so that it applies to any angle.
+func (w warp) around(x float32) float32 {
+	turn := w.hi - w.lo
+	k := float32(math.Floor(float64((x - w.lo) / turn)))
+	return w.at(x-k*turn) + k*turn
+}
+
+// squeeze maps distance d from the focus, out of a context of length from,
+// into one of length to. Context next to the focus keeps its scale and the
+// squeeze grows with distance (a Sarkar-Brown fisheye curve).
+func squeeze(d, from, to float32) float32 {
+	if from <= 0 || to >= from {
+		return d * to / max(from, 1e-6)
+	}
+	k := float64(to / from)
+	u := float64(d / from)
+	g := (1 / k) * u / ((1/k-1)*u + 1)
+	return float32(g) * to
+}
@@ -0,0 +1,152 @@
+package layout
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+)
+
+var allModes = []Mode{ModeTreeV, ModeMapV, ModeRadial, ModeSunburst}
+
+// focusDir returns a directory two levels down, a typical focus.
+func focusDir(tree *fs.Tree) string {
+	for _, c := range tree.Root.Children {
+		if c.IsDir() {
+			for _, g := range c.Children {
+				if g.IsDir() {
+					return g.Path
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// area is the ground area of the footprint of n's subtree.
+func area(n *Node) float32 {
+	lo, hi := footprint(n)
+	return (hi[0] - lo[0]) * (hi[1] - lo[1])
+}
+
+// outerRadius is the outer radius of the widest sunburst ring.
+func outerRadius(n *Node) float32 {
+	var r float32
+	walk(n, func(n *Node) { r = max(r, n.Sector.Outer) })
+	return r
+}
+
+// nodes lists n and its descendants in layout order.
+func nodes(n *Node) []*Node {
+	var all []*Node
+	walk(n, func(n *Node) { all = append(all, n) })
+	return all
+}
+
+func TestFisheye_EnlargesFocusAndKeepsExtent(t *testing.T) {
+	tree := fs.SyntheticTree(2000, 10, 3)
+	focus := focusDir(tree)
+	for _, mode := range allModes {
+		opts := DefaultOptions(mode)
+		plain := Compute(tree, opts)
+		opts.Focus, opts.Magnify = focus, DefaultMagnify
+		warped := Compute(tree, opts)
+
+		before, after := findNode(plain, focus), findNode(warped, focus)
+		if before == nil || after == nil {
+			t.Fatalf("%s: focus %s not laid out", mode, focus)
+		}
+		if r := area(after) / area(before); r < 2 {
+			t.Errorf("%s: focus subtree grew %.2fx in area, want at least 2x", mode, r)
+		}
+		if mode == ModeSunburst {
+			if a, b := outerRadius(plain), outerRadius(warped); a != b {
+				t.Errorf("%s: outer radius %v became %v", mode, a, b)
+			}
+			continue
+		}
+		lo0, hi0 := footprint(plain)
+		lo1, hi1 := footprint(warped)
+		for i := range lo0 {
+			if math.Abs(float64(lo1[i]-lo0[i])) > 1e-3 || math.Abs(float64(hi1[i]-hi0[i])) > 1e-3 {
+				t.Errorf("%s: scene extent %v..%v became %v..%v", mode, lo0, hi0, lo1, hi1)
+				break
+			}
+		}
+	}
+}
+
+func TestFisheye_CompressesContextWithoutOverlap(t *testing.T) {
+	tree := fs.SyntheticTree(600, 10, 3)
+	focus := focusDir(tree)
+	for _, mode := range []Mode{ModeTreeV, ModeMapV, ModeRadial} {
+		opts := DefaultOptions(mode)
+		plain := nodes(Compute(tree, opts))
+		opts.Focus, opts.Magnify = focus, DefaultMagnify
+		warped := nodes(Compute(tree, opts))
+		if len(plain) != len(warped) {
+			t.Fatalf("%s: %d nodes became %d", mode, len(plain), len(warped))
+		}
+
+		shrunk := 0
+		for i, a := range plain {
+			b := warped[i]
+			if b.Size.X*b.Size.Z < a.Size.X*a.Size.Z*0.99 {
+				shrunk++
+			}
+			// Boxes apart along an axis stay apart along it
+			for j := i + 1; j < len(plain); j++ {
+				c, d := plain[j], warped[j]
+				if a.Position.X+a.Size.X/2 <= c.Position.X-c.Size.X/2 && b.Position.X+b.Size.X/2 > d.Position.X-d.Size.X/2+1e-3 {
+					t.Fatalf("%s: %s and %s overlap in X after the warp", mode, a.Path(), c.Path())
+				}
+				if a.Position.Z+a.Size.Z/2 <= c.Position.Z-c.Size.Z/2 && b.Position.Z+b.Size.Z/2 > d.Position.Z-d.Size.Z/2+1e-3 {
+					t.Fatalf("%s: %s and %s overlap in Z after the warp", mode, a.Path(), c.Path())
+				}
+			}
+		}
+		if shrunk == 0 {
+			t.Errorf("%s: no context node was compressed", mode)
+		}
+	}
+}
+
+func TestFisheye_SunburstRingsStillTile(t *testing.T) {
+	tree := fs.SyntheticTree(2000, 10, 3)
+	opts := DefaultOptions(ModeSunburst)
+	opts.Focus, opts.Magnify = focusDir(tree), DefaultMagnify
+	root := Compute(tree, opts)
+	walk(root, func(n *Node) {
+		if n.Sector == nil || len(n.Children) == 0 {
+			return
+		}
+		var sweep float32
+		for _, c := range n.Children {
+			if c.Sector.Sweep <= 0 || c.Sector.Inner < n.Sector.Outer-1e-3 {
+				t.Fatalf("%s: child %s has sweep %v from radius %v", n.Path(), c.Path(), c.Sector.Sweep, c.Sector.Inner)
+			}
+			sweep += c.Sector.Sweep
+		}
+		if sweep > n.Sector.Sweep+1e-3 {
+			t.Errorf("%s: children sweep %v, more than their parent's %v", n.Path(), sweep, n.Sector.Sweep)
+		}
+	})
+}
+
+func TestFisheye_NoOpWithoutRoom(t *testing.T) {
+	tree := fs.SyntheticTree(300, 10, 2)
+	for _, mode := range allModes {
+		opts := DefaultOptions(mode)
+		want := nodes(Compute(tree, opts))
+		for _, focus := range []string{tree.Root.Path, "/no/such/dir"} {
+			opts.Focus, opts.Magnify = focus, DefaultMagnify
+			got := nodes(Compute(tree, opts))
+			for i := range want {
+				if got[i].Position != want[i].Position || got[i].Size != want[i].Size {
+					t.Errorf("%s: focus %s moved %s", mode, focus, got[i].Path())
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package layout
+
+import (
+	"bytes"
+	"flag"
+	"image"
+	imgcolor "image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/Crank-Git/FSNRedux/internal/color"
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+)
+
+var update = flag.Bool("update", false, "rewrite golden images in testdata")
+
+// TestCompute_FootprintGolden compares top-down footprint images of fully
+// expanded synthetic tree layouts against testdata/footprint_*.png. Run with
+// -update after an intended layout change and review the new images.
+func TestCompute_FootprintGolden(t *testing.T) {
+	for _, mode := range []Mode{ModeTreeV, ModeMapV, ModeRadial, ModeSunburst} {
+		t.Run(mode.String(), func(t *testing.T) {
+			tree := fs.SyntheticTree(300, 20, 4)
+			opts := DefaultOptions(mode)
+			opts.ColorMode = color.ModeSize
+			got := rasterizeFootprints(Compute(tree, opts), 256, 256)
+
+			golden := filepath.Join("testdata", "footprint_"+mode.String()+".png")
+			if *update {
+				var buf bytes.Buffer
+				if err := png.Encode(&buf, got); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.MkdirAll("testdata", 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(golden, buf.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			f, err := os.Open(golden)
+			if err != nil {
+				t.Fatalf("%v (run go test -update to create it)", err)
+			}
+			defer f.Close()
+			want, err := png.Decode(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := countDiff(got, want); diff > 0 {
+				t.Errorf("%d pixels differ from %s", diff, golden)
+			}
+		})
+	}
+}
+
+func TestRasterizeFootprints_NilRootIsGround(t *testing.T) {
+	img := rasterizeFootprints(nil, 8, 8)
+	if c := img.RGBAAt(4, 4); c.R != color.Ground.R || c.G != color.Ground.G || c.B != color.Ground.B {
+		t.Errorf("pixel = %v, want ground color", c)
+	}
+}
+
+// countDiff returns the number of pixels that differ between two images,
+// or the pixel count of a when their sizes differ.
+func countDiff(a *image.RGBA, b image.Image) int {
+	if a.Bounds() != b.Bounds() {
+		return a.Bounds().Dx() * a.Bounds().Dy()
+	}
+	n := 0
+	for y := a.Bounds().Min.Y; y < a.Bounds().Max.Y; y++ {
+		for x := a.Bounds().Min.X; x < a.Bounds().Max.X; x++ {
+			r1, g1, b1, a1 := a.At(x, y).RGBA()
+			r2, g2, b2, a2 := b.At(x, y).RGBA()
+			if r1 != r2 || g1 != g2 || b1 != b2 || a1 != a2 {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// rasterMargin is the blank border around a rasterized layout, in pixels.
+const rasterMargin = 4
+
+// rasterizeFootprints draws a layout top-down (orthographic, looking along
+// -Y) for the footprint goldens. Each cuboid's footprint (or sunburst
+// segment's sector) is filled with its color, shaded by the height of its
+// top, and taller nodes cover lower ones.
+func rasterizeFootprints(root *Node, width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	bg := imgcolor.RGBA{color.Ground.R, color.Ground.G, color.Ground.B, 255}
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = bg.R, bg.G, bg.B, bg.A
+	}
+	if root == nil {
+		return img
+	}
+
+	var nodes []*Node
+	var collect func(n *Node)
+	collect = func(n *Node) {
+		nodes = append(nodes, n)
+		for _, c := range n.Children {
+			collect(c)
+		}
+	}
+	collect(root)
+
+	// Fit the footprint of every box into the image, keeping the aspect ratio
+	minX, minZ := nodes[0].Position.X, nodes[0].Position.Z
+	maxX, maxZ, maxTop := minX, minZ, float32(0)
+	for _, n := range nodes {
+		minX = min(minX, n.Position.X-n.Size.X/2)
+		maxX = max(maxX, n.Position.X+n.Size.X/2)
+		minZ = min(minZ, n.Position.Z-n.Size.Z/2)
+		maxZ = max(maxZ, n.Position.Z+n.Size.Z/2)
+		maxTop = max(maxTop, top(n))
+	}
+	scale := min(float32(width-2*rasterMargin)/max(maxX-minX, 1e-3), float32(height-2*rasterMargin)/max(maxZ-minZ, 1e-3))
+
+	// Painter's algorithm: lowest tops first; ties in tree order
+	sort.SliceStable(nodes, func(i, j int) bool { return top(nodes[i]) < top(nodes[j]) })
+	for _, n := range nodes {
+		x0 := rasterMargin + int((n.Position.X-n.Size.X/2-minX)*scale)
+		x1 := rasterMargin + int((n.Position.X+n.Size.X/2-minX)*scale)
+		y0 := rasterMargin + int((n.Position.Z-n.Size.Z/2-minZ)*scale)
+		y1 := rasterMargin + int((n.Position.Z+n.Size.Z/2-minZ)*scale)
+		if x1 <= x0 {
+			x1 = x0 + 1
+		}
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+
+		shade := float32(1)
+		if maxTop > 0 {
+			shade = 0.55 + 0.45*top(n)/maxTop
+		}
+		fill := imgcolor.RGBA{
+			uint8(float32(n.Color.R) * shade),
+			uint8(float32(n.Color.G) * shade),
+			uint8(float32(n.Color.B) * shade),
+			255,
+		}
+		edge := imgcolor.RGBA{fill.R / 2, fill.G / 2, fill.B / 2, 255}
+		if n.Sector != nil {
+			// Fill the pixels whose centers fall inside the sector
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					wx := minX + (float32(x-rasterMargin)+0.5)/scale
+					wz := minZ + (float32(y-rasterMargin)+0.5)/scale
+					if n.Sector.Contains(wx, wz) {
+						img.SetRGBA(x, y, fill)
+					}
+				}
+			}
+			continue
+		}
+		for y := y0; y < y1; y++ {
+			for x := x0; x < x1; x++ {
+				c := fill
+				if x == x0 || x == x1-1 || y == y0 || y == y1-1 {
+					c = edge
+				}
+				img.SetRGBA(x, y, c)
+			}
+		}
+	}
+	return img
+}
+
+// top is the height of a layout box's upper face.
+func top(n *Node) float32 {
+	return n.Position.Y + n.Size.Y/2
+}
@@ -0,0 +1,62 @@
+package layout
+
+import "fmt"
+
+// Geometry holds the sizes and spacings of the layouts. The pedestal fields
+// apply to TreeV and Radial and default to fsnav's values.
+type Geometry struct {
+	PaddingRatio float32 // MapV: spacing between sibling cuboids, as a fraction of their rect
+	HeightScale  float32 // multiplier for file-size-to-height mapping
+	MinHeight    float32 // minimum cuboid height
+	MaxHeight    float32 // maximum cuboid height
+
+	FileSize     float32 // side of a file tile on a pedestal
+	FileSpacing  float32 // gap between file tiles and around the grid
+	FileHeight   float32 // height of a file tile (unless FileHeights)
+	DirSize      float32 // minimum pedestal side (fsnav: 0.5 + 0.2)
+	DirSpacing   float32 // gap between neighboring subtrees in a row
+	DirHeight    float32 // pedestal thickness
+	DirDist      float32 // distance from a pedestal to its row of subdirectories
+	WrapDirs     int     // TreeV: more subdirectories than this wrap into a grid of rows
+	MinFootprint float32 // smallest scaled file tile, as a fraction of FileSize
+}
+
+// DefaultGeometry returns the default sizes, matching fsnav for pedestals.
+func DefaultGeometry() Geometry {
+	return Geometry{
+		PaddingRatio: 0.02,
+		HeightScale:  1.0,
+		MinHeight:    0.1,
+		MaxHeight:    20.0,
+		FileSize:     0.5,
+		FileSpacing:  0.1,
+		FileHeight:   0.1,
+		DirSize:      0.7,
+		DirSpacing:   0.5,
+		DirHeight:    0.1,
+		DirDist:      5.0,
+		WrapDirs:     16,
+		MinFootprint: 0.3,
+	}
+}
+
+// Validate reports the first value that would break a layout.
+func (g Geometry) Validate() error {
+	switch {
+	case g.PaddingRatio < 0 || g.PaddingRatio >= 0.5:
+		return fmt.Errorf("padding ratio %g outside [0, 0.5)", g.PaddingRatio)
+	case g.HeightScale <= 0:
+		return fmt.Errorf("height scale %g must be positive", g.HeightScale)
+	case g.MinHeight <= 0 || g.MaxHeight < g.MinHeight:
+		return fmt.Errorf("heights need 0 < min (%g) <= max (%g)", g.MinHeight, g.MaxHeight)
+	case g.FileSize <= 0 || g.FileHeight <= 0 || g.DirSize <= 0 || g.DirHeight <= 0:
+		return fmt.Errorf("file and pedestal sizes must be positive")
+	case g.FileSpacing < 0 || g.DirSpacing < 0 || g.DirDist < 0:
+		return fmt.Errorf("spacings must not be negative")
+	case g.WrapDirs < 1:
+		return fmt.Errorf("wrap dirs %d must be at least 1", g.WrapDirs)
+	case g.MinFootprint <= 0 || g.MinFootprint > 1:
+		return fmt.Errorf("min footprint %g outside (0, 1]", g.MinFootprint)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package layout
+
+import (
+	"path/filepath"
+	"sort"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/color"
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+)
+
+// Small-file grouping defaults (see Options.GroupFiles and GroupBelow).
+const (
+	DefaultGroupFiles = 500      // file tiles per directory before grouping kicks in
+	DefaultGroupBelow = 4 * 1024 // files under this size are grouped once it does
+)
+
+// groupName is the last element of a group's path. It holds a NUL byte, so
+// it can never name a real file.
+const groupName = "\x00small files"
+
+// Group is a synthetic node standing in for many small files of one
+// directory ("1,203 files, 4.1 MB"). It has no fs.Entry of its own.
+type Group struct {
+	Dir     *fs.Entry   // directory the files belong to
+	Members []*fs.Entry // grouped files, largest first
+	Size    int64       // total size of the members
+}
+
+// GroupPath returns the path that identifies the group of small files in
+// dir. Adding it to Options.ExpandedPaths shows the members individually.
+func GroupPath(dir string) string {
+	return filepath.Join(dir, groupName)
+}
+
+// IsGroupPath reports whether path names a group rather than an entry.
+func IsGroupPath(path string) bool {
+	return filepath.Base(path) == groupName
+}
+
+// Path returns the group's path (see GroupPath).
+func (g *Group) Path() string {
+	return GroupPath(g.Dir.Path)
+}
+
+// Path identifies the node across layouts: its entry's path, or its
+// group's. It is empty for nodes with neither.
+func (n *Node) Path() string {
+	switch {
+	case n.Entry != nil:
+		return n.Entry.Path
+	case n.Group != nil:
+		return n.Group.Path()
+	}
+	return ""
+}
+
+// splitFiles returns the files of dir to lay out one by one and the group
+// standing in for the rest, if any. Grouping applies once dir holds more
+// than opts.GroupFiles files and its group is not expanded: the largest
+// files keep their own tiles, one tile is left for the group, and files
+// under opts.GroupBelow join the group regardless. Files keep their order.
+func splitFiles(dir *fs.Entry, opts Options) ([]*fs.Entry, *Group) {
+	var files []*fs.Entry
+	for _, child := range dir.Children {
+		if child.Type != fs.TypeDir {
+			files = append(files, child)
+		}
+	}
+	if opts.GroupFiles <= 0 || len(files) <= opts.GroupFiles || opts.ExpandedPaths[GroupPath(dir.Path)] {
+		return files, nil
+	}
+
+	bySize := make([]*fs.Entry, len(files))
+	copy(bySize, files)
+	sort.SliceStable(bySize, func(i, j int) bool { return bySize[i].Size > bySize[j].Size })
+
+	keep := make(map[*fs.Entry]bool, opts.GroupFiles)
+	for _, f := range bySize[:opts.GroupFiles-1] {
+		if f.Size >= opts.GroupBelow {
+			keep[f] = true
+		}
+	}
+	g := &Group{Dir: dir}
+	for _, f := range bySize {
+		if !keep[f] {
+			g.Members = append(g.Members, f)
+			g.Size += max(f.Size, 0)
+		}
+	}
+	if len(g.Members) < 2 {
+		return files, nil
+	}
+
+	shown := files[:0:0]
+	for _, f := range files {
+		if keep[f] {
+			shown = append(shown, f)
+		}
+	}
+	return shown, g
+}
+
+// fileCells returns how many file tiles a directory's pedestal holds.
+func fileCells(dir *fs.Entry, opts Options) int {
+	files, g := splitFiles(dir, opts)
+	if g != nil {
+		return len(files) + 1
+	}
+	return len(files)
+}
+
+// groupColor is the mean color of the group's members.
+func groupColor(g *Group, opts Options) rl.Color {
+	largest := color.LargestFile(g.Dir.Children)
+	var r, gr, b, a uint64
+	for _, f := range g.Members {
+		c := fileColor(f, largest, opts)
+		r += uint64(c.R)
+		gr += uint64(c.G)
+		b += uint64(c.B)
+		a += uint64(c.A)
+	}
+	n := uint64(max(len(g.Members), 1))
+	return rl.NewColor(uint8(r/n), uint8(gr/n), uint8(b/n), uint8(a/n))
+}
@@ -0,0 +1,134 @@
+package layout
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+)
+
+// crowdedDir is a directory of n one-KB files plus a few big ones.
+func crowdedDir(n int) *fs.Tree {
+	root := &fs.Entry{Name: "crowd", Type: fs.TypeDir, Path: "/crowd", Loaded: true}
+	for i := 0; i < n; i++ {
+		root.Children = append(root.Children, &fs.Entry{
+			Name: fmt.Sprintf("f%05d", i), Path: fmt.Sprintf("/crowd/f%05d", i),
+			Type: fs.TypeFile, Size: 1 << 10, Depth: 1,
+		})
+	}
+	for i := 0; i < 3; i++ {
+		root.Children = append(root.Children, &fs.Entry{
+			Name: fmt.Sprintf("big%d", i), Path: fmt.Sprintf("/crowd/big%d", i),
+			Type: fs.TypeFile, Size: 1 << 30, Depth: 1,
+		})
+	}
+	sub := &fs.Entry{Name: "sub", Type: fs.TypeDir, Path: "/crowd/sub", Size: 1 << 30, Depth: 1}
+	root.Children = append(root.Children, sub)
+	root.Size = int64(n)<<10 + 4<<30
+	return &fs.Tree{Root: root}
+}
+
+// groupsIn returns the group nodes under n.
+func groupsIn(n *Node) []*Node {
+	var found []*Node
+	if n.Group != nil {
+		found = append(found, n)
+	}
+	for _, c := range n.Children {
+		found = append(found, groupsIn(c)...)
+	}
+	return found
+}
+
+func TestGroup_FewFilesAreNotGrouped(t *testing.T) {
+	for _, mode := range []Mode{ModeTreeV, ModeMapV, ModeRadial, ModeSunburst} {
+		result := Compute(crowdedDir(100), DefaultOptions(mode))
+		if g := groupsIn(result); len(g) != 0 {
+			t.Errorf("%s: 103 files grouped into %d nodes", mode, len(g))
+		}
+	}
+}
+
+func TestGroup_SmallFilesCollapseIntoOneNode(t *testing.T) {
+	tree := crowdedDir(50000)
+	for _, mode := range []Mode{ModeTreeV, ModeMapV, ModeRadial, ModeSunburst} {
+		result := Compute(tree, DefaultOptions(mode))
+		groups := groupsIn(result)
+		if len(groups) != 1 {
+			t.Fatalf("%s: got %d group nodes, want 1", mode, len(groups))
+		}
+		g := groups[0]
+		if g.Entry != nil || len(g.Children) != 0 {
+			t.Errorf("%s: group node has entry %v and %d children", mode, g.Entry, len(g.Children))
+		}
+		if g.Path() != GroupPath("/crowd") || !IsGroupPath(g.Path()) {
+			t.Errorf("%s: group path %q", mode, g.Path())
+		}
+		if len(g.Group.Members) != 50000 || g.Group.Size != 50000<<10 {
+			t.Errorf("%s: group has %d members of %d bytes, want every small file", mode, len(g.Group.Members), g.Group.Size)
+		}
+		if len(result.Children) != 5 {
+			t.Errorf("%s: root has %d children, want 3 big files, the group and sub", mode, len(result.Children))
+		}
+	}
+}
+
+func TestGroup_CountThresholdKeepsLargestFiles(t *testing.T) {
+	opts := DefaultOptions(ModeTreeV)
+	opts.GroupFiles = 10
+	opts.GroupBelow = 0
+	result := Compute(crowdedDir(50), opts)
+
+	files := 0
+	for _, c := range result.Children {
+		if c.Entry != nil && !c.Entry.IsDir() {
+			files++
+		}
+	}
+	groups := groupsIn(result)
+	if files != 9 || len(groups) != 1 {
+		t.Fatalf("got %d file tiles and %d groups, want 9 and 1", files, len(groups))
+	}
+	if n := len(groups[0].Group.Members); n != 44 {
+		t.Errorf("group has %d members, want 44", n)
+	}
+	for _, m := range groups[0].Group.Members {
+		if m.Size > 1<<10 {
+			t.Errorf("%s (%d bytes) grouped while smaller files kept tiles", m.Name, m.Size)
+		}
+	}
+}
+
+func TestGroup_ExpandingShowsMembers(t *testing.T) {
+	tree := crowdedDir(1000)
+	opts := DefaultOptions(ModeTreeV)
+	opts.ExpandedPaths = map[string]bool{"/crowd": true}
+	grouped := Compute(tree, opts)
+
+	opts.ExpandedPaths[GroupPath("/crowd")] = true
+	expanded := Compute(tree, opts)
+	if g := groupsIn(expanded); len(g) != 0 {
+		t.Fatalf("expanded group still laid out as %d nodes", len(g))
+	}
+	if len(expanded.Children) != 1004 {
+		t.Errorf("expanded dir has %d children, want every file and sub", len(expanded.Children))
+	}
+	if expanded.Size.X <= grouped.Size.X {
+		t.Errorf("pedestal did not grow for the members: %v -> %v", grouped.Size.X, expanded.Size.X)
+	}
+}
+
+func TestGroup_IncrementalFollowsGroupExpansion(t *testing.T) {
+	tree := crowdedDir(1000)
+	opts := DefaultOptions(ModeTreeV)
+	opts.ExpandedPaths = map[string]bool{"/crowd": true}
+	inc := NewIncremental()
+	inc.Compute(tree, opts)
+
+	opts.ExpandedPaths[GroupPath("/crowd")] = true
+	got := inc.Compute(tree, opts)
+	want := Compute(tree, opts)
+	if got.Size != want.Size || len(got.Children) != len(want.Children) {
+		t.Errorf("incremental pedestal %v with %d children, full %v with %d", got.Size, len(got.Children), want.Size, len(want.Children))
+	}
+}
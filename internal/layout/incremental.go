@@ -0,0 +1,180 @@
+package layout
+
+import (
+	"strings"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/color"
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+)
+
+// Incremental computes TreeV layouts across expand/collapse changes. Directory
+// bounds and placed subtrees are cached between calls. Only directories whose
+// expansion changed, plus their ancestors, whose widths depend on them, are
+// re-measured, and only those and the subtrees they move are placed again:
+// every other directory comes back as the same *Node as last time, so
+// callers can tell it is unchanged by pointer. Other modes, a different tree,
+// or changed geometry options fall back to a full recompute; a fisheye focus
+// distorts the nodes in place, so it turns off the reuse of placed subtrees.
+//
+// Returned layouts share nodes with each other and must not be modified.
+type Incremental struct {
+	tree     *fs.Tree
+	shape    shapeKey
+	paint    paintKey
+	expanded map[string]bool
+	bounds   map[*fs.Entry]*dirBounds
+	placed   map[*fs.Entry]*placedDir
+}
+
+// shapeKey holds the options that affect TreeV bounds.
+type shapeKey struct {
+	mode       Mode
+	maxDepth   int
+	sort       fs.SortMode
+	groupFiles int
+	groupBelow int64
+	geometry   Geometry
+	stable     bool
+}
+
+// paintKey holds the options that affect placed nodes but not bounds.
+type paintKey struct {
+	colorMode     color.Mode
+	timeField     fs.TimeField
+	reference     time.Time
+	buckets       *color.AgeBucket
+	numBuckets    int
+	fileHeights   bool
+	fileFootprint bool
+}
+
+func paintOf(opts Options) paintKey {
+	k := paintKey{
+		colorMode:     opts.ColorMode,
+		timeField:     opts.TimeField,
+		reference:     opts.AgeScale.Reference,
+		numBuckets:    len(opts.AgeScale.Buckets),
+		fileHeights:   opts.FileHeights,
+		fileFootprint: opts.FileFootprint,
+	}
+	if k.numBuckets > 0 {
+		k.buckets = &opts.AgeScale.Buckets[0]
+	}
+	return k
+}
+
+func shapeOf(opts Options) shapeKey {
+	return shapeKey{
+		mode:       opts.Mode,
+		maxDepth:   opts.MaxDepth,
+		sort:       opts.Sort,
+		groupFiles: opts.GroupFiles,
+		groupBelow: opts.GroupBelow,
+		geometry:   opts.Geometry,
+		stable:     opts.Stable,
+	}
+}
+
+// NewIncremental creates an empty layout cache.
+func NewIncremental() *Incremental {
+	return &Incremental{}
+}
+
+// Compute returns the layout of tree under opts, reusing cached bounds
+// where the expansion state is unchanged.
+func (inc *Incremental) Compute(tree *fs.Tree, opts Options) *Node {
+	if tree == nil || tree.Root == nil {
+		return nil
+	}
+	if opts.Mode != ModeTreeV || opts.ExpandedPaths == nil {
+		inc.Reset()
+		return Compute(tree, opts)
+	}
+
+	if inc.bounds == nil || tree != inc.tree || shapeOf(opts) != inc.shape {
+		inc.tree = tree
+		inc.shape = shapeOf(opts)
+		inc.bounds = make(map[*fs.Entry]*dirBounds)
+		inc.placed = make(map[*fs.Entry]*placedDir)
+	} else {
+		for path := range inc.expanded {
+			if !opts.ExpandedPaths[path] {
+				inc.invalidate(path)
+			}
+		}
+		for path, on := range opts.ExpandedPaths {
+			if on && !inc.expanded[path] {
+				inc.invalidate(path)
+			}
+		}
+	}
+
+	inc.expanded = make(map[string]bool, len(opts.ExpandedPaths))
+	for path, on := range opts.ExpandedPaths {
+		if on {
+			inc.expanded[path] = true
+		}
+	}
+
+	if paintOf(opts) != inc.paint {
+		inc.paint = paintOf(opts)
+		inc.placed = make(map[*fs.Entry]*placedDir)
+	}
+	placed := inc.placed
+	if opts.Focus != "" && opts.Magnify > 1 {
+		placed = nil
+		inc.placed = make(map[*fs.Entry]*placedDir)
+	}
+
+	calcBounds(tree.Root, inc.bounds, opts)
+	root := place(tree.Root, rl.NewVector3(0, opts.DirHeight/2, 0), inc.bounds, placed, opts)
+	Fisheye(root, opts.Focus, opts.Magnify)
+	return root
+}
+
+// Invalidate drops the cached bounds and placement of the directory at path
+// and its ancestors, e.g. after its children were reloaded.
+func (inc *Incremental) Invalidate(path string) {
+	if inc.bounds != nil {
+		inc.invalidate(path)
+	}
+}
+
+// Reset empties the cache.
+func (inc *Incremental) Reset() {
+	inc.tree = nil
+	inc.expanded = nil
+	inc.bounds = nil
+	inc.placed = nil
+}
+
+// invalidate walks from the root down to path, dropping bounds and placed
+// subtrees on the way.
+func (inc *Incremental) invalidate(path string) {
+	entry := inc.tree.Root
+	for entry != nil {
+		delete(inc.bounds, entry)
+		delete(inc.placed, entry)
+		if entry.Path == path {
+			return
+		}
+		var next *fs.Entry
+		for _, child := range entry.Children {
+			if child.Type == fs.TypeDir && isPathWithin(path, child.Path) {
+				next = child
+				break
+			}
+		}
+		entry = next
+	}
+}
+
+// isPathWithin reports whether path is dir or inside it.
+func isPathWithin(path, dir string) bool {
+	if path == dir {
+		return true
+	}
+	return strings.HasPrefix(path, dir) && len(path) > len(dir) && (path[len(dir)] == '/' || path[len(dir)] == '\\')
+}
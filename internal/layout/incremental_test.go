@@ -0,0 +1,129 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+)
+
+// dirPaths lists every directory path under entry in pre-order.
+func dirPaths(entry *fs.Entry) []string {
+	if !entry.IsDir() {
+		return nil
+	}
+	paths := []string{entry.Path}
+	for _, child := range entry.Children {
+		paths = append(paths, dirPaths(child)...)
+	}
+	return paths
+}
+
+// sameLayout reports the first path where two layouts differ, or "".
+func sameLayout(a, b *Node) string {
+	if a.Entry != b.Entry || a.Position != b.Position || a.Size != b.Size || len(a.Children) != len(b.Children) {
+		return a.Entry.Path
+	}
+	for i := range a.Children {
+		if p := sameLayout(a.Children[i], b.Children[i]); p != "" {
+			return p
+		}
+	}
+	return ""
+}
+
+func TestIncremental_MatchesFullCompute(t *testing.T) {
+	tree := fs.SyntheticTree(2000, 20, 3)
+	dirs := dirPaths(tree.Root)
+	inc := NewIncremental()
+	expanded := map[string]bool{tree.Root.Path: true}
+
+	// Expand everything one directory at a time, then collapse every third
+	steps := append([]string(nil), dirs[1:]...)
+	for i := 1; i < len(dirs); i += 3 {
+		steps = append(steps, dirs[i])
+	}
+	for i, path := range steps {
+		expanded[path] = !expanded[path]
+		opts := DefaultOptions(ModeTreeV)
+		opts.ExpandedPaths = expanded
+
+		got := inc.Compute(tree, opts)
+		want := Compute(tree, opts)
+		if p := sameLayout(got, want); p != "" {
+			t.Fatalf("step %d (toggle %s): incremental layout differs at %s", i, path, p)
+		}
+	}
+}
+
+func TestIncremental_ResetsOnNewTree(t *testing.T) {
+	inc := NewIncremental()
+	opts := DefaultOptions(ModeTreeV)
+	opts.ExpandedPaths = map[string]bool{"/synthetic": true}
+
+	inc.Compute(fs.SyntheticTree(100, 10, 2), opts)
+	other := fs.SyntheticTree(300, 10, 2)
+	if p := sameLayout(inc.Compute(other, opts), Compute(other, opts)); p != "" {
+		t.Errorf("layout of a new tree differs at %s", p)
+	}
+}
+
+// dirNodes maps the paths of the directory nodes under n to the nodes.
+func dirNodes(n *Node, into map[string]*Node) map[string]*Node {
+	if n.Entry != nil && n.Entry.IsDir() {
+		into[n.Entry.Path] = n
+	}
+	for _, c := range n.Children {
+		dirNodes(c, into)
+	}
+	return into
+}
+
+func TestIncremental_ReusesUnchangedSubtrees(t *testing.T) {
+	tree := fs.SyntheticTree(4000, 10, 20)
+	expanded := make(map[string]bool)
+	for _, path := range dirPaths(tree.Root) {
+		expanded[path] = true
+	}
+	opts := DefaultOptions(ModeTreeV)
+	opts.ExpandedPaths = expanded
+	inc := NewIncremental()
+	before := dirNodes(inc.Compute(tree, opts), map[string]*Node{})
+
+	// Collapse a directory in the last row behind the root
+	last := tree.Root.Children[len(tree.Root.Children)-1]
+	for _, c := range tree.Root.Children {
+		if c.IsDir() {
+			last = c
+		}
+	}
+	toggled := last.Children[0]
+	for _, c := range last.Children {
+		if c.IsDir() {
+			toggled = c
+		}
+	}
+	expanded[toggled.Path] = false
+	root := inc.Compute(tree, opts)
+	if p := sameLayout(root, Compute(tree, opts)); p != "" {
+		t.Fatalf("incremental layout differs at %s", p)
+	}
+
+	reused := 0
+	for path, node := range dirNodes(root, map[string]*Node{}) {
+		old := before[path]
+		switch {
+		case isPathWithin(toggled.Path, path):
+			if node == old {
+				t.Errorf("%s, on the path to the change, was not placed again", path)
+			}
+		case old != nil && node.Position == old.Position:
+			if node != old {
+				t.Errorf("%s did not move but was placed again", path)
+			}
+			reused++
+		}
+	}
+	if reused < len(before)/2 {
+		t.Errorf("only %d of %d directories reused; the change moved too much to show anything", reused, len(before))
+	}
+}
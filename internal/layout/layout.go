@@ -1,7 +1,10 @@
 package layout
 
 import (
+	"strings"
+
 	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/color"
 	"github.com/Crank-Git/FSNRedux/internal/fs"
 )
 
@@ -9,8 +12,10 @@ import (
 type Mode uint8
 
 const (
-	ModeMapV  Mode = iota // Squarified treemap with 3D extrusion
-	ModeTreeV             // Hierarchical tree with pedestals and columns
+	ModeMapV     Mode = iota // Squarified treemap with 3D extrusion
+	ModeTreeV                // Hierarchical tree with pedestals and columns
+	ModeRadial               // Balloon tree: subdirectories on rings around their parent
+	ModeSunburst             // Concentric ring segments sized by bytes, extruded in 3D
 )
 
 // String returns the mode name.
@@ -20,31 +25,67 @@ func (m Mode) String() string {
 		return "MapV"
 	case ModeTreeV:
 		return "TreeV"
+	case ModeRadial:
+		return "Radial"
+	case ModeSunburst:
+		return "Sunburst"
 	default:
 		return "Unknown"
 	}
 }
 
+// ParseMode converts a name ("treev", "mapv", "radial" or "sunburst") to a Mode.
+func ParseMode(name string) (Mode, bool) {
+	switch strings.ToLower(name) {
+	case "treev":
+		return ModeTreeV, true
+	case "mapv":
+		return ModeMapV, true
+	case "radial":
+		return ModeRadial, true
+	case "sunburst":
+		return ModeSunburst, true
+	default:
+		return ModeTreeV, false
+	}
+}
+
 // Options controls layout parameters.
 type Options struct {
 	Mode          Mode
-	MaxDepth      int              // limit visible depth (0 = unlimited)
-	PaddingRatio  float32          // spacing between sibling cuboids (default 0.02)
-	HeightScale   float32          // multiplier for file-size-to-height mapping (default 1.0)
-	MinHeight     float32          // minimum cuboid height (default 0.1)
-	MaxHeight     float32          // maximum cuboid height (default 20.0)
-	ExpandedPaths map[string]bool  // which directories are expanded (nil = all)
+	MaxDepth      int             // limit visible depth (0 = unlimited)
+	Geometry                      // sizes and spacings (see DefaultGeometry)
+	ExpandedPaths map[string]bool // which directories are expanded (nil = all)
+	ColorMode     color.Mode      // which attribute drives file colors
+	AgeScale      color.AgeScale  // age buckets and reference time for ColorMode age
+	TimeField     fs.TimeField    // which timestamp represents a file's age
+	SectorHeight  SectorHeight    // what drives sunburst segment heights
+	FileHeights   bool            // pedestal layouts: file height grows with log size (see scaleHeight)
+	FileFootprint bool            // pedestal layouts: file tiles shrink with log size within their grid cell
+	Sort          fs.SortMode     // TreeV: order of subdirectory rows and of files on pedestals
+	GroupFiles    int             // files per directory before small ones are grouped (0 = never)
+	GroupBelow    int64           // once grouping, files smaller than this are always grouped
+	Stable        bool            // TreeV, MapV: keep children where earlier layouts put them (see Order)
+	Order         Order           // remembered placement order for Stable; updated by each layout
+	Focus         string          // path of the subtree Fisheye enlarges ("" = no distortion)
+	Magnify       float32         // how many times Fisheye enlarges Focus (see DefaultMagnify)
 }
 
 // DefaultOptions returns sensible default layout options.
+// MapV colors files by age and the tree modes by size unless ColorMode is
+// overridden.
 func DefaultOptions(mode Mode) Options {
+	colorMode := color.ModeAge
+	if mode != ModeMapV {
+		colorMode = color.ModeSize
+	}
 	return Options{
-		Mode:         mode,
-		MaxDepth:     0,
-		PaddingRatio: 0.02,
-		HeightScale:  1.0,
-		MinHeight:    0.1,
-		MaxHeight:    20.0,
+		Mode:       mode,
+		MaxDepth:   0,
+		Geometry:   DefaultGeometry(),
+		ColorMode:  colorMode,
+		GroupFiles: DefaultGroupFiles,
+		GroupBelow: DefaultGroupBelow,
 	}
 }
 
@@ -56,6 +97,8 @@ type Node struct {
 	Color    rl.Color
 	Children []*Node
 	Depth    int
+	Sector   *Sector // sunburst segment; Position and Size are then its bounding box
+	Group    *Group  // small files standing in for their directory's rest; Entry is then nil
 }
 
 // Rect2D is a 2D rectangle used for treemap subdivision.
@@ -63,17 +106,25 @@ type Rect2D struct {
 	X, Y, W, H float32
 }
 
-// Compute transforms an fs.Tree into a positioned layout tree.
+// Compute transforms an fs.Tree into a positioned layout tree, distorted
+// around opts.Focus when set.
 func Compute(tree *fs.Tree, opts Options) *Node {
 	if tree == nil || tree.Root == nil {
 		return nil
 	}
+	var root *Node
 	switch opts.Mode {
 	case ModeMapV:
-		return computeMapV(tree, opts)
+		root = computeMapV(tree, opts)
 	case ModeTreeV:
-		return computeTreeV(tree, opts)
+		root = computeTreeV(tree, opts)
+	case ModeRadial:
+		root = computeRadial(tree, opts)
+	case ModeSunburst:
+		root = computeSunburst(tree, opts)
 	default:
-		return computeMapV(tree, opts)
+		root = computeMapV(tree, opts)
 	}
+	Fisheye(root, opts.Focus, opts.Magnify)
+	return root
 }
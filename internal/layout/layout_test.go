@@ -0,0 +1,33 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+)
+
+// deepest returns the largest entry depth laid out under n.
+func deepest(n *Node) int {
+	d := 0
+	if n.Entry != nil {
+		d = n.Entry.Depth
+	}
+	for _, c := range n.Children {
+		d = max(d, deepest(c))
+	}
+	return d
+}
+
+func TestCompute_MaxDepthCapsEveryMode(t *testing.T) {
+	tree := fs.SyntheticTree(2000, 10, 3)
+	for _, mode := range []Mode{ModeTreeV, ModeMapV, ModeRadial, ModeSunburst} {
+		opts := DefaultOptions(mode)
+		if full := deepest(Compute(tree, opts)); full <= 2 {
+			t.Fatalf("%s: synthetic tree only %d deep", mode, full)
+		}
+		opts.MaxDepth = 2
+		if d := deepest(Compute(tree, opts)); d != 2 {
+			t.Errorf("%s: laid out %d levels with MaxDepth 2", mode, d)
+		}
+	}
+}
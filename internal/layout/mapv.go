@@ -20,10 +20,12 @@ func computeMapV(tree *fs.Tree, opts Options) *Node {
 		H: totalArea,
 	}
 
-	return layoutMapVNode(tree.Root, rootRect, 0, opts)
+	return layoutMapVNode(tree.Root, rootRect, 0, tree.Root.Size, opts)
 }
 
-func layoutMapVNode(entry *fs.Entry, rect Rect2D, depth int, opts Options) *Node {
+// layoutMapVNode lays out entry in rect; largest is the size of the largest
+// file beside it, which its color is scaled against.
+func layoutMapVNode(entry *fs.Entry, rect Rect2D, depth int, largest int64, opts Options) *Node {
 	if opts.MaxDepth > 0 && depth > opts.MaxDepth {
 		return nil
 	}
@@ -31,7 +33,7 @@ func layoutMapVNode(entry *fs.Entry, rect Rect2D, depth int, opts Options) *Node
 	height := scaleHeight(entry.Size, opts)
 	nodeColor := color.DirColor
 	if entry.Type != fs.TypeDir {
-		nodeColor = color.ColorFromAge(entry.ModTime)
+		nodeColor = fileColor(entry, largest, opts)
 	}
 
 	node := &Node{
@@ -60,32 +62,58 @@ func layoutMapVNode(entry *fs.Entry, rect Rect2D, depth int, opts Options) *Node
 			H: rect.H - 2*padding,
 		}
 
+		// Small files in a group share one rectangle
+		_, group := splitFiles(entry, opts)
+		grouped := make(map[*fs.Entry]bool)
+		if group != nil {
+			for _, f := range group.Members {
+				grouped[f] = true
+			}
+		}
+
 		// Filter to children with size > 0
 		sizedChildren := make([]*fs.Entry, 0, len(entry.Children))
 		for _, child := range entry.Children {
-			if child.Size > 0 {
+			if child.Size > 0 && !grouped[child] {
 				sizedChildren = append(sizedChildren, child)
 			}
 		}
 		// Also add zero-size children so they still appear
 		for _, child := range entry.Children {
-			if child.Size == 0 {
+			if child.Size == 0 && !grouped[child] {
 				sizedChildren = append(sizedChildren, child)
 			}
 		}
 
-		if len(sizedChildren) > 0 {
-			rects := squarify(sizedChildren, innerRect, entry.Size)
-			for i, child := range sizedChildren {
-				if i < len(rects) {
-					childNode := layoutMapVNode(child, rects[i], depth+1, opts)
-					if childNode != nil {
-						// Raise children above the parent pedestal
-						childNode.Position.Y += height
-						raiseChildren(childNode, height)
-						node.Children = append(node.Children, childNode)
-					}
-				}
+		sizedChildren = ordered(entry, sizedChildren, opts)
+
+		sizes := make([]int64, 0, len(sizedChildren)+1)
+		for _, child := range sizedChildren {
+			sizes = append(sizes, child.Size)
+		}
+		if group != nil {
+			sizes = append(sizes, group.Size)
+		}
+
+		var rects []Rect2D
+		if opts.Stable {
+			rects = stripSizes(sizes, innerRect)
+		} else {
+			rects = squarifySizes(sizes, innerRect)
+		}
+		largestChild := color.LargestFile(entry.Children)
+		for i, rect := range rects {
+			var childNode *Node
+			if i < len(sizedChildren) {
+				childNode = layoutMapVNode(sizedChildren[i], rect, depth+1, largestChild, opts)
+			} else if opts.MaxDepth == 0 || depth+1 <= opts.MaxDepth {
+				childNode = mapVGroup(group, rect, depth+1, opts)
+			}
+			if childNode != nil {
+				// Raise children above the parent pedestal
+				childNode.Position.Y += height
+				raiseChildren(childNode, height)
+				node.Children = append(node.Children, childNode)
 			}
 		}
 	}
@@ -93,6 +121,18 @@ func layoutMapVNode(entry *fs.Entry, rect Rect2D, depth int, opts Options) *Node
 	return node
 }
 
+// mapVGroup builds the tile for a group of small files in rect.
+func mapVGroup(g *Group, rect Rect2D, depth int, opts Options) *Node {
+	height := scaleHeight(g.Size, opts)
+	return &Node{
+		Group:    g,
+		Position: rl.NewVector3(rect.X+rect.W/2, height/2, rect.Y+rect.H/2),
+		Size:     rl.NewVector3(rect.W*(1-opts.PaddingRatio), height, rect.H*(1-opts.PaddingRatio)),
+		Color:    groupColor(g, opts),
+		Depth:    depth,
+	}
+}
+
 // raiseChildren recursively raises all child nodes by a given Y offset.
 func raiseChildren(node *Node, offset float32) {
 	for _, child := range node.Children {
@@ -110,21 +150,20 @@ type indexedArea struct {
 // squarify implements the squarified treemap algorithm.
 // Returns a slice of Rect2D, one per child, proportional to child size.
 func squarify(children []*fs.Entry, rect Rect2D, parentSize int64) []Rect2D {
-	if len(children) == 0 {
-		return nil
+	sizes := make([]int64, len(children))
+	for i, c := range children {
+		sizes[i] = c.Size
 	}
+	return squarifySizes(sizes, rect)
+}
 
-	// Assign areas proportional to size
-	totalSize := float64(0)
-	for _, c := range children {
-		totalSize += math.Max(float64(c.Size), 1) // minimum 1 to avoid zero-area
+// squarifySizes is squarify over plain sizes, one Rect2D per size.
+func squarifySizes(sizes []int64, rect Rect2D) []Rect2D {
+	if len(sizes) == 0 {
+		return nil
 	}
 
-	totalArea := float64(rect.W) * float64(rect.H)
-	areas := make([]float64, len(children))
-	for i, c := range children {
-		areas[i] = (math.Max(float64(c.Size), 1) / totalSize) * totalArea
-	}
+	areas := sizeAreas(sizes, rect)
 
 	// Sort areas descending (children should already be sorted, but ensure)
 	sorted := make([]indexedArea, len(areas))
@@ -135,7 +174,7 @@ func squarify(children []*fs.Entry, rect Rect2D, parentSize int64) []Rect2D {
 		return sorted[i].area > sorted[j].area
 	})
 
-	rects := make([]Rect2D, len(children))
+	rects := make([]Rect2D, len(sizes))
 	remaining := Rect2D{X: rect.X, Y: rect.Y, W: rect.W, H: rect.H}
 
 	i := 0
@@ -169,6 +208,22 @@ func squarify(children []*fs.Entry, rect Rect2D, parentSize int64) []Rect2D {
 	return rects
 }
 
+// sizeAreas divides the area of rect between sizes in proportion, counting
+// each size as at least 1 so that empty entries still get a sliver.
+func sizeAreas(sizes []int64, rect Rect2D) []float64 {
+	totalSize := float64(0)
+	for _, size := range sizes {
+		totalSize += math.Max(float64(size), 1) // minimum 1 to avoid zero-area
+	}
+
+	totalArea := float64(rect.W) * float64(rect.H)
+	areas := make([]float64, len(sizes))
+	for i, size := range sizes {
+		areas[i] = (math.Max(float64(size), 1) / totalSize) * totalArea
+	}
+	return areas
+}
+
 // worstAspectRatio computes the worst aspect ratio in a row for the squarify algorithm.
 func worstAspectRatio(row []indexedArea, rowArea float64, shortSide float32) float64 {
 	if len(row) == 0 || shortSide == 0 || rowArea == 0 {
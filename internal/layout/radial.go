@@ -0,0 +1,151 @@
+package layout
+
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/color"
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+)
+
+// Radial layout parameters. Pedestals and files use the TreeV sizes.
+const (
+	rdRingGap    = 1.5  // clearance between a pedestal and its ring of subdirectories
+	rdSpacing    = 0.5  // minimum gap between neighboring subtrees on a ring
+	rdRadiusGrow = 1.05 // ring radius growth per step while fitting a ring
+)
+
+// ring is the radial footprint of a directory: its subtree fits in a disc
+// of the given radius around the pedestal center, and its subdirectories
+// sit at ringRadius from it, starting at the listed angles.
+type ring struct {
+	radius     float32
+	ringRadius float32
+	angles     []float64 // center angle of each subdirectory, in order
+}
+
+// computeRadial generates a balloon tree: each directory's subdirectories
+// are placed on a circle around its pedestal, every one getting an arc in
+// proportion to the width of its own subtree. Sibling subtrees never
+// overlap and a wide directory grows a bigger ring instead of a long row.
+func computeRadial(tree *fs.Tree, opts Options) *Node {
+	rings := make(map[*fs.Entry]*ring)
+	calcRing(tree.Root, rings, opts)
+	return placeRadial(tree.Root, rl.NewVector3(0, opts.DirHeight/2, 0), rings, opts)
+}
+
+// showsChildren reports whether a directory's contents are laid out: it is
+// expanded and above the depth cap.
+func showsChildren(entry *fs.Entry, opts Options) bool {
+	expanded := opts.ExpandedPaths == nil || opts.ExpandedPaths[entry.Path]
+	return expanded && (opts.MaxDepth == 0 || entry.Depth < opts.MaxDepth)
+}
+
+// pedestalSize is the size of a directory's pedestal, as in TreeV.
+func pedestalSize(entry *fs.Entry, opts Options) rl.Vector3 {
+	if !showsChildren(entry, opts) {
+		return rl.NewVector3(opts.DirSize, opts.DirHeight, opts.DirSize)
+	}
+	w, d := calcDirSize(fileCells(entry, opts), opts)
+	return rl.NewVector3(w, opts.DirHeight, d)
+}
+
+// calcRing computes the footprint of every directory bottom-up.
+func calcRing(entry *fs.Entry, rings map[*fs.Entry]*ring, opts Options) *ring {
+	size := pedestalSize(entry, opts)
+	own := float32(math.Hypot(float64(size.X), float64(size.Z))) / 2
+	r := &ring{radius: own}
+	rings[entry] = r
+	if !showsChildren(entry, opts) {
+		return r
+	}
+
+	var radii []float32
+	var maxChild, total float32
+	for _, child := range entry.Children {
+		if child.Type != fs.TypeDir {
+			continue
+		}
+		cr := calcRing(child, rings, opts).radius + rdSpacing/2
+		radii = append(radii, cr)
+		maxChild = max(maxChild, cr)
+		total += 2 * cr
+	}
+	if len(radii) == 0 {
+		return r
+	}
+
+	// The ring must clear the pedestal and be long enough for every subtree;
+	// grow it until the arcs the subtrees need add up to a full turn
+	R := max(own+rdRingGap+maxChild, total/(2*math.Pi))
+	for arcsFor(radii, R) > 2*math.Pi {
+		R *= rdRadiusGrow
+	}
+
+	// Spread the leftover angle evenly between subtrees
+	need := arcsFor(radii, R)
+	slack := (2*math.Pi - need) / float64(len(radii))
+	if len(radii) == 1 {
+		slack = 0
+	}
+	angle := -math.Pi / 2 // first (largest) subdirectory behind, along -Z
+	for i, cr := range radii {
+		half := arc(cr, R) / 2
+		if i > 0 {
+			angle += half + slack/2
+		}
+		r.angles = append(r.angles, angle)
+		angle += half + slack/2
+	}
+	r.ringRadius = R
+	r.radius = max(own, R+maxChild)
+	return r
+}
+
+// arc is the angle a disc of radius cr covers on a circle of radius R.
+func arc(cr, R float32) float64 {
+	return 2 * math.Asin(math.Min(1, float64(cr/R)))
+}
+
+func arcsFor(radii []float32, R float32) float64 {
+	sum := 0.0
+	for _, cr := range radii {
+		sum += arc(cr, R)
+	}
+	return sum
+}
+
+// placeRadial positions a directory, its files and its subdirectory rings.
+func placeRadial(entry *fs.Entry, pos rl.Vector3, rings map[*fs.Entry]*ring, opts Options) *Node {
+	node := &Node{
+		Entry:    entry,
+		Position: pos,
+		Size:     pedestalSize(entry, opts),
+		Color:    color.DirColor,
+		Depth:    entry.Depth,
+	}
+	if !showsChildren(entry, opts) {
+		return node
+	}
+
+	var dirs []*fs.Entry
+	for _, child := range entry.Children {
+		if child.Type == fs.TypeDir {
+			dirs = append(dirs, child)
+		}
+	}
+	files, group := splitFiles(entry, opts)
+	placeFiles(node, files, group, opts)
+
+	r := rings[entry]
+	for i, dir := range dirs {
+		a := r.angles[i]
+		childPos := rl.NewVector3(
+			pos.X+r.ringRadius*float32(math.Cos(a)),
+			pos.Y,
+			pos.Z+r.ringRadius*float32(math.Sin(a)),
+		)
+		node.Children = append(node.Children, placeRadial(dir, childPos, rings, opts))
+	}
+	return node
+}
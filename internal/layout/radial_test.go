@@ -0,0 +1,155 @@
+package layout
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+)
+
+func TestComputeRadial_SingleDir(t *testing.T) {
+	tree := &fs.Tree{Root: &fs.Entry{Name: "root", Type: fs.TypeDir}}
+	result := Compute(tree, DefaultOptions(ModeRadial))
+	if result == nil || result.Entry.Name != "root" {
+		t.Fatalf("expected the root pedestal, got %+v", result)
+	}
+	if result.Position.X != 0 || result.Position.Z != 0 {
+		t.Errorf("root should be at the origin, got %v", result.Position)
+	}
+}
+
+func TestComputeRadial_FilesOnPedestal(t *testing.T) {
+	tree := &fs.Tree{
+		Root: &fs.Entry{
+			Name: "root",
+			Type: fs.TypeDir,
+			Size: 2000,
+			Children: []*fs.Entry{
+				{Name: "a.txt", Type: fs.TypeFile, Size: 1000, ModTime: time.Now(), Depth: 1},
+				{Name: "b.txt", Type: fs.TypeFile, Size: 1000, ModTime: time.Now(), Depth: 1},
+			},
+		},
+	}
+	result := Compute(tree, DefaultOptions(ModeRadial))
+	if len(result.Children) != 2 {
+		t.Fatalf("expected 2 file children, got %d", len(result.Children))
+	}
+	pedestalTop := result.Position.Y + result.Size.Y/2
+	for _, child := range result.Children {
+		if child.Position.Y < pedestalTop {
+			t.Errorf("file %s (Y=%f) should be above pedestal top (%f)", child.Entry.Name, child.Position.Y, pedestalTop)
+		}
+		if math.Abs(float64(child.Position.X)) > float64(result.Size.X/2) || math.Abs(float64(child.Position.Z)) > float64(result.Size.Z/2) {
+			t.Errorf("file %s at %v is off the pedestal", child.Entry.Name, child.Position)
+		}
+	}
+}
+
+func TestComputeRadial_SubdirsOnARing(t *testing.T) {
+	root := &fs.Entry{Name: "root", Type: fs.TypeDir, Path: "/root"}
+	for i := 0; i < 6; i++ {
+		root.Children = append(root.Children, &fs.Entry{Name: fmt.Sprintf("d%d", i), Path: fmt.Sprintf("/root/d%d", i), Type: fs.TypeDir, Depth: 1})
+	}
+	result := Compute(&fs.Tree{Root: root}, DefaultOptions(ModeRadial))
+	if len(result.Children) != 6 {
+		t.Fatalf("expected 6 children, got %d", len(result.Children))
+	}
+
+	first := distXZ(result, result.Children[0])
+	for _, child := range result.Children {
+		if d := distXZ(result, child); math.Abs(d-first) > 1e-4 {
+			t.Errorf("%s is %f from the parent, want %f like its siblings", child.Entry.Name, d, first)
+		}
+		if child.Position.Y != result.Position.Y {
+			t.Errorf("%s should sit at the parent's height", child.Entry.Name)
+		}
+	}
+	// The largest (first) subdirectory goes behind the parent, as in TreeV
+	if c := result.Children[0]; c.Position.Z >= result.Position.Z || math.Abs(float64(c.Position.X)) > 1e-4 {
+		t.Errorf("first subdirectory at %v, want straight behind the parent", c.Position)
+	}
+}
+
+func TestComputeRadial_NoOverlap(t *testing.T) {
+	tree := fs.SyntheticTree(3000, 15, 5)
+	result := Compute(tree, DefaultOptions(ModeRadial))
+
+	var pedestals []*Node
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		if n.Entry.IsDir() {
+			pedestals = append(pedestals, n)
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(result)
+	if len(pedestals) < 100 {
+		t.Fatalf("only %d directories laid out", len(pedestals))
+	}
+	for i, a := range pedestals {
+		for _, b := range pedestals[i+1:] {
+			if overlapXZ(a, b) {
+				t.Fatalf("%s at %v overlaps %s at %v", a.Entry.Path, a.Position, b.Entry.Path, b.Position)
+			}
+		}
+	}
+}
+
+func TestComputeRadial_WideDirStaysCompact(t *testing.T) {
+	root := &fs.Entry{Name: "root", Type: fs.TypeDir, Path: "/root"}
+	for i := 0; i < 400; i++ {
+		root.Children = append(root.Children, &fs.Entry{Name: fmt.Sprintf("d%d", i), Path: fmt.Sprintf("/root/d%d", i), Type: fs.TypeDir, Depth: 1})
+	}
+	tree := &fs.Tree{Root: root}
+
+	opts := DefaultOptions(ModeTreeV)
+	opts.WrapDirs = len(root.Children) // fsnav's single row
+	treev := extentXZ(Compute(tree, opts))
+	radial := extentXZ(Compute(tree, DefaultOptions(ModeRadial)))
+	if radial*3 > treev {
+		t.Errorf("400 subdirectories span %.0f units radially vs %.0f in TreeV; want much narrower", radial, treev)
+	}
+}
+
+func TestComputeRadial_CollapsedHidesChildren(t *testing.T) {
+	tree := fs.SyntheticTree(200, 10, 3)
+	opts := DefaultOptions(ModeRadial)
+	opts.ExpandedPaths = map[string]bool{tree.Root.Path: true}
+	result := Compute(tree, opts)
+	for _, c := range result.Children {
+		if len(c.Children) != 0 {
+			t.Errorf("collapsed %s has %d children", c.Entry.Path, len(c.Children))
+		}
+	}
+}
+
+func distXZ(a, b *Node) float64 {
+	return math.Hypot(float64(a.Position.X-b.Position.X), float64(a.Position.Z-b.Position.Z))
+}
+
+func overlapXZ(a, b *Node) bool {
+	return math.Abs(float64(a.Position.X-b.Position.X)) < float64(a.Size.X+b.Size.X)/2 &&
+		math.Abs(float64(a.Position.Z-b.Position.Z)) < float64(a.Size.Z+b.Size.Z)/2
+}
+
+// extentXZ returns the larger side of the ground footprint of a layout.
+func extentXZ(root *Node) float64 {
+	minX, maxX := math.Inf(1), math.Inf(-1)
+	minZ, maxZ := math.Inf(1), math.Inf(-1)
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		minX = math.Min(minX, float64(n.Position.X-n.Size.X/2))
+		maxX = math.Max(maxX, float64(n.Position.X+n.Size.X/2))
+		minZ = math.Min(minZ, float64(n.Position.Z-n.Size.Z/2))
+		maxZ = math.Max(maxZ, float64(n.Position.Z+n.Size.Z/2))
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(root)
+	return math.Max(maxX-minX, maxZ-minZ)
+}
@@ -0,0 +1,178 @@
+package layout
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+)
+
+// Order remembers where stable layouts placed each directory's children,
+// keyed by layout mode name and then by directory path. Laying out with
+// Options.Stable both follows and updates it, so a directory stays where it
+// was last seen when sizes change or entries come and go. It is plain data
+// and can be saved between sessions.
+type Order map[string]map[string]*Placement
+
+// Placement is the remembered order of one directory's children.
+type Placement struct {
+	Sort  fs.SortMode `json:"sort"`  // order the names were first placed in
+	Names []string    `json:"names"` // child names in placement order
+	Seen  int64       `json:"seen"`  // Unix time the directory was last laid out
+}
+
+// Names returns the remembered child names of the directory at path in mode.
+func (o Order) Names(mode Mode, path string) []string {
+	if p := o[mode.String()][path]; p != nil {
+		return p.Names
+	}
+	return nil
+}
+
+// Prune drops the least recently laid out directories until at most limit
+// remain across all modes.
+func (o Order) Prune(limit int) {
+	type dir struct {
+		mode, path string
+		seen       int64
+	}
+	var dirs []dir
+	for mode, paths := range o {
+		for path, p := range paths {
+			dirs = append(dirs, dir{mode, path, p.Seen})
+		}
+	}
+	if len(dirs) <= limit {
+		return
+	}
+	sort.Slice(dirs, func(i, j int) bool { return dirs[i].seen > dirs[j].seen })
+	for _, d := range dirs[limit:] {
+		delete(o[d.mode], d.path)
+		if len(o[d.mode]) == 0 {
+			delete(o, d.mode)
+		}
+	}
+}
+
+// arrange returns entries, a subset of dir's children in sort order, in
+// their remembered order for mode. Children placed before keep their
+// relative order; new ones follow in the order given. The remembered order
+// is updated: new names are appended and names no longer in dir are
+// dropped. A directory first placed in another sort order is placed afresh.
+func (o Order) arrange(mode Mode, sortMode fs.SortMode, dir *fs.Entry, entries []*fs.Entry) []*fs.Entry {
+	paths := o[mode.String()]
+	if paths == nil {
+		paths = make(map[string]*Placement)
+		o[mode.String()] = paths
+	}
+	p := paths[dir.Path]
+	if p == nil || p.Sort != sortMode {
+		p = &Placement{Sort: sortMode}
+		paths[dir.Path] = p
+	}
+	p.Seen = time.Now().Unix()
+
+	prev := p.Names
+	rank := make(map[string]int, len(prev))
+	for i, name := range prev {
+		rank[name] = i
+	}
+
+	arranged := make([]*fs.Entry, 0, len(entries))
+	var added []*fs.Entry
+	for _, e := range entries {
+		if _, ok := rank[e.Name]; ok {
+			arranged = append(arranged, e)
+		} else {
+			added = append(added, e)
+		}
+	}
+	sort.SliceStable(arranged, func(i, j int) bool {
+		return rank[arranged[i].Name] < rank[arranged[j].Name]
+	})
+	arranged = append(arranged, added...)
+
+	if len(added) == 0 && len(prev) == len(dir.Children) {
+		return arranged // nothing to record
+	}
+	present := make(map[string]bool, len(dir.Children))
+	for _, c := range dir.Children {
+		present[c.Name] = true
+	}
+	names := make([]string, 0, len(prev)+len(added))
+	for _, name := range prev {
+		if present[name] {
+			names = append(names, name)
+		}
+	}
+	for _, e := range added {
+		names = append(names, e.Name)
+	}
+	p.Names = names
+	return arranged
+}
+
+// ordered returns entries, children of dir in the mode's sort order, in the
+// order the layout places them: as remembered in opts.Order when
+// opts.Stable is set, as given otherwise. Only TreeV follows opts.Sort;
+// MapV places children by size.
+func ordered(dir *fs.Entry, entries []*fs.Entry, opts Options) []*fs.Entry {
+	if !opts.Stable || opts.Order == nil {
+		return entries
+	}
+	sortMode := fs.SortSize
+	if opts.Mode == ModeTreeV {
+		sortMode = opts.Sort
+	}
+	return opts.Order.arrange(opts.Mode, sortMode, dir, entries)
+}
+
+// stripSizes is the ordered counterpart of squarifySizes used by stable
+// MapV layouts: rects follow the order of sizes in strips across the
+// shorter side of rect, stacked along the longer one. Strips hold equal
+// counts of items, so every rect moves smoothly with the sizes instead of
+// jumping when squarify would regroup them. The first stable layout is in
+// size order, so the items sharing a strip are of similar size and the
+// tiles come out about square.
+func stripSizes(sizes []int64, rect Rect2D) []Rect2D {
+	if len(sizes) == 0 {
+		return nil
+	}
+	areas := sizeAreas(sizes, rect)
+
+	columns := rect.W >= rect.H
+	length, breadth := rect.H, rect.W
+	if !columns {
+		length, breadth = rect.W, rect.H
+	}
+	strips := 1
+	if length > 0 {
+		strips = max(1, int(math.Round(math.Sqrt(float64(len(sizes))*float64(breadth/length)))))
+	}
+	perStrip := (len(sizes) + strips - 1) / strips
+
+	rects := make([]Rect2D, len(sizes))
+	var offset float32
+	for i := 0; i < len(areas); i += perStrip {
+		j := min(i+perStrip, len(areas))
+		stripArea := 0.0
+		for _, a := range areas[i:j] {
+			stripArea += a
+		}
+
+		thickness := float32(stripArea / float64(length))
+		var along float32
+		for k := i; k < j; k++ {
+			l := float32(areas[k]/stripArea) * length
+			if columns {
+				rects[k] = Rect2D{X: rect.X + offset, Y: rect.Y + along, W: thickness, H: l}
+			} else {
+				rects[k] = Rect2D{X: rect.X + along, Y: rect.Y + offset, W: l, H: thickness}
+			}
+			along += l
+		}
+		offset += thickness
+	}
+	return rects
+}
@@ -0,0 +1,226 @@
+package layout
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+)
+
+// sizedTree builds a root of directories, each holding one file of the
+// given size, with children sorted largest first as a scan leaves them.
+func sizedTree(sizes []int64) *fs.Tree {
+	root := &fs.Entry{Name: "root", Type: fs.TypeDir, Path: "/root", Loaded: true}
+	for i, size := range sizes {
+		dir := &fs.Entry{
+			Name: fmt.Sprintf("d%02d", i), Path: fmt.Sprintf("/root/d%02d", i),
+			Type: fs.TypeDir, Size: size, Depth: 1, Loaded: true,
+		}
+		dir.Children = []*fs.Entry{{
+			Name: "f", Path: dir.Path + "/f", Type: fs.TypeFile, Size: size, Depth: 2,
+		}}
+		root.Children = append(root.Children, dir)
+		root.Size += size
+	}
+	sort.SliceStable(root.Children, func(i, j int) bool { return root.Children[i].Size > root.Children[j].Size })
+	return &fs.Tree{Root: root}
+}
+
+// jitter returns sizes each scaled by a random factor within ±frac.
+func jitter(sizes []int64, frac float64, rng *rand.Rand) []int64 {
+	out := make([]int64, len(sizes))
+	for i, s := range sizes {
+		out[i] = int64(float64(s) * (1 + frac*(2*rng.Float64()-1)))
+	}
+	return out
+}
+
+// positions maps the paths of root's children to their centers.
+func positions(root *Node) map[string]rl.Vector3 {
+	pos := make(map[string]rl.Vector3, len(root.Children))
+	for _, c := range root.Children {
+		pos[c.Path()] = c.Position
+	}
+	return pos
+}
+
+// drift is the mean ground-plane distance the nodes in both maps moved.
+func drift(before, after map[string]rl.Vector3) float64 {
+	var sum float64
+	n := 0
+	for path, a := range before {
+		b, ok := after[path]
+		if !ok {
+			continue
+		}
+		sum += math.Hypot(float64(b.X-a.X), float64(b.Z-a.Z))
+		n++
+	}
+	return sum / float64(max(n, 1))
+}
+
+// rescanDrift lays out sizes, then re-lays them out after each of a few
+// rescans with slightly changed sizes, returning the worst mean drift.
+func rescanDrift(mode Mode, stable bool) float64 {
+	rng := rand.New(rand.NewSource(1))
+	sizes := make([]int64, 40)
+	for i := range sizes {
+		sizes[i] = 1<<20 + rng.Int63n(1<<20)
+	}
+	opts := DefaultOptions(mode)
+	opts.Stable = stable
+	opts.Order = Order{}
+	prev := positions(Compute(sizedTree(sizes), opts))
+
+	worst := 0.0
+	for i := 0; i < 5; i++ {
+		next := positions(Compute(sizedTree(jitter(sizes, 0.1, rng)), opts))
+		worst = max(worst, drift(prev, next))
+		prev = next
+	}
+	return worst
+}
+
+func TestStable_MapVDriftsLessThanSquarify(t *testing.T) {
+	squarified := rescanDrift(ModeMapV, false)
+	stable := rescanDrift(ModeMapV, true)
+	if stable >= squarified/2 {
+		t.Errorf("stable MapV drifted %.3f per rescan, squarified %.3f", stable, squarified)
+	}
+	// Tiles are about 30/sqrt(40) ≈ 4.7 wide; they should barely move
+	if stable > 1 {
+		t.Errorf("stable MapV tiles moved %.3f on average", stable)
+	}
+}
+
+func TestStable_TreeVKeepsPositionsWhenSizesReorder(t *testing.T) {
+	if d := rescanDrift(ModeTreeV, false); d == 0 {
+		t.Fatal("reordering by size did not move TreeV rows; the test shows nothing")
+	}
+	if d := rescanDrift(ModeTreeV, true); d != 0 {
+		t.Errorf("stable TreeV directories moved %.3f on average", d)
+	}
+}
+
+func TestStable_OrderSurvivesSessions(t *testing.T) {
+	sizes := []int64{400, 300, 200, 100}
+	opts := DefaultOptions(ModeTreeV)
+	opts.Stable = true
+	opts.Order = Order{}
+	want := positions(Compute(sizedTree(sizes), opts))
+
+	// A later session starts from the saved order with the sizes reversed
+	saved := Order{}
+	for mode, paths := range opts.Order {
+		saved[mode] = make(map[string]*Placement)
+		for path, p := range paths {
+			saved[mode][path] = &Placement{Sort: p.Sort, Names: append([]string(nil), p.Names...)}
+		}
+	}
+	opts.Order = saved
+	got := positions(Compute(sizedTree([]int64{100, 200, 300, 400}), opts))
+	if d := drift(want, got); d != 0 {
+		t.Errorf("directories moved %.3f on average across sessions", d)
+	}
+}
+
+func TestOrder_NewEntriesFollowAndRemovedAreDropped(t *testing.T) {
+	opts := DefaultOptions(ModeMapV)
+	opts.Stable = true
+	opts.Order = Order{}
+	Compute(sizedTree([]int64{300, 200, 100}), opts)
+	if got := fmt.Sprint(opts.Order.Names(ModeMapV, "/root")); got != "[d00 d01 d02]" {
+		t.Fatalf("first layout recorded %s", got)
+	}
+
+	// d01 is gone and d03, now the largest, is new
+	tree := sizedTree([]int64{300, 0, 100, 500})
+	for i, c := range tree.Root.Children {
+		if c.Name == "d01" {
+			tree.Root.Children = append(tree.Root.Children[:i], tree.Root.Children[i+1:]...)
+			break
+		}
+	}
+	result := Compute(tree, opts)
+	if got := fmt.Sprint(opts.Order.Names(ModeMapV, "/root")); got != "[d00 d02 d03]" {
+		t.Errorf("order after rescan is %s, want d03 appended and d01 dropped", got)
+	}
+	var placed []string
+	for _, c := range result.Children {
+		placed = append(placed, c.Entry.Name)
+	}
+	if got := fmt.Sprint(placed); got != "[d00 d02 d03]" {
+		t.Errorf("MapV placed %s", got)
+	}
+}
+
+func TestStable_SortChangeReordersAndModesAreSeparate(t *testing.T) {
+	tree := sizedTree([]int64{100, 400, 300, 200})
+	opts := DefaultOptions(ModeTreeV)
+	opts.Stable = true
+	opts.Order = Order{}
+	Compute(tree, opts)
+	mapv := DefaultOptions(ModeMapV)
+	mapv.Stable, mapv.Order = true, opts.Order
+	Compute(tree, mapv)
+
+	// Cycling the sort reorders the rows instead of keeping the size order
+	opts.Sort = fs.SortName
+	var placed []string
+	for _, c := range Compute(tree, opts).Children {
+		placed = append(placed, c.Entry.Name)
+	}
+	if got := fmt.Sprint(placed); got != "[d00 d01 d02 d03]" {
+		t.Errorf("TreeV sorted by name placed %s", got)
+	}
+	if got := fmt.Sprint(opts.Order.Names(ModeTreeV, "/root")); got != "[d00 d01 d02 d03]" {
+		t.Errorf("TreeV remembers %s after the sort change", got)
+	}
+	if got := fmt.Sprint(opts.Order.Names(ModeMapV, "/root")); got != "[d01 d02 d03 d00]" {
+		t.Errorf("MapV remembers %s, want its own size order", got)
+	}
+}
+
+func TestOrder_PruneKeepsRecent(t *testing.T) {
+	order := Order{"TreeV": {}, "MapV": {}}
+	for i := 0; i < 5; i++ {
+		order["TreeV"][fmt.Sprint("/t", i)] = &Placement{Seen: int64(i)}
+		order["MapV"][fmt.Sprint("/m", i)] = &Placement{Seen: int64(10 + i)}
+	}
+	order.Prune(3)
+	if len(order["TreeV"]) != 0 || len(order["MapV"]) != 3 {
+		t.Fatalf("kept %v, want the 3 most recent", order)
+	}
+	for _, path := range []string{"/m2", "/m3", "/m4"} {
+		if order["MapV"][path] == nil {
+			t.Errorf("dropped %s, one of the most recent", path)
+		}
+	}
+}
+
+func TestStripSizes_FillsRectInOrder(t *testing.T) {
+	rect := Rect2D{X: -5, Y: -5, W: 10, H: 10}
+	sizes := []int64{1, 8, 3, 5, 2, 9, 4}
+	rects := stripSizes(sizes, rect)
+
+	var area float64
+	for i, r := range rects {
+		area += float64(r.W * r.H)
+		if r.X < rect.X-1e-3 || r.Y < rect.Y-1e-3 || r.X+r.W > rect.X+rect.W+1e-3 || r.Y+r.H > rect.Y+rect.H+1e-3 {
+			t.Errorf("rect %d %+v outside %+v", i, r, rect)
+		}
+		if i > 0 {
+			p := rects[i-1]
+			if r.X < p.X-1e-3 || (r.X < p.X+1e-3 && r.Y < p.Y) {
+				t.Errorf("rect %d %+v placed before rect %d %+v", i, r, i-1, p)
+			}
+		}
+	}
+	if math.Abs(area-100) > 1e-2 {
+		t.Errorf("rects cover %.3f, want 100", area)
+	}
+}
@@ -0,0 +1,325 @@
+package layout
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/color"
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+)
+
+// Sunburst layout parameters.
+const (
+	sbRootRadius  = 1.5   // radius of the root disc
+	sbRingWidth   = 1.5   // radial width of each ring
+	sbRingGap     = 0.06  // radial gap between rings
+	sbSiblingGap  = 0.04  // arc length left between neighboring segments
+	sbMinSweep    = 0.003 // segments narrower than this (radians) are dropped
+	sbBaseHeight  = 0.2   // height of the outermost ring / oldest entries
+	sbLevelHeight = 0.35  // extra height per level inward (SectorHeightDepth)
+	sbAgeHeight   = 3.0   // extra height of the newest entries (SectorHeightAge)
+	sbAgeSpan     = 3650  // days over which age heights fall off (log scale)
+)
+
+// SectorHeight selects what drives the height of sunburst segments.
+type SectorHeight uint8
+
+const (
+	SectorHeightDepth SectorHeight = iota // inner rings stand taller, like a stepped cone
+	SectorHeightAge                       // recently modified entries stand taller
+)
+
+// String returns the height mode name.
+func (h SectorHeight) String() string {
+	switch h {
+	case SectorHeightDepth:
+		return "Depth"
+	case SectorHeightAge:
+		return "Age"
+	default:
+		return "Unknown"
+	}
+}
+
+// ParseSectorHeight converts a name ("depth", "age") to a SectorHeight.
+func ParseSectorHeight(name string) (SectorHeight, bool) {
+	switch strings.ToLower(name) {
+	case "depth":
+		return SectorHeightDepth, true
+	case "age":
+		return SectorHeightAge, true
+	default:
+		return SectorHeightDepth, false
+	}
+}
+
+// Sector is an annular sector around a vertical axis: the footprint of a
+// sunburst segment, extruded between the bottom and top of its node's box.
+// Angles are in radians, measured from +X toward +Z.
+type Sector struct {
+	CenterX, CenterZ float32 // the sunburst's axis
+	Inner, Outer     float32 // radii
+	Start, Sweep     float32 // first angle and angular extent (2*Pi = full ring)
+}
+
+// sectorStep is the largest angle one tessellated slice of a sector spans.
+const sectorStep = 4 * math.Pi / 180
+
+// Slices returns how many slices the sector's arcs are cut into when it is
+// tessellated.
+func (s *Sector) Slices() int {
+	return max(1, int(math.Ceil(float64(s.Sweep)/sectorStep)))
+}
+
+// Angle returns the angle where slice i of n slices starts.
+func (s *Sector) Angle(i, n int) float64 {
+	return float64(s.Start) + float64(s.Sweep)*float64(i)/float64(n)
+}
+
+// Faces tessellates the sector extruded from y0 to y1 into quads, calling
+// face with each quad's corners and outward normal: the top, the outer and
+// inner walls, the two ends unless it closes into a ring, and the bottom if
+// bottom is set. Corners are not wound consistently, and quads meeting at
+// the axis of a sector without an inner radius are degenerate.
+func (s *Sector) Faces(y0, y1 float32, bottom bool, face func(a, b, c, d, normal rl.Vector3)) {
+	vertex := func(a float64, r, y float32) rl.Vector3 {
+		x, z := s.Point(a, r)
+		return rl.NewVector3(x, y, z)
+	}
+	n := s.Slices()
+	for i := 0; i < n; i++ {
+		a0, a1 := s.Angle(i, n), s.Angle(i+1, n)
+		mid := (a0 + a1) / 2
+		radial := rl.NewVector3(float32(math.Cos(mid)), 0, float32(math.Sin(mid)))
+		face(vertex(a0, s.Inner, y1), vertex(a0, s.Outer, y1), vertex(a1, s.Outer, y1), vertex(a1, s.Inner, y1), rl.NewVector3(0, 1, 0))
+		if bottom {
+			face(vertex(a0, s.Inner, y0), vertex(a0, s.Outer, y0), vertex(a1, s.Outer, y0), vertex(a1, s.Inner, y0), rl.NewVector3(0, -1, 0))
+		}
+		face(vertex(a0, s.Outer, y0), vertex(a1, s.Outer, y0), vertex(a1, s.Outer, y1), vertex(a0, s.Outer, y1), radial)
+		if s.Inner > 0 {
+			face(vertex(a0, s.Inner, y0), vertex(a1, s.Inner, y0), vertex(a1, s.Inner, y1), vertex(a0, s.Inner, y1), rl.Vector3Negate(radial))
+		}
+	}
+	if s.Sweep < 2*math.Pi {
+		for i, a := range []float64{s.Angle(0, n), s.Angle(n, n)} {
+			// Ends face away from the sweep
+			normal := rl.NewVector3(float32(math.Sin(a)), 0, -float32(math.Cos(a)))
+			if i == 1 {
+				normal = rl.Vector3Negate(normal)
+			}
+			face(vertex(a, s.Inner, y0), vertex(a, s.Outer, y0), vertex(a, s.Outer, y1), vertex(a, s.Inner, y1), normal)
+		}
+	}
+}
+
+// Contains reports whether the ground point (x, z) lies in the footprint.
+func (s *Sector) Contains(x, z float32) bool {
+	dx, dz := float64(x-s.CenterX), float64(z-s.CenterZ)
+	r := math.Hypot(dx, dz)
+	if r < float64(s.Inner) || r > float64(s.Outer) {
+		return false
+	}
+	return s.InSweep(math.Atan2(dz, dx))
+}
+
+// InSweep reports whether the angle a lies within the sector's sweep.
+func (s *Sector) InSweep(a float64) bool {
+	if s.Sweep >= 2*math.Pi {
+		return true
+	}
+	d := math.Mod(a-float64(s.Start), 2*math.Pi)
+	if d < 0 {
+		d += 2 * math.Pi
+	}
+	return d <= float64(s.Sweep)
+}
+
+// Point returns the ground point at angle a and radius r.
+func (s *Sector) Point(a float64, r float32) (x, z float32) {
+	return s.CenterX + r*float32(math.Cos(a)), s.CenterZ + r*float32(math.Sin(a))
+}
+
+// Mid returns the footprint point halfway along and across the sector, where
+// its label and selection anchor go.
+func (s *Sector) Mid() (x, z float32) {
+	if s.Inner == 0 && s.Sweep >= 2*math.Pi {
+		return s.CenterX, s.CenterZ
+	}
+	return s.Point(float64(s.Start+s.Sweep/2), (s.Inner+s.Outer)/2)
+}
+
+// Extent returns the ground rectangle enclosing the footprint.
+func (s *Sector) Extent() (minX, minZ, maxX, maxZ float32) {
+	minX, minZ = float32(math.Inf(1)), float32(math.Inf(1))
+	maxX, maxZ = float32(math.Inf(-1)), float32(math.Inf(-1))
+	add := func(a float64, r float32) {
+		x, z := s.Point(a, r)
+		minX, maxX = min(minX, x), max(maxX, x)
+		minZ, maxZ = min(minZ, z), max(maxZ, z)
+	}
+	start, end := float64(s.Start), float64(s.Start+s.Sweep)
+	for _, r := range []float32{s.Inner, s.Outer} {
+		add(start, r)
+		add(end, r)
+	}
+	// The outer arc bulges furthest where it crosses an axis
+	for k := math.Ceil(start / (math.Pi / 2)); k*math.Pi/2 <= end; k++ {
+		add(k*math.Pi/2, s.Outer)
+	}
+	return minX, minZ, maxX, maxZ
+}
+
+// computeSunburst lays the tree out as concentric rings: the root is a disc
+// in the middle and each expanded directory's children share the arc just
+// outside it, each getting an angle in proportion to its size. Nodes carry
+// their Sector; Position and Size hold its bounding box.
+func computeSunburst(tree *fs.Tree, opts Options) *Node {
+	sb := sunburst{opts: opts, rootDepth: tree.Root.Depth, now: opts.AgeScale.Reference}
+	if sb.now.IsZero() {
+		sb.now = time.Now()
+	}
+	sb.levels = sb.depthOf(tree.Root)
+	return sb.place(tree.Root, &Sector{Outer: sbRootRadius, Start: -math.Pi / 2, Sweep: 2 * math.Pi}, tree.Root.Size)
+}
+
+type sunburst struct {
+	opts      Options
+	rootDepth int
+	levels    int // deepest ring shown, relative to the root
+	now       time.Time
+}
+
+// depthOf returns the deepest ring level shown under entry.
+func (sb *sunburst) depthOf(entry *fs.Entry) int {
+	level := entry.Depth - sb.rootDepth
+	if !sb.showsChildren(entry) {
+		return level
+	}
+	for _, child := range entry.Children {
+		level = max(level, sb.depthOf(child))
+	}
+	return level
+}
+
+func (sb *sunburst) showsChildren(entry *fs.Entry) bool {
+	if entry.Type != fs.TypeDir || len(entry.Children) == 0 {
+		return false
+	}
+	if sb.opts.ExpandedPaths != nil && !sb.opts.ExpandedPaths[entry.Path] {
+		return false
+	}
+	return sb.opts.MaxDepth == 0 || entry.Depth < sb.opts.MaxDepth
+}
+
+// height returns the extruded height of an entry's segment.
+func (sb *sunburst) height(entry *fs.Entry) float32 {
+	if sb.opts.SectorHeight == SectorHeightAge {
+		t := entry.Time(sb.opts.TimeField)
+		if t.IsZero() {
+			return sbBaseHeight
+		}
+		days := max(sb.now.Sub(t).Hours()/24, 0)
+		fresh := 1 - math.Min(1, math.Log1p(days)/math.Log1p(sbAgeSpan))
+		return sbBaseHeight + float32(fresh)*sbAgeHeight
+	}
+	return sbBaseHeight + float32(sb.levels-(entry.Depth-sb.rootDepth))*sbLevelHeight
+}
+
+// place builds the node for entry in sector s, then rings its children.
+// largest is the size of the largest file beside entry.
+func (sb *sunburst) place(entry *fs.Entry, s *Sector, largest int64) *Node {
+	h := sb.height(entry)
+	minX, minZ, maxX, maxZ := s.Extent()
+	node := &Node{
+		Entry:    entry,
+		Position: rl.NewVector3((minX+maxX)/2, h/2, (minZ+maxZ)/2),
+		Size:     rl.NewVector3(maxX-minX, h, maxZ-minZ),
+		Color:    color.DirColor,
+		Depth:    entry.Depth,
+		Sector:   s,
+	}
+	if entry.Type != fs.TypeDir {
+		node.Color = fileColor(entry, largest, sb.opts)
+	}
+	if !sb.showsChildren(entry) {
+		return node
+	}
+
+	// Small files in a group share one segment, after the other children
+	_, group := splitFiles(entry, sb.opts)
+	grouped := make(map[*fs.Entry]bool)
+	var total int64
+	if group != nil {
+		for _, f := range group.Members {
+			grouped[f] = true
+		}
+		total += group.Size
+	}
+	for _, child := range entry.Children {
+		if !grouped[child] {
+			total += max(child.Size, 0)
+		}
+	}
+	if total == 0 {
+		return node
+	}
+
+	inner := s.Outer + sbRingGap
+	outer := inner + sbRingWidth
+	angle := s.Start
+	// next claims the following arc of the ring for size bytes, or returns
+	// nil if it is too thin to draw
+	next := func(size int64) *Sector {
+		sweep := s.Sweep * float32(float64(max(size, 0))/float64(total))
+		start := angle
+		angle += sweep
+		if sweep < sbMinSweep {
+			return nil
+		}
+		// Leave a sliver between neighbors so segments read separately
+		gap := min(sbSiblingGap/outer, sweep/5)
+		return &Sector{
+			CenterX: s.CenterX, CenterZ: s.CenterZ,
+			Inner: inner, Outer: outer,
+			Start: start + gap/2, Sweep: sweep - gap,
+		}
+	}
+	largestChild := color.LargestFile(entry.Children)
+	for _, child := range entry.Children {
+		if grouped[child] {
+			continue
+		}
+		if cs := next(child.Size); cs != nil {
+			node.Children = append(node.Children, sb.place(child, cs, largestChild))
+		}
+	}
+	if group != nil {
+		if cs := next(group.Size); cs != nil {
+			node.Children = append(node.Children, sb.placeGroup(group, cs))
+		}
+	}
+	return node
+}
+
+// placeGroup builds the segment for a group of small files, as tall as a
+// file segment at its depth.
+func (sb *sunburst) placeGroup(g *Group, s *Sector) *Node {
+	h := sbBaseHeight + float32(sb.levels-(g.Dir.Depth+1-sb.rootDepth))*sbLevelHeight
+	if sb.opts.SectorHeight == SectorHeightAge {
+		h = sbBaseHeight
+		for _, f := range g.Members {
+			h = max(h, sb.height(f))
+		}
+	}
+	minX, minZ, maxX, maxZ := s.Extent()
+	return &Node{
+		Group:    g,
+		Position: rl.NewVector3((minX+maxX)/2, h/2, (minZ+maxZ)/2),
+		Size:     rl.NewVector3(maxX-minX, h, maxZ-minZ),
+		Color:    groupColor(g, sb.opts),
+		Depth:    g.Dir.Depth + 1,
+		Sector:   s,
+	}
+}
@@ -0,0 +1,115 @@
+package layout
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+)
+
+// twoFiles is a root holding a 1000 and a 3000 byte file.
+func twoFiles() *fs.Tree {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &fs.Tree{Root: &fs.Entry{
+		Name: "root", Path: "/root", Type: fs.TypeDir, Size: 4000, ModTime: now,
+		Children: []*fs.Entry{
+			{Name: "old", Path: "/root/old", Type: fs.TypeFile, Size: 1000, ModTime: now.AddDate(-5, 0, 0), Depth: 1},
+			{Name: "new", Path: "/root/new", Type: fs.TypeFile, Size: 3000, ModTime: now.AddDate(0, 0, -1), Depth: 1},
+		},
+	}}
+}
+
+func TestComputeSunburst_SpanProportionalToSize(t *testing.T) {
+	result := Compute(twoFiles(), DefaultOptions(ModeSunburst))
+	if result.Sector == nil || result.Sector.Sweep < 2*math.Pi || result.Sector.Inner != 0 {
+		t.Fatalf("root should be a full disc, got %+v", result.Sector)
+	}
+	if len(result.Children) != 2 {
+		t.Fatalf("expected 2 segments, got %d", len(result.Children))
+	}
+	small, large := result.Children[0].Sector, result.Children[1].Sector
+	if ratio := large.Sweep / small.Sweep; math.Abs(float64(ratio)-3) > 0.05 {
+		t.Errorf("3000 vs 1000 bytes: sweep ratio %f, want about 3", ratio)
+	}
+	if small.Inner <= result.Sector.Outer {
+		t.Errorf("first ring starts at %f, inside the root disc (%f)", small.Inner, result.Sector.Outer)
+	}
+}
+
+func TestComputeSunburst_ChildrenNestInsideParent(t *testing.T) {
+	result := Compute(fs.SyntheticTree(3000, 15, 5), DefaultOptions(ModeSunburst))
+	checked := 0
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		for _, c := range n.Children {
+			p, s := n.Sector, c.Sector
+			if math.Abs(float64(s.Inner-(p.Outer+sbRingGap))) > 1e-4 {
+				t.Fatalf("%s starts at radius %f, want the ring just outside its parent (%f)", c.Entry.Path, s.Inner, p.Outer+sbRingGap)
+			}
+			if !p.InSweep(float64(s.Start)) || !p.InSweep(float64(s.Start+s.Sweep)) || s.Sweep > p.Sweep {
+				t.Fatalf("%s spans [%f, +%f], outside its parent's [%f, +%f]", c.Entry.Path, s.Start, s.Sweep, p.Start, p.Sweep)
+			}
+			if c.Size.Y >= n.Size.Y {
+				t.Fatalf("%s is %f tall, not lower than its parent (%f)", c.Entry.Path, c.Size.Y, n.Size.Y)
+			}
+			checked++
+			walk(c)
+		}
+	}
+	walk(result)
+	if checked < 300 {
+		t.Errorf("only %d segments checked", checked)
+	}
+}
+
+func TestComputeSunburst_BoxEnclosesSector(t *testing.T) {
+	result := Compute(fs.SyntheticTree(500, 10, 4), DefaultOptions(ModeSunburst))
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		s := n.Sector
+		for i := 0; i <= 32; i++ {
+			a := float64(s.Start) + float64(s.Sweep)*float64(i)/32
+			for _, r := range []float32{s.Inner, s.Outer} {
+				x, z := s.Point(a, r)
+				if math.Abs(float64(x-n.Position.X)) > float64(n.Size.X/2)+1e-3 || math.Abs(float64(z-n.Position.Z)) > float64(n.Size.Z/2)+1e-3 {
+					t.Fatalf("%s: sector point (%f, %f) outside its box %v %v", n.Entry.Path, x, z, n.Position, n.Size)
+				}
+			}
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(result)
+}
+
+func TestComputeSunburst_AgeHeights(t *testing.T) {
+	tree := twoFiles()
+	opts := DefaultOptions(ModeSunburst)
+	opts.SectorHeight = SectorHeightAge
+	opts.AgeScale.Reference = tree.Root.ModTime
+	result := Compute(tree, opts)
+	old, recent := result.Children[0], result.Children[1]
+	if recent.Size.Y <= old.Size.Y {
+		t.Errorf("file modified yesterday is %f tall, not taller than a 5-year-old one (%f)", recent.Size.Y, old.Size.Y)
+	}
+	if old.Position.Y != old.Size.Y/2 {
+		t.Errorf("segment should stand on the ground, got Y=%f for height %f", old.Position.Y, old.Size.Y)
+	}
+}
+
+func TestComputeSunburst_CollapsedHidesChildren(t *testing.T) {
+	tree := fs.SyntheticTree(200, 10, 3)
+	opts := DefaultOptions(ModeSunburst)
+	opts.ExpandedPaths = map[string]bool{tree.Root.Path: true}
+	result := Compute(tree, opts)
+	if len(result.Children) == 0 {
+		t.Fatal("expanded root has no segments")
+	}
+	for _, c := range result.Children {
+		if len(c.Children) != 0 {
+			t.Errorf("collapsed %s has %d children", c.Entry.Path, len(c.Children))
+		}
+	}
+}
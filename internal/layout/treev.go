@@ -8,122 +8,181 @@ import (
 	"github.com/Crank-Git/FSNRedux/internal/fs"
 )
 
-// Layout parameters matching fsnav exactly.
-const (
-	lpFileSize    = 0.5
-	lpFileSpacing = 0.1
-	lpFileHeight  = 0.1
-	lpDirSize     = 0.7 // minimum dir size (fsnav: 0.5 + 0.2)
-	lpDirSpacing  = 0.5
-	lpDirHeight   = 0.1
-	lpDirDist     = 5.0
-)
-
 // dirBounds stores layout bounds for a directory (matching fsnav Dir::min_x/max_x/vis_size).
 type dirBounds struct {
 	minX, maxX float32
+	back       float32    // how far the subtree reaches behind the pedestal's center
 	size       rl.Vector3 // visual size of the dir pedestal
+	rows       []dirRow   // subdirectory rows, front to back
+}
+
+// dirRow is one row of subdirectories behind a pedestal.
+type dirRow struct {
+	start, end int     // range of the directory's subdirectories
+	width      float32 // sum of the subdirectories' bound widths
+	z          float32 // distance of the row's centers behind the parent's center
 }
 
 // computeTreeV generates the FSN-style hierarchical layout matching fsnav.
 func computeTreeV(tree *fs.Tree, opts Options) *Node {
 	bounds := make(map[*fs.Entry]*dirBounds)
 	calcBounds(tree.Root, bounds, opts)
-	return place(tree.Root, rl.NewVector3(0, lpDirHeight/2, 0), bounds, opts)
+	return place(tree.Root, rl.NewVector3(0, opts.DirHeight/2, 0), bounds, nil, opts)
 }
 
 // calcDirSize computes pedestal size based on file count (matching fsnav calc_dir_size).
-func calcDirSize(numFiles int) (float32, float32) {
+func calcDirSize(numFiles int, opts Options) (float32, float32) {
 	if numFiles == 0 {
-		return lpDirSize, lpDirSize
+		return opts.DirSize, opts.DirSize
 	}
 	filesX := int(math.Ceil(math.Sqrt(float64(numFiles))))
 	filesY := int(math.Ceil(float64(numFiles) / float64(filesX)))
 
-	xsz := float32(filesX)*lpFileSize + float32(filesX+1)*lpFileSpacing
-	ysz := float32(filesY)*lpFileSize + float32(filesY+1)*lpFileSpacing
+	xsz := float32(filesX)*opts.FileSize + float32(filesX+1)*opts.FileSpacing
+	ysz := float32(filesY)*opts.FileSize + float32(filesY+1)*opts.FileSpacing
 
-	if xsz < lpDirSize {
-		xsz = lpDirSize
+	if xsz < opts.DirSize {
+		xsz = opts.DirSize
 	}
-	if ysz < lpDirSize {
-		ysz = lpDirSize
+	if ysz < opts.DirSize {
+		ysz = opts.DirSize
 	}
 	return xsz, ysz
 }
 
 // calcBounds recursively computes width bounds for each directory (matching fsnav Dir::calc_bounds).
+// Directories already in bounds are reused as-is (see Incremental).
 func calcBounds(entry *fs.Entry, bounds map[*fs.Entry]*dirBounds, opts Options) {
 	if entry.Type != fs.TypeDir {
 		return
 	}
+	if _, ok := bounds[entry]; ok {
+		return
+	}
 
-	isExpanded := opts.ExpandedPaths == nil || opts.ExpandedPaths[entry.Path]
-
-	// Collapsed directories use minimum size (no files shown)
-	if !isExpanded {
+	// Collapsed directories, and those at the depth cap, use minimum size
+	// (no files shown)
+	if !showsChildren(entry, opts) {
 		b := &dirBounds{
-			size: rl.NewVector3(lpDirSize, lpDirHeight, lpDirSize),
+			size: rl.NewVector3(opts.DirSize, opts.DirHeight, opts.DirSize),
+			back: opts.DirSize / 2,
 		}
-		b.minX = -(lpDirSize + lpDirSpacing) / 2
-		b.maxX = (lpDirSize + lpDirSpacing) / 2
+		b.minX = -(opts.DirSize + opts.DirSpacing) / 2
+		b.maxX = (opts.DirSize + opts.DirSpacing) / 2
 		bounds[entry] = b
 		return
 	}
 
-	// Count files for expanded directories
-	numFiles := 0
-	for _, child := range entry.Children {
-		if child.Type != fs.TypeDir {
-			numFiles++
-		}
+	dirW, dirD := calcDirSize(fileCells(entry, opts), opts)
+	b := &dirBounds{
+		size: rl.NewVector3(dirW, opts.DirHeight, dirD),
+		back: dirD / 2,
 	}
 
-	dirW, dirD := calcDirSize(numFiles)
-	b := &dirBounds{
-		size: rl.NewVector3(dirW, lpDirHeight, dirD),
+	// Recurse into subdirs
+	dirs := subdirs(entry, opts)
+	for _, child := range dirs {
+		calcBounds(child, bounds, opts)
 	}
+	b.rows = calcRows(dirs, bounds, dirD, opts)
 
-	if opts.MaxDepth > 0 && entry.Depth >= opts.MaxDepth {
-		b.minX = -(dirW + lpDirSpacing) / 2
-		b.maxX = (dirW + lpDirSpacing) / 2
-		bounds[entry] = b
-		return
+	width := dirW
+	for _, row := range b.rows {
+		width = max(width, row.width)
+		for _, child := range dirs[row.start:row.end] {
+			b.back = max(b.back, row.z+bounds[child].back)
+		}
 	}
 
-	// Recurse into subdirs
-	childWidth := float32(0)
-	for _, child := range entry.Children {
+	b.minX = -(width + opts.DirSpacing) / 2
+	b.maxX = (width + opts.DirSpacing) / 2
+	bounds[entry] = b
+}
+
+// subdirs returns a directory's subdirectories in layout order.
+func subdirs(entry *fs.Entry, opts Options) []*fs.Entry {
+	var dirs []*fs.Entry
+	for _, child := range fs.Sorted(entry.Children, opts.Sort) {
 		if child.Type == fs.TypeDir {
-			calcBounds(child, bounds, opts)
-			cb := bounds[child]
-			childWidth += cb.maxX - cb.minX
+			dirs = append(dirs, child)
 		}
 	}
+	return ordered(entry, dirs, opts)
+}
 
-	width := dirW
-	if childWidth > width {
-		width = childWidth
+// calcRows splits subdirectories into rows behind a pedestal of depth dirD.
+// Up to opts.WrapDirs subdirectories share one row as in fsnav; more wrap into
+// rows of about equal width, enough of them that the grid comes out roughly
+// as deep as it is wide. Each row starts behind the deepest subtree of the
+// row in front of it.
+func calcRows(dirs []*fs.Entry, bounds map[*fs.Entry]*dirBounds, dirD float32, opts Options) []dirRow {
+	if len(dirs) == 0 {
+		return nil
+	}
+	var total float32
+	for _, dir := range dirs {
+		total += bounds[dir].maxX - bounds[dir].minX
+	}
+	target := total
+	if len(dirs) > opts.WrapDirs {
+		n := math.Ceil(math.Sqrt(float64(total / (opts.DirSize + opts.DirDist))))
+		target = total / float32(n)
 	}
 
-	b.minX = -(width + lpDirSpacing) / 2
-	b.maxX = (width + lpDirSpacing) / 2
-	bounds[entry] = b
+	var rows []dirRow
+	row := dirRow{z: dirD/2 + opts.DirDist}
+	back := float32(0) // deepest subtree of the current row, behind its centers
+	for i, dir := range dirs {
+		cb := bounds[dir]
+		if i > row.start && row.width >= target {
+			rows = append(rows, row)
+			row = dirRow{start: i, z: row.z + back + opts.DirDist}
+			back = 0
+		}
+		if i == row.start && len(rows) > 0 {
+			// Keep a deep pedestal clear of the row in front of it
+			row.z = max(row.z, rows[len(rows)-1].z+back+cb.size.Z/2+opts.DirSpacing)
+		}
+		row.width += cb.maxX - cb.minX
+		row.end = i + 1
+		back = max(back, cb.back)
+	}
+	return append(rows, row)
 }
 
-// place recursively positions nodes (matching fsnav Dir::place).
-func place(entry *fs.Entry, pos rl.Vector3, bounds map[*fs.Entry]*dirBounds, opts Options) *Node {
+// placedDir is a directory's subtree as last placed, and where.
+type placedDir struct {
+	node *Node
+	pos  rl.Vector3
+}
+
+// place recursively positions nodes (matching fsnav Dir::place). Directories
+// in placed that sit where they were last placed are returned as they are,
+// without visiting their subtrees; the others are placed and recorded in it
+// (see Incremental). placed may be nil.
+func place(entry *fs.Entry, pos rl.Vector3, bounds map[*fs.Entry]*dirBounds, placed map[*fs.Entry]*placedDir, opts Options) *Node {
 	if opts.MaxDepth > 0 && entry.Depth > opts.MaxDepth {
 		return nil
 	}
+	if p := placed[entry]; p != nil && p.pos == pos {
+		return p.node
+	}
+	node := placeNode(entry, pos, bounds, placed, opts)
+	if placed != nil && entry.Type == fs.TypeDir {
+		placed[entry] = &placedDir{node: node, pos: pos}
+	}
+	return node
+}
 
+// placeNode positions entry and places its children.
+func placeNode(entry *fs.Entry, pos rl.Vector3, bounds map[*fs.Entry]*dirBounds, placed map[*fs.Entry]*placedDir, opts Options) *Node {
 	b := bounds[entry]
 	if b == nil {
 		// Non-directory entries shouldn't reach here, but handle gracefully
 		return &Node{
 			Entry:    entry,
 			Position: pos,
-			Size:     rl.NewVector3(lpFileSize, lpFileHeight, lpFileSize),
+			Size:     rl.NewVector3(opts.FileSize, opts.FileHeight, opts.FileSize),
 			Color:    color.FileColor,
 			Depth:    entry.Depth,
 		}
@@ -141,91 +200,117 @@ func place(entry *fs.Entry, pos rl.Vector3, bounds map[*fs.Entry]*dirBounds, opt
 		return node
 	}
 
-	// Only show contents for expanded directories
-	isExpanded := opts.ExpandedPaths == nil || opts.ExpandedPaths[entry.Path]
-	if !isExpanded {
+	// Only show contents for expanded directories above the depth cap
+	if !showsChildren(entry, opts) {
 		return node
 	}
 
-	// Separate files and subdirs
-	var files []*fs.Entry
-	var dirs []*fs.Entry
-	for _, child := range entry.Children {
-		if child.Type == fs.TypeDir {
-			dirs = append(dirs, child)
-		} else {
-			files = append(files, child)
-		}
-	}
-
 	// Place files in grid on top of pedestal (matching fsnav)
-	if len(files) > 0 {
-		sideFiles := int(math.Ceil(math.Sqrt(float64(len(files)))))
-		fRowWidth := float32(sideFiles)*lpFileSize + float32(sideFiles-1)*lpFileSpacing
-
-		offs := float32(lpFileSize/2 + lpFileSpacing)
-		fStartX := pos.X - b.size.X/2 + offs
-		fStartZ := pos.Z - b.size.Z/2 + offs
-		fStartY := pos.Y + b.size.Y/2 + float32(lpFileHeight/2) // on top of pedestal
-
-		fPosX := fStartX
-		fPosZ := fStartZ
-		_ = fRowWidth
-
-		// Find max file size for color scaling
-		var maxFileSize int64
-		for _, file := range files {
-			if file.Size > maxFileSize {
-				maxFileSize = file.Size
-			}
+	files, group := splitFiles(entry, opts)
+	placeFiles(node, files, group, opts)
+
+	// Place child directories behind the pedestal. A single row keeps fsnav's
+	// start and pitch; wrapped rows are packed and centered on the pedestal.
+	dirs := subdirs(entry, opts)
+	fsnavRow := len(dirs) <= opts.WrapDirs
+	for _, row := range b.rows {
+		x, gap := -row.width/2, float32(0)
+		if fsnavRow {
+			x, gap = b.minX-opts.DirSpacing/2, opts.DirSpacing
 		}
-
-		for i, file := range files {
-			col := i % sideFiles
-
-			fileColor := color.ColorFromSize(file.Size, maxFileSize)
-
-			fileNode := &Node{
-				Entry:    file,
-				Position: rl.NewVector3(fPosX, fStartY, fPosZ),
-				Size:     rl.NewVector3(lpFileSize, lpFileHeight, lpFileSize),
-				Color:    fileColor,
-				Depth:    file.Depth,
-			}
-			node.Children = append(node.Children, fileNode)
-
-			fPosX += lpFileSize + lpFileSpacing
-			if col == sideFiles-1 {
-				fPosX = fStartX
-				fPosZ += lpFileSize + lpFileSpacing
-			}
-		}
-	}
-
-	// Place child directories behind the pedestal
-	if len(dirs) > 0 && (opts.MaxDepth == 0 || entry.Depth < opts.MaxDepth) {
-		x := b.minX - lpDirSpacing/2
-		for _, dir := range dirs {
+		for _, dir := range dirs[row.start:row.end] {
 			cb := bounds[dir]
-			if cb == nil {
-				continue
-			}
 			width := cb.maxX - cb.minX
 
 			childPos := rl.NewVector3(
 				pos.X+x+width/2,
-				pos.Y, // same Y as parent (matching fsnav)
-				pos.Z-(b.size.Z/2+lpDirDist), // negative Z (matching fsnav)
+				pos.Y,       // same Y as parent (matching fsnav)
+				pos.Z-row.z, // negative Z (matching fsnav)
 			)
 
-			childNode := place(dir, childPos, bounds, opts)
+			childNode := place(dir, childPos, bounds, placed, opts)
 			if childNode != nil {
 				node.Children = append(node.Children, childNode)
 			}
 
-			x += width + lpDirSpacing
+			x += width + gap
 		}
 	}
 
 	return node
 }
+
+// fileTile returns the footprint side and height of the tile for a file of
+// the given size: fsnav's fixed tile, or scaled by the log of the size when
+// the options ask for it.
+// A scaled footprint shrinks within the grid cell, so pedestals keep their
+// size.
+func fileTile(size int64, opts Options) (side, height float32) {
+	side, height = opts.FileSize, opts.FileHeight
+	if !opts.FileHeights && !opts.FileFootprint {
+		return side, height
+	}
+	h := scaleHeight(size, opts)
+	if opts.FileHeights {
+		height = h
+	}
+	if opts.FileFootprint && opts.MaxHeight > opts.MinHeight {
+		frac := (h - opts.MinHeight) / (opts.MaxHeight - opts.MinHeight)
+		side = opts.FileSize * (opts.MinFootprint + (1-opts.MinFootprint)*frac)
+	}
+	return side, height
+}
+
+// placeFiles lays files out in a square grid on top of a directory's
+// pedestal, appending them to its children (matching fsnav). A group of
+// small files takes the last cell, sized like a file of its total size.
+func placeFiles(node *Node, files []*fs.Entry, group *Group, opts Options) {
+	cells := len(files)
+	if group != nil {
+		cells++
+	}
+	if cells == 0 {
+		return
+	}
+	files = ordered(node.Entry, fs.Sorted(files, opts.Sort), opts)
+	pos, size := node.Position, node.Size
+	sideFiles := int(math.Ceil(math.Sqrt(float64(cells))))
+
+	offs := opts.FileSize/2 + opts.FileSpacing
+	fStartX := pos.X - size.X/2 + offs
+	fStartZ := pos.Z - size.Z/2 + offs
+	top := pos.Y + size.Y/2
+
+	fPosX := fStartX
+	fPosZ := fStartZ
+	largest := color.LargestFile(files)
+	for i, file := range files {
+		col := i % sideFiles
+		side, height := fileTile(file.Size, opts)
+
+		fileNode := &Node{
+			Entry:    file,
+			Position: rl.NewVector3(fPosX, top+height/2, fPosZ), // on top of pedestal
+			Size:     rl.NewVector3(side, height, side),
+			Color:    fileColor(file, largest, opts),
+			Depth:    file.Depth,
+		}
+		node.Children = append(node.Children, fileNode)
+
+		fPosX += opts.FileSize + opts.FileSpacing
+		if col == sideFiles-1 {
+			fPosX = fStartX
+			fPosZ += opts.FileSize + opts.FileSpacing
+		}
+	}
+	if group != nil {
+		side, height := fileTile(group.Size, opts)
+		node.Children = append(node.Children, &Node{
+			Group:    group,
+			Position: rl.NewVector3(fPosX, top+height/2, fPosZ),
+			Size:     rl.NewVector3(side, height, side),
+			Color:    groupColor(group, opts),
+			Depth:    node.Depth + 1,
+		})
+	}
+}
@@ -1,12 +1,18 @@
 package layout
 
 import (
+	"fmt"
+	"math"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/Crank-Git/FSNRedux/internal/fs"
 )
 
+// fsnav holds the default pedestal geometry, fsnav's own constants.
+var fsnav = DefaultGeometry()
+
 func TestComputeTreeV_NilTree(t *testing.T) {
 	result := Compute(nil, DefaultOptions(ModeTreeV))
 	if result != nil {
@@ -104,3 +110,207 @@ func TestComputeTreeV_SubdirsSpreadHorizontally(t *testing.T) {
 		}
 	}
 }
+
+// wideDir is a root with n empty subdirectories.
+func wideDir(n int) *fs.Tree {
+	root := &fs.Entry{Name: "root", Type: fs.TypeDir, Path: "/root"}
+	for i := 0; i < n; i++ {
+		root.Children = append(root.Children, &fs.Entry{Name: fmt.Sprintf("d%d", i), Path: fmt.Sprintf("/root/d%d", i), Type: fs.TypeDir, Depth: 1})
+	}
+	return &fs.Tree{Root: root}
+}
+
+// subtreeXZ returns the ground rectangle covered by a node and its descendants.
+func subtreeXZ(n *Node) (minX, minZ, maxX, maxZ float64) {
+	minX, maxX = float64(n.Position.X-n.Size.X/2), float64(n.Position.X+n.Size.X/2)
+	minZ, maxZ = float64(n.Position.Z-n.Size.Z/2), float64(n.Position.Z+n.Size.Z/2)
+	for _, c := range n.Children {
+		x0, z0, x1, z1 := subtreeXZ(c)
+		minX, minZ = math.Min(minX, x0), math.Min(minZ, z0)
+		maxX, maxZ = math.Max(maxX, x1), math.Max(maxZ, z1)
+	}
+	return minX, minZ, maxX, maxZ
+}
+
+func TestComputeTreeV_SmallDirsStayInOneRow(t *testing.T) {
+	result := Compute(wideDir(fsnav.WrapDirs), DefaultOptions(ModeTreeV))
+	for _, child := range result.Children {
+		if child.Position.Z != result.Children[0].Position.Z {
+			t.Fatalf("%s at Z=%f, want the single row at Z=%f", child.Entry.Name, child.Position.Z, result.Children[0].Position.Z)
+		}
+	}
+	if z := result.Position.Z - result.Children[0].Position.Z; z != result.Size.Z/2+fsnav.DirDist {
+		t.Errorf("row is %f behind the parent, want %f as in fsnav", z, result.Size.Z/2+fsnav.DirDist)
+	}
+
+	// fsnav starts half a spacing left of the bounds and steps by the
+	// collapsed width plus a spacing
+	width := fsnav.DirSize + fsnav.DirSpacing
+	rowWidth := max(result.Size.X, float32(fsnav.WrapDirs)*width)
+	x := -(rowWidth+fsnav.DirSpacing)/2 - fsnav.DirSpacing/2 + width/2
+	for _, child := range result.Children {
+		if math.Abs(float64(child.Position.X-x)) > 1e-4 {
+			t.Errorf("%s at X=%f, want %f as in fsnav", child.Entry.Name, child.Position.X, x)
+		}
+		x += width + fsnav.DirSpacing
+	}
+}
+
+func TestComputeTreeV_WideDirWrapsIntoGrid(t *testing.T) {
+	result := Compute(wideDir(400), DefaultOptions(ModeTreeV))
+	rows := make(map[float32]bool)
+	for _, child := range result.Children {
+		rows[child.Position.Z] = true
+	}
+	if len(rows) < 2 {
+		t.Fatalf("400 subdirectories in %d row(s), want a grid", len(rows))
+	}
+	minX, minZ, maxX, maxZ := subtreeXZ(result)
+	if w, d := maxX-minX, maxZ-minZ; w > 3*d || d > 3*w {
+		t.Errorf("grid is %.0f wide and %.0f deep, want roughly square", w, d)
+	}
+}
+
+func TestComputeTreeV_NoSiblingOverlap(t *testing.T) {
+	trees := map[string]*fs.Tree{
+		"deep":  fs.SyntheticTree(3000, 15, 5),
+		"bushy": fs.SyntheticTree(3000, 2, 40),
+		"wide":  wideDir(300),
+	}
+	for name, tree := range trees {
+		t.Run(name, func(t *testing.T) {
+			checked := 0
+			var walk func(n *Node)
+			walk = func(n *Node) {
+				var dirs []*Node
+				for _, c := range n.Children {
+					if c.Entry.IsDir() {
+						dirs = append(dirs, c)
+					}
+				}
+				for i, a := range dirs {
+					ax0, az0, ax1, az1 := subtreeXZ(a)
+					for _, b := range dirs[i+1:] {
+						bx0, bz0, bx1, bz1 := subtreeXZ(b)
+						if ax0 < bx1 && bx0 < ax1 && az0 < bz1 && bz0 < az1 {
+							t.Fatalf("subtrees of %s and %s overlap", a.Entry.Path, b.Entry.Path)
+						}
+						checked++
+					}
+					walk(a)
+				}
+			}
+			// fsnav's own single rows can overlap deep subtrees as they do
+			// in fsnav, so pack every directory into rows
+			opts := DefaultOptions(ModeTreeV)
+			opts.WrapDirs = 0
+			walk(Compute(tree, opts))
+			if checked == 0 {
+				t.Fatal("no sibling pairs checked")
+			}
+		})
+	}
+}
+
+// mixedFiles is a root holding files from 4 GB down to 10 bytes, largest
+// first as the scanner sorts them.
+func mixedFiles() *fs.Tree {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	root := &fs.Entry{Name: "root", Type: fs.TypeDir, Path: "/root"}
+	for _, f := range []struct {
+		name  string
+		size  int64
+		hours int
+	}{{"b.iso", 4 << 30, 0}, {"d.txt", 3 << 20, 3}, {"a.log", 4 << 10, 1}, {"c.txt", 10, 2}} {
+		root.Children = append(root.Children, &fs.Entry{
+			Name: f.name, Path: "/root/" + f.name, Type: fs.TypeFile, Size: f.size,
+			ModTime: now.Add(time.Duration(f.hours) * time.Hour), Depth: 1,
+		})
+	}
+	return &fs.Tree{Root: root}
+}
+
+func TestComputeTreeV_FixedFileTilesByDefault(t *testing.T) {
+	result := Compute(mixedFiles(), DefaultOptions(ModeTreeV))
+	for _, f := range result.Children {
+		if f.Size.X != fsnav.FileSize || f.Size.Y != fsnav.FileHeight || f.Size.Z != fsnav.FileSize {
+			t.Errorf("%s is %v, want fsnav's fixed tile", f.Entry.Name, f.Size)
+		}
+	}
+}
+
+func TestComputeTreeV_FileTilesScaleWithSize(t *testing.T) {
+	opts := DefaultOptions(ModeTreeV)
+	opts.FileHeights = true
+	opts.FileFootprint = true
+	result := Compute(mixedFiles(), opts)
+
+	top := result.Position.Y + result.Size.Y/2
+	for i, f := range result.Children {
+		if math.Abs(float64(f.Position.Y-f.Size.Y/2-top)) > 1e-5 {
+			t.Errorf("%s floats off the pedestal: bottom %f, top %f", f.Entry.Name, f.Position.Y-f.Size.Y/2, top)
+		}
+		if f.Size.X > fsnav.FileSize || f.Size.X < fsnav.FileSize*fsnav.MinFootprint {
+			t.Errorf("%s footprint %f outside [%f, %f]", f.Entry.Name, f.Size.X, fsnav.FileSize*fsnav.MinFootprint, fsnav.FileSize)
+		}
+		if i == 0 {
+			continue
+		}
+		prev := result.Children[i-1]
+		if f.Size.Y >= prev.Size.Y || f.Size.X >= prev.Size.X {
+			t.Errorf("%s (%d bytes) is not smaller than %s (%d bytes): %v vs %v",
+				f.Entry.Name, f.Entry.Size, prev.Entry.Name, prev.Entry.Size, f.Size, prev.Size)
+		}
+	}
+}
+
+func TestComputeTreeV_FileOrder(t *testing.T) {
+	cases := map[fs.SortMode]string{
+		fs.SortSize:      "b.iso d.txt a.log c.txt",
+		fs.SortName:      "a.log b.iso c.txt d.txt",
+		fs.SortMTime:     "d.txt c.txt a.log b.iso",
+		fs.SortExtension: "b.iso a.log c.txt d.txt",
+	}
+	for order, want := range cases {
+		tree := mixedFiles()
+		opts := DefaultOptions(ModeTreeV)
+		opts.Sort = order
+		var names []string
+		for _, f := range Compute(tree, opts).Children {
+			names = append(names, f.Entry.Name)
+		}
+		if got := strings.Join(names, " "); got != want {
+			t.Errorf("%s order: got %s, want %s", order, got, want)
+		}
+	}
+}
+
+func TestComputeTreeV_GeometryOverrides(t *testing.T) {
+	if err := fsnav.Validate(); err != nil {
+		t.Fatalf("default geometry invalid: %v", err)
+	}
+	opts := DefaultOptions(ModeTreeV)
+	opts.DirDist = 12
+	opts.FileSize = 1
+	opts.FileHeight = 0.4
+	opts.WrapDirs = 4
+
+	result := Compute(wideDir(4), opts)
+	if z := result.Position.Z - result.Children[0].Position.Z; z != result.Size.Z/2+12 {
+		t.Errorf("row is %f behind the parent, want %f", z, result.Size.Z/2+12)
+	}
+	wrapped := Compute(wideDir(40), opts)
+	if wrapped.Children[0].Position.Z == wrapped.Children[39].Position.Z {
+		t.Error("40 subdirectories stayed in one row with WrapDirs 4")
+	}
+	for _, f := range Compute(mixedFiles(), opts).Children {
+		if f.Size.X != 1 || f.Size.Y != 0.4 {
+			t.Errorf("%s is %v, want the overridden tile", f.Entry.Name, f.Size)
+		}
+	}
+
+	opts.MinHeight, opts.MaxHeight = 3, 2
+	if opts.Validate() == nil {
+		t.Error("min height above max height should not validate")
+	}
+}
@@ -0,0 +1,117 @@
+package renderer
+
+import (
+	"sort"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/color"
+	"github.com/Crank-Git/FSNRedux/internal/scene"
+)
+
+// batch is every cuboid of one color bucket, drawn with a single instanced call.
+type batch struct {
+	Color      rl.Color
+	Transforms []rl.Matrix // unit cube -> node box (scale then translate)
+	Orders     []int       // node Order of each transform (ascending)
+	Nodes      []*scene.SceneNode
+}
+
+// Minimal instancing shader: flat colDiffuse, matching rl.DrawCubeV's unlit look.
+const instancingVS = `#version 330
+in vec3 vertexPosition;
+in mat4 instanceTransform;
+uniform mat4 mvp;
+void main() {
+    gl_Position = mvp*instanceTransform*vec4(vertexPosition, 1.0);
+}
+`
+
+const instancingFS = `#version 330
+uniform vec4 colDiffuse;
+out vec4 finalColor;
+void main() {
+    finalColor = colDiffuse;
+}
+`
+
+// buildBatches groups every drawable node by the bucket of its draw color
+// (color.QuantizedColor), so nodes whose colors differ only mid-tween or
+// mid-fade share a batch; each batch is drawn in the color of its first node.
+// Opaque batches come first, sorted by bucket so the result is
+// deterministic. Translucent nodes follow in traversal order, the order the
+// per-node path draws them in, with only consecutive nodes of one bucket
+// sharing a batch.
+func buildBatches(graph *scene.Graph, highlight func(*scene.SceneNode) bool) []batch {
+	index := make(map[uint32]int)
+	var opaque, translucent []batch
+	graph.Traverse(func(node *scene.SceneNode) bool {
+		if !drawable(node) || node.Sector != nil {
+			return true
+		}
+		c := baseColor(node, highlight)
+		key := color.QuantizedColor(c)
+		var b *batch
+		if c.A == 255 {
+			i, ok := index[key]
+			if !ok {
+				i = len(opaque)
+				index[key] = i
+				opaque = append(opaque, batch{Color: c})
+			}
+			b = &opaque[i]
+		} else {
+			if n := len(translucent); n == 0 || color.QuantizedColor(translucent[n-1].Color) != key {
+				translucent = append(translucent, batch{Color: c})
+			}
+			b = &translucent[len(translucent)-1]
+		}
+		b.Transforms = append(b.Transforms, boxTransform(node.Position, node.Size))
+		b.Orders = append(b.Orders, node.Order)
+		b.Nodes = append(b.Nodes, node)
+		return true
+	})
+	sort.Slice(opaque, func(i, j int) bool {
+		return color.QuantizedColor(opaque[i].Color) < color.QuantizedColor(opaque[j].Color)
+	})
+	return append(opaque, translucent...)
+}
+
+// refit updates b's transforms to where its nodes are now.
+func (b *batch) refit() {
+	for i, node := range b.Nodes {
+		b.Transforms[i] = boxTransform(node.Position, node.Size)
+	}
+}
+
+// visible returns the runs of b's transforms that fall inside spans, with
+// runs that are adjacent in the batch merged into one draw.
+func (b *batch) visible(spans []scene.Span) [][]rl.Matrix {
+	var runs [][]rl.Matrix
+	start, end := 0, -1
+	for _, s := range spans {
+		lo := sort.SearchInts(b.Orders, s.Start)
+		hi := sort.SearchInts(b.Orders, s.End)
+		if lo == hi {
+			continue
+		}
+		if lo != end {
+			if end > start {
+				runs = append(runs, b.Transforms[start:end])
+			}
+			start = lo
+		}
+		end = hi
+	}
+	if end > start {
+		runs = append(runs, b.Transforms[start:end])
+	}
+	return runs
+}
+
+// boxTransform maps the unit cube centered at the origin onto a box.
+func boxTransform(pos, size rl.Vector3) rl.Matrix {
+	return rl.Matrix{
+		M0: size.X, M5: size.Y, M10: size.Z, M15: 1,
+		M12: pos.X, M13: pos.Y, M14: pos.Z,
+	}
+}
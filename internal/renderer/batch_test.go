@@ -0,0 +1,177 @@
+package renderer
+
+import (
+	"fmt"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/color"
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+	"github.com/Crank-Git/FSNRedux/internal/layout"
+	"github.com/Crank-Git/FSNRedux/internal/scene"
+)
+
+// syntheticGraph lays out a fully expanded synthetic tree.
+func syntheticGraph(files int) *scene.Graph {
+	tree := fs.SyntheticTree(files, 200, 8)
+	expanded := make(map[string]bool)
+	var walk func(e *fs.Entry)
+	walk = func(e *fs.Entry) {
+		if e.IsDir() {
+			expanded[e.Path] = true
+			for _, c := range e.Children {
+				walk(c)
+			}
+		}
+	}
+	walk(tree.Root)
+	opts := layout.DefaultOptions(layout.ModeTreeV)
+	opts.ExpandedPaths = expanded
+	return scene.NewGraph(layout.Compute(tree, opts), expanded)
+}
+
+func TestBuildBatches_OneInstancePerNode(t *testing.T) {
+	g := syntheticGraph(5000)
+
+	batches := buildBatches(g, nil)
+	instances := 0
+	seen := make(map[uint32]bool)
+	for _, b := range batches {
+		if seen[color.QuantizedColor(b.Color)] {
+			t.Errorf("color %v split across batches", b.Color)
+		}
+		seen[color.QuantizedColor(b.Color)] = true
+		instances += len(b.Transforms)
+	}
+	if visible := g.VisibleNodeCount(); instances != visible {
+		t.Errorf("instances = %d, want %d visible nodes", instances, visible)
+	}
+	// The size gradient is continuous, but quantized colors keep it to a
+	// few dozen batches
+	if len(batches) > 64 {
+		t.Errorf("got %d batches for %d instances, want them shared by color bucket", len(batches), instances)
+	}
+}
+
+func TestBuildBatches_SkipsFadedNodes(t *testing.T) {
+	g := syntheticGraph(100)
+	before := 0
+	for _, b := range buildBatches(g, nil) {
+		before += len(b.Transforms)
+	}
+
+	faded := g.Root.Children[0]
+	faded.Fade = 1
+	after := 0
+	for _, b := range buildBatches(g, nil) {
+		after += len(b.Transforms)
+	}
+	if after != before-1 {
+		t.Errorf("instances after fading one node = %d, want %d", after, before-1)
+	}
+}
+
+func TestBuildBatches_TweenedColorsShareBuckets(t *testing.T) {
+	g := syntheticGraph(5000)
+	want := len(buildBatches(g, nil))
+
+	// Mid-tween colors drift by a shade or two from their legend color
+	i := 0
+	g.Traverse(func(node *scene.SceneNode) bool {
+		if node.Color.R%8 < 6 {
+			node.Color.R += uint8(i % 2)
+		}
+		i++
+		return true
+	})
+	if got := len(buildBatches(g, nil)); got != want {
+		t.Errorf("got %d batches after nudging colors, want %d", got, want)
+	}
+}
+
+func TestBuildBatches_TranslucentKeepTraversalOrder(t *testing.T) {
+	g := syntheticGraph(500)
+	var order []int
+	g.Traverse(func(node *scene.SceneNode) bool {
+		if drawable(node) && node.Sector == nil && len(order) < 40 {
+			node.Alpha = 0.5
+			order = append(order, node.Order)
+		}
+		return true
+	})
+
+	var got []int
+	opaque := true
+	for _, b := range buildBatches(g, nil) {
+		if b.Color.A == 255 {
+			if !opaque {
+				t.Fatal("opaque batch drawn after translucent ones")
+			}
+			continue
+		}
+		opaque = false
+		got = append(got, b.Orders...)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(order) {
+		t.Errorf("translucent nodes drawn in order %v, want traversal order %v", got, order)
+	}
+}
+
+func TestBatchRefit_FollowsNodes(t *testing.T) {
+	g := syntheticGraph(200)
+	batches := buildBatches(g, nil)
+	g.Traverse(func(node *scene.SceneNode) bool {
+		node.Position.X += 2
+		return true
+	})
+	for i := range batches {
+		batches[i].refit()
+		for j, node := range batches[i].Nodes {
+			if batches[i].Transforms[j] != boxTransform(node.Position, node.Size) {
+				t.Fatalf("%s: transform not refit", node.Entry.Path)
+			}
+		}
+	}
+}
+
+func TestBatchVisible_MergesAdjacentRuns(t *testing.T) {
+	b := batch{Orders: []int{1, 3, 4, 8, 9}, Transforms: make([]rl.Matrix, 5)}
+
+	// [0,4) and [4,6) both hit the batch and are contiguous in it
+	runs := b.visible([]scene.Span{{Start: 0, End: 4}, {Start: 4, End: 6}, {Start: 9, End: 12}})
+	if len(runs) != 2 || len(runs[0]) != 3 || len(runs[1]) != 1 {
+		t.Errorf("got %d runs %v, want lengths [3 1]", len(runs), runLengths(runs))
+	}
+	if runs := b.visible(nil); len(runs) != 0 {
+		t.Errorf("no spans should draw nothing, got %d runs", len(runs))
+	}
+}
+
+func runLengths(runs [][]rl.Matrix) []int {
+	var n []int
+	for _, r := range runs {
+		n = append(n, len(r))
+	}
+	return n
+}
+
+func TestBoxTransform(t *testing.T) {
+	m := boxTransform(rl.NewVector3(1, 2, 3), rl.NewVector3(4, 5, 6))
+	if m.M0 != 4 || m.M5 != 5 || m.M10 != 6 || m.M12 != 1 || m.M13 != 2 || m.M14 != 3 || m.M15 != 1 {
+		t.Errorf("unexpected transform %+v", m)
+	}
+}
+
+// BenchmarkBuildBatches measures the one-off cost of rebuilding the batches
+// for a 200k-node scene and reports the draw calls per frame it replaces.
+func BenchmarkBuildBatches(b *testing.B) {
+	g := syntheticGraph(200000)
+	nodes := g.VisibleNodeCount()
+	b.ResetTimer()
+	var batches []batch
+	for i := 0; i < b.N; i++ {
+		batches = buildBatches(g, nil)
+	}
+	b.ReportMetric(float64(nodes), "calls/frame-unbatched")
+	b.ReportMetric(float64(len(batches)), "calls/frame-batched")
+}
@@ -0,0 +1,11 @@
+//go:build !cgo
+
+package renderer
+
+import rl "github.com/gen2brain/raylib-go/raylib"
+
+// drawInstanced wraps rl.DrawMeshInstanced, whose instance count is an int
+// in the cgo bindings and an int32 in the purego ones.
+func drawInstanced(mesh rl.Mesh, material rl.Material, transforms []rl.Matrix) {
+	rl.DrawMeshInstanced(mesh, material, transforms, int32(len(transforms)))
+}
@@ -0,0 +1,157 @@
+package renderer
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/color"
+	"github.com/Crank-Git/FSNRedux/internal/scene"
+)
+
+// Light setup: a sun high over the viewer's left shoulder.
+var (
+	lightDir = rl.Vector3Normalize(rl.NewVector3(-0.35, -1, -0.55)) // direction the light travels
+	ambient  = float32(0.45)
+)
+
+// shadowHeight is the plane shadows are flattened onto, just above the ground.
+const shadowHeight = -0.005
+
+// shadowColor darkens the ground under the scene.
+var shadowColor = rl.NewColor(color.Ground.R*3/5, color.Ground.G*3/5, color.Ground.B*3/5, 255)
+
+// Spotlight (the FSN selection light): apex height above the node and cone angle.
+const (
+	spotHeight = 12
+	spotCos    = 0.97
+)
+
+// Lit instancing shader: Lambert diffuse plus ambient, and a spotlight cone
+// from above the selected node.
+const litVS = `#version 330
+in vec3 vertexPosition;
+in vec3 vertexNormal;
+in mat4 instanceTransform;
+uniform mat4 mvp;
+out vec3 fragPosition;
+out vec3 fragNormal;
+void main() {
+    vec4 world = instanceTransform*vec4(vertexPosition, 1.0);
+    fragPosition = world.xyz;
+    fragNormal = mat3(instanceTransform)*vertexNormal;
+    gl_Position = mvp*world;
+}
+`
+
+const litFS = `#version 330
+in vec3 fragPosition;
+in vec3 fragNormal;
+uniform vec4 colDiffuse;
+uniform vec3 lightDir;
+uniform float ambient;
+uniform vec3 spotPos;
+uniform float spotOn;
+uniform float spotCos;
+out vec4 finalColor;
+void main() {
+    vec3 n = normalize(fragNormal);
+    float light = ambient + (1.0 - ambient)*max(dot(n, -lightDir), 0.0);
+    if (spotOn > 0.5) {
+        vec3 toFrag = normalize(fragPosition - spotPos);
+        float cone = smoothstep(spotCos, mix(spotCos, 1.0, 0.4), -toFrag.y);
+        light += 0.6*cone*max(dot(n, -toFrag), 0.0);
+    }
+    finalColor = vec4(min(colDiffuse.rgb*light, vec3(1.0)), colDiffuse.a);
+}
+`
+
+// litShader holds the lit material and its per-frame uniform locations.
+type litShader struct {
+	material rl.Material
+	spotPos  int32
+	spotOn   int32
+}
+
+// loadLitShader compiles the lit shader. ok is false when it fails to build.
+func loadLitShader() (litShader, bool) {
+	shader := rl.LoadShaderFromMemory(litVS, litFS)
+	if !rl.IsShaderValid(shader) {
+		return litShader{}, false
+	}
+	shader.UpdateLocation(rl.ShaderLocMatrixMvp, rl.GetShaderLocation(shader, "mvp"))
+	shader.UpdateLocation(rl.ShaderLocMatrixModel, rl.GetShaderLocationAttrib(shader, "instanceTransform"))
+	rl.SetShaderValue(shader, rl.GetShaderLocation(shader, "lightDir"), []float32{lightDir.X, lightDir.Y, lightDir.Z}, rl.ShaderUniformVec3)
+	rl.SetShaderValue(shader, rl.GetShaderLocation(shader, "ambient"), []float32{ambient}, rl.ShaderUniformFloat)
+	rl.SetShaderValue(shader, rl.GetShaderLocation(shader, "spotCos"), []float32{spotCos}, rl.ShaderUniformFloat)
+
+	material := rl.LoadMaterialDefault()
+	material.Shader = shader
+	return litShader{
+		material: material,
+		spotPos:  rl.GetShaderLocation(shader, "spotPos"),
+		spotOn:   rl.GetShaderLocation(shader, "spotOn"),
+	}, true
+}
+
+// setSpotlight aims the spotlight at node, or turns it off when node is nil.
+func (l *litShader) setSpotlight(node *scene.SceneNode) {
+	on := float32(0)
+	var pos rl.Vector3
+	if node != nil {
+		on = 1
+		pos = spotApex(node)
+	}
+	rl.SetShaderValue(l.material.Shader, l.spotOn, []float32{on}, rl.ShaderUniformFloat)
+	rl.SetShaderValue(l.material.Shader, l.spotPos, []float32{pos.X, pos.Y, pos.Z}, rl.ShaderUniformVec3)
+}
+
+// spotApex is where the spotlight hangs above a node.
+func spotApex(node *scene.SceneNode) rl.Vector3 {
+	c := node.Center()
+	return rl.NewVector3(c.X, node.Bounds.Max.Y+spotHeight, c.Z)
+}
+
+// shadowTransform maps the unit cube onto a box's shadow: the box squashed
+// along lightDir onto the plane y = shadowHeight.
+func shadowTransform(pos, size rl.Vector3) rl.Matrix {
+	k := -lightDir.X / lightDir.Y
+	m := -lightDir.Z / lightDir.Y
+	return rl.Matrix{
+		M0: size.X, M4: k * size.Y, M12: pos.X + k*(pos.Y-shadowHeight),
+		M13: shadowHeight,
+		M6:  m * size.Y, M10: size.Z, M14: pos.Z + m*(pos.Y-shadowHeight),
+		M15: 1,
+	}
+}
+
+// buildShadows returns the shadow transform of every solid drawable node.
+func buildShadows(graph *scene.Graph) []rl.Matrix {
+	var shadows []rl.Matrix
+	graph.Traverse(func(node *scene.SceneNode) bool {
+		if drawable(node) && node.Sector == nil && node.Opacity() >= 0.5 {
+			shadows = append(shadows, shadowTransform(node.Position, node.Size))
+		}
+		return true
+	})
+	return shadows
+}
+
+// drawSpotlight draws the translucent beam and ground pool of the spotlight.
+func drawSpotlight(node *scene.SceneNode) {
+	apex := spotApex(node)
+	radius := max(node.Size.X, node.Size.Z) * 0.75
+	if s := node.Sector; s != nil {
+		radius = min(radius, (s.Outer-s.Inner)*0.75)
+	}
+	c := node.Center()
+	beam := rl.NewColor(255, 250, 220, 28)
+	rl.DrawCylinderEx(apex, rl.NewVector3(c.X, 0, c.Z), 0.05, radius, 24, beam)
+	rl.DrawCylinder(rl.NewVector3(c.X, shadowHeight+0.002, c.Z), radius, radius, 0.001, 24, rl.NewColor(255, 250, 220, 60))
+}
+
+// drawOutline traces a node's edges for selection and hover.
+func drawOutline(node *scene.SceneNode, c rl.Color) {
+	if node.Sector != nil {
+		drawSectorWires(node.Sector, node.Bounds.Min.Y, node.Bounds.Max.Y, c)
+		return
+	}
+	rl.DrawCubeWiresV(node.Position, rl.Vector3Scale(node.Size, 1.015), c)
+}
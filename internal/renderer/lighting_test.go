@@ -0,0 +1,26 @@
+package renderer
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestShadowTransform_FlattensAlongLight(t *testing.T) {
+	pos, size := rl.NewVector3(3, 2, -1), rl.NewVector3(1, 4, 2)
+	m := shadowTransform(pos, size)
+
+	// The box's top center lands on the shadow plane along the light ray
+	top := rl.Vector3Transform(rl.NewVector3(0, 0.5, 0), m)
+	want := rl.NewVector3(pos.X, pos.Y+size.Y/2, pos.Z)
+	tRay := (shadowHeight - want.Y) / lightDir.Y
+	want = rl.Vector3Add(want, rl.Vector3Scale(lightDir, tRay))
+	if rl.Vector3Distance(top, want) > 1e-4 {
+		t.Errorf("top center shadow = %v, want %v", top, want)
+	}
+	for _, corner := range []rl.Vector3{rl.NewVector3(-0.5, -0.5, -0.5), rl.NewVector3(0.5, 0.5, 0.5)} {
+		if p := rl.Vector3Transform(corner, m); p.Y != shadowHeight {
+			t.Errorf("corner %v projects to y=%v, want %v", corner, p.Y, shadowHeight)
+		}
+	}
+}
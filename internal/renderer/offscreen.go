@@ -0,0 +1,38 @@
+package renderer
+
+import (
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// RenderToImage draws one frame into an offscreen texture of the given size
+// and reads it back as an image (top row first). draw runs between
+// BeginTextureMode and EndTextureMode. It needs a GL context (a window,
+// which may be hidden) but never presents anything on screen. The caller
+// unloads the image.
+func RenderToImage(width, height int32, draw func()) (*rl.Image, error) {
+	target := rl.LoadRenderTexture(width, height)
+	if !rl.IsRenderTextureValid(target) {
+		return nil, fmt.Errorf("cannot create a %dx%d render target", width, height)
+	}
+	defer rl.UnloadRenderTexture(target)
+
+	rl.BeginDrawing()
+	rl.BeginTextureMode(target)
+	draw()
+	rl.EndTextureMode()
+	rl.EndDrawing()
+
+	img := rl.LoadImageFromTexture(target.Texture)
+	rl.ImageFlipVertical(img) // GL textures are stored bottom row first
+	return img, nil
+}
+
+// ExportPNG writes an image to a PNG file.
+func ExportPNG(img *rl.Image, path string) error {
+	if !rl.ExportImage(*img, path) {
+		return fmt.Errorf("cannot write %s", path)
+	}
+	return nil
+}
@@ -4,64 +4,359 @@ import (
 	rl "github.com/gen2brain/raylib-go/raylib"
 	"github.com/Crank-Git/FSNRedux/internal/color"
 	"github.com/Crank-Git/FSNRedux/internal/scene"
+	"github.com/Crank-Git/FSNRedux/internal/shading"
 )
 
 // Link color matching fsnav: glColor3f(0.1, 0.75, 0.2)
 var linkColor = rl.NewColor(26, 191, 51, 255)
 
 // Renderer handles all 3D drawing.
-type Renderer struct{}
+type Renderer struct {
+	// Batching draws cuboids with one instanced call per color bucket instead
+	// of one rl.DrawCubeV per node. It falls back to per-node drawing when the
+	// instancing shader can't be compiled.
+	Batching bool
+
+	// Shading selects flat colors or the lit pipeline (directional light,
+	// outlines, spotlight and optional ground shadows). Lighting needs the
+	// instancing path; per-node drawing is always flat.
+	Shading shading.Mode
+
+	// highlight, when set, dims every file node for which it returns false
+	// (used by the color legend to pick out one bucket).
+	highlight func(node *scene.SceneNode) bool
+
+	// Cached batches, rebuilt when the graph, its version or the highlight
+	// changes and refit when its nodes only move
+	batches      []batch
+	shadows      []rl.Matrix // shadow transforms, built with shading.Shadows
+	sectors      []*scene.SceneNode
+	builtFor     *scene.Graph
+	builtVersion uint64
+	builtMoved   uint64
+	builtShading shading.Mode
+	dirty        bool
+
+	// GPU resources for instancing, created lazily once a window exists
+	cube       rl.Mesh
+	material   rl.Material
+	instancing bool // shader compiled and resources loaded
+	initTried  bool
+	lit        litShader
+	litOK      bool // lit shader compiled
+	lighting   bool // this frame uses the lit pipeline
+}
 
 // New creates a renderer.
 func New() *Renderer {
-	return &Renderer{}
+	return &Renderer{Batching: true, Shading: shading.Shadows}
+}
+
+// SetHighlight sets the node filter used to dim non-matching files (nil = none).
+func (r *Renderer) SetHighlight(fn func(node *scene.SceneNode) bool) {
+	r.highlight = fn
+	r.dirty = true
+}
+
+// Unload releases GPU resources. Call before closing the window.
+func (r *Renderer) Unload() {
+	if r.instancing {
+		rl.UnloadMaterial(r.material) // also unloads the shader
+		rl.UnloadMesh(&r.cube)
+	}
+	if r.litOK {
+		rl.UnloadMaterial(r.lit.material)
+	}
+	r.instancing = false
+	r.litOK = false
+	r.batches = nil
+	r.shadows = nil
+	r.sectors = nil
+	r.builtFor = nil
+}
+
+// BatchCount returns how many instanced draw calls the last batched frame used.
+func (r *Renderer) BatchCount() int {
+	return len(r.batches)
 }
 
-// DrawScene renders the entire scene graph (matching fsnav's root->draw()).
-func (r *Renderer) DrawScene(graph *scene.Graph, selected *scene.SceneNode, hovered *scene.SceneNode) {
+// initInstancing compiles the instancing shader and uploads the shared unit cube.
+func (r *Renderer) initInstancing() bool {
+	if r.initTried {
+		return r.instancing
+	}
+	r.initTried = true
+
+	shader := rl.LoadShaderFromMemory(instancingVS, instancingFS)
+	if !rl.IsShaderValid(shader) {
+		return false
+	}
+	shader.UpdateLocation(rl.ShaderLocMatrixMvp, rl.GetShaderLocation(shader, "mvp"))
+	shader.UpdateLocation(rl.ShaderLocMatrixModel, rl.GetShaderLocationAttrib(shader, "instanceTransform"))
+
+	r.material = rl.LoadMaterialDefault()
+	r.material.Shader = shader
+	r.cube = rl.GenMeshCube(1, 1, 1)
+	r.instancing = true
+	r.lit, r.litOK = loadLitShader()
+	return true
+}
+
+// DrawScene renders the scene graph (matching fsnav's root->draw()). When view
+// is non-nil, subtrees outside its frustum are skipped and directories too
+// small on screen are drawn as one aggregated block.
+func (r *Renderer) DrawScene(graph *scene.Graph, view *scene.View, selected *scene.SceneNode, hovered *scene.SceneNode) {
+	r.lighting = false
 	if graph == nil || graph.Root == nil {
 		return
 	}
-	// fsnav draws post-order (children first, then parent) for correct transparency.
-	// We do the same via traversal.
-	graph.Traverse(func(node *scene.SceneNode) bool {
-		r.drawNode(node, selected, hovered)
+	if !r.Batching || !r.initInstancing() {
+		// fsnav draws post-order (children first, then parent) for correct transparency.
+		// We do the same via traversal.
+		graph.Cull(view, func(node *scene.SceneNode, aggregate bool) bool {
+			if aggregate {
+				r.drawAggregate(node)
+			} else {
+				r.drawNode(node, selected, hovered)
+			}
+			return true
+		})
+		r.drawLeaving(graph)
+		return
+	}
+
+	r.lighting = r.Shading != shading.Flat && r.litOK
+	if graph != r.builtFor || graph.Version != r.builtVersion || r.Shading != r.builtShading || r.dirty {
+		r.batches = buildBatches(graph, r.highlight)
+		r.sectors = sectorNodes(graph)
+		r.shadows = nil
+		if r.lighting && r.Shading == shading.Shadows {
+			r.shadows = buildShadows(graph)
+		}
+		r.builtFor = graph
+		r.builtVersion = graph.Version
+		r.builtMoved = graph.Moved
+		r.builtShading = r.Shading
+		r.dirty = false
+	} else if graph.Moved != r.builtMoved {
+		// Nodes glided without changing color: move the cached boxes
+		for i := range r.batches {
+			r.batches[i].refit()
+		}
+		if r.shadows != nil {
+			r.shadows = buildShadows(graph)
+		}
+		r.builtMoved = graph.Moved
+	}
+
+	// Shadows first, flat and opaque, so the scene's boxes cover them
+	if len(r.shadows) > 0 {
+		r.material.GetMap(rl.MapDiffuse).Color = shadowColor
+		drawInstanced(r.cube, r.material, r.shadows)
+	}
+
+	material := r.material
+	if r.lighting {
+		material = r.lit.material
+		r.lit.setSpotlight(selected)
+	}
+	diffuse := material.GetMap(rl.MapDiffuse)
+	if view == nil {
+		for _, b := range r.batches {
+			diffuse.Color = b.Color
+			drawInstanced(r.cube, material, b.Transforms)
+		}
+		for _, node := range r.sectors {
+			r.drawSector(node, baseColor(node, r.highlight))
+		}
+	} else {
+		spans, aggregates := graph.VisibleSpans(view)
+		for i := range r.batches {
+			diffuse.Color = r.batches[i].Color
+			for _, run := range r.batches[i].visible(spans) {
+				drawInstanced(r.cube, material, run)
+			}
+		}
+		for _, node := range visibleSectors(r.sectors, spans) {
+			r.drawSector(node, baseColor(node, r.highlight))
+		}
+		for _, node := range aggregates {
+			r.drawAggregate(node)
+		}
+	}
+
+	graph.Cull(view, func(node *scene.SceneNode, aggregate bool) bool {
+		if !aggregate {
+			drawLinks(node)
+		}
 		return true
 	})
+	r.drawLeaving(graph)
+
+	// Selection and hover change every frame, so they are drawn over the
+	// cached batches instead of invalidating them (slightly enlarged to win
+	// the depth test against the batched cube underneath).
+	for _, node := range []*scene.SceneNode{selected, hovered} {
+		if node == nil || !drawable(node) || (node == hovered && hovered == selected) {
+			continue
+		}
+		c := fade(stateColor(node, selected), node.Opacity())
+		if node.Sector != nil {
+			drawSector(inflate(node.Sector, 0.01), node.Bounds.Min.Y, node.Bounds.Max.Y*1.01, c, r.lighting)
+		} else {
+			r.drawBox(node.Position, rl.Vector3Scale(node.Size, 1.01), c)
+		}
+		if r.lighting {
+			drawOutline(node, fade(outlineColor(node, selected), node.Opacity()))
+		}
+	}
+	if r.lighting && selected != nil && drawable(selected) {
+		drawSpotlight(selected)
+	}
 }
 
-func (r *Renderer) drawNode(node *scene.SceneNode, selected *scene.SceneNode, hovered *scene.SceneNode) {
-	if node.Size.X < 0.01 || node.Size.Y < 0.01 || node.Size.Z < 0.01 {
+// outlineColor is the edge color for the selected and hovered nodes.
+func outlineColor(node, selected *scene.SceneNode) rl.Color {
+	if node == selected {
+		return rl.NewColor(255, 255, 255, 230)
+	}
+	return rl.NewColor(255, 255, 255, 130)
+}
+
+// drawBox draws one cuboid, lit when this frame uses lighting.
+func (r *Renderer) drawBox(pos, size rl.Vector3, c rl.Color) {
+	if !r.lighting {
+		rl.DrawCubeV(pos, size, c)
 		return
 	}
+	r.lit.material.GetMap(rl.MapDiffuse).Color = c
+	drawInstanced(r.cube, r.lit.material, []rl.Matrix{boxTransform(pos, size)})
+}
 
-	isDir := node.Entry != nil && node.Entry.IsDir()
+// drawSector draws a sunburst segment, lit when this frame uses lighting.
+func (r *Renderer) drawSector(node *scene.SceneNode, c rl.Color) {
+	drawSector(node.Sector, node.Bounds.Min.Y, node.Bounds.Max.Y, c, r.lighting)
+}
 
-	// Color-based selection/hover (matching fsnav get_color)
-	drawColor := node.Color
-	if node == selected {
-		if isDir {
-			drawColor = color.DirSelected
-		} else {
-			drawColor = color.FileSelected
+// DrawSelection outlines box-selected nodes in the selection color.
+func (r *Renderer) DrawSelection(nodes []*scene.SceneNode) {
+	for _, node := range nodes {
+		if !drawable(node) {
+			continue
 		}
-	} else if node == hovered {
-		if isDir {
-			drawColor = color.DirHover
+		if node.Sector != nil {
+			drawSectorWires(node.Sector, node.Bounds.Min.Y, node.Bounds.Max.Y, stateColor(node, node))
 		} else {
-			drawColor = color.FileHover
+			rl.DrawCubeWiresV(node.Position, rl.Vector3Scale(node.Size, 1.02), stateColor(node, node))
 		}
 	}
+}
+
+// drawable reports whether a node produces any geometry.
+func drawable(node *scene.SceneNode) bool {
+	if node.Size.X < 0.01 || node.Size.Y < 0.01 || node.Size.Z < 0.01 {
+		return false
+	}
+	// Time travel: entries that don't exist yet fade out
+	return node.Opacity() > 0
+}
+
+// fade scales a color's alpha by o.
+func fade(c rl.Color, o float32) rl.Color {
+	if o < 1 {
+		c.A = uint8(float32(c.A) * o)
+	}
+	return c
+}
+
+// baseColor is a node's color ignoring selection and hover: dimmed when the
+// highlight filter rejects it, and faded by time travel or a tween.
+func baseColor(node *scene.SceneNode, highlight func(*scene.SceneNode) bool) rl.Color {
+	c := node.Color
+	isDir := node.Entry != nil && node.Entry.IsDir()
+	if highlight != nil && !isDir && !highlight(node) {
+		c = color.LerpColor(c, color.Background, 0.8)
+	}
+	return fade(c, node.Opacity())
+}
+
+// stateColor returns the selected or hover color for a node (matching fsnav get_color).
+func stateColor(node, selected *scene.SceneNode) rl.Color {
+	isDir := node.Entry != nil && node.Entry.IsDir()
+	switch {
+	case node == selected && isDir:
+		return color.DirSelected
+	case node == selected:
+		return color.FileSelected
+	case isDir:
+		return color.DirHover
+	default:
+		return color.FileHover
+	}
+}
+
+func (r *Renderer) drawNode(node *scene.SceneNode, selected *scene.SceneNode, hovered *scene.SceneNode) {
+	if !drawable(node) {
+		return
+	}
+
+	drawColor := baseColor(node, r.highlight)
+	if node == selected || node == hovered {
+		drawColor = fade(stateColor(node, selected), node.Opacity())
+	}
+
+	if node.Sector != nil {
+		r.drawSector(node, drawColor)
+		return
+	}
 
 	// Draw solid cube (matching fsnav draw_node -> draw_cube)
 	rl.DrawCubeV(node.Position, node.Size, drawColor)
 
-	// Connection lines from parent center to child center (matching fsnav)
-	if isDir && node.Expanded {
-		for _, child := range node.Children {
-			if child.Visible && child.Entry != nil && child.Entry.IsDir() {
-				rl.DrawLine3D(node.Position, child.Position, linkColor)
-			}
+	drawLinks(node)
+}
+
+// drawLeaving draws nodes removed by a collapse while they shrink away.
+func (r *Renderer) drawLeaving(graph *scene.Graph) {
+	for _, node := range graph.Leaving {
+		if !drawable(node) {
+			continue
+		}
+		if node.Sector != nil {
+			r.drawSector(node, baseColor(node, r.highlight))
+		} else {
+			r.drawBox(node.Position, node.Size, baseColor(node, r.highlight))
+		}
+	}
+}
+
+// drawAggregate draws a far-away expanded directory as a single block covering
+// its whole subtree, in the mean color of its files.
+func (r *Renderer) drawAggregate(node *scene.SceneNode) {
+	if node.Opacity() <= 0 {
+		return
+	}
+	b := node.SubtreeBounds
+	c := fade(node.AggregateColor, node.Opacity())
+	if node.Sector != nil {
+		// The subtree fans out within the segment's angle; its own segment
+		// stands in for it
+		drawSector(node.Sector, b.Min.Y, b.Max.Y, c, r.lighting)
+		return
+	}
+	r.drawBox(rl.Vector3Scale(rl.Vector3Add(b.Min, b.Max), 0.5), rl.Vector3Subtract(b.Max, b.Min), c)
+}
+
+// drawLinks draws connection lines from an expanded directory's center to its
+// subdirectories' centers (matching fsnav).
+func drawLinks(node *scene.SceneNode) {
+	if node.Entry == nil || !node.Entry.IsDir() || !node.Expanded || node.Sector != nil {
+		return
+	}
+	for _, child := range node.Children {
+		if child.Visible && child.Entry != nil && child.Entry.IsDir() && child.Opacity() > 0 {
+			lc := fade(linkColor, child.Opacity())
+			rl.DrawLine3D(node.Position, child.Position, lc)
 		}
 	}
 }
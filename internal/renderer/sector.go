@@ -0,0 +1,133 @@
+package renderer
+
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/layout"
+	"github.com/Crank-Git/FSNRedux/internal/scene"
+)
+
+// sectorVertex returns the world point at angle a, radius r and height y.
+func sectorVertex(s *layout.Sector, a float64, r, y float32) rl.Vector3 {
+	x, z := s.Point(a, r)
+	return rl.NewVector3(x, y, z)
+}
+
+// drawSector draws an annular sector extruded from y0 to y1 in immediate
+// mode: the top, the outer and inner walls and, unless it closes into a
+// ring, its two ends. The bottom sits on the ground and is skipped. When lit
+// is true each face is shaded on the CPU like the lit shader shades cuboids.
+func drawSector(s *layout.Sector, y0, y1 float32, c rl.Color, lit bool) {
+	// Each slice emits up to 4 quads (6 vertices each); ends add 2 more
+	rl.CheckRenderBatchLimit(int32(s.Slices()*24 + 12))
+	rl.Begin(rl.Triangles)
+	s.Faces(y0, y1, false, func(a, b, cc, d, normal rl.Vector3) {
+		col := c
+		if lit {
+			col = shade(c, normal)
+		}
+		rl.Color4ub(col.R, col.G, col.B, col.A)
+		triangle(a, b, cc, normal)
+		triangle(a, cc, d, normal)
+	})
+	rl.End()
+}
+
+// triangle emits one triangle wound counter-clockwise around normal, so it
+// survives backface culling. Degenerate triangles (at a sector's apex) are
+// dropped.
+func triangle(a, b, c, normal rl.Vector3) {
+	facing := rl.Vector3DotProduct(rl.Vector3CrossProduct(rl.Vector3Subtract(b, a), rl.Vector3Subtract(c, a)), normal)
+	if facing == 0 {
+		return
+	}
+	if facing < 0 {
+		b, c = c, b
+	}
+	rl.Normal3f(normal.X, normal.Y, normal.Z)
+	for _, v := range []rl.Vector3{a, b, c} {
+		rl.Vertex3f(v.X, v.Y, v.Z)
+	}
+}
+
+// shade applies the directional light to a face color.
+func shade(c rl.Color, normal rl.Vector3) rl.Color {
+	light := ambient + (1-ambient)*max(rl.Vector3DotProduct(normal, rl.Vector3Negate(lightDir)), 0)
+	scale := func(v uint8) uint8 { return uint8(min(float32(v)*light, 255)) }
+	return rl.NewColor(scale(c.R), scale(c.G), scale(c.B), c.A)
+}
+
+// inflate returns s grown by d on each side, so it can be drawn over itself.
+func inflate(s *layout.Sector, d float32) *layout.Sector {
+	g := *s
+	g.Inner = max(g.Inner-d, 0)
+	g.Outer += d
+	if g.Sweep < 2*math.Pi {
+		grow := min(d/g.Outer, (2*math.Pi-g.Sweep)/2)
+		g.Start -= grow
+		g.Sweep += 2 * grow
+	}
+	return &g
+}
+
+// drawSectorWires traces a sector's edges: both arcs top and bottom and the
+// vertical and radial edges of its ends.
+func drawSectorWires(s *layout.Sector, y0, y1 float32, c rl.Color) {
+	n := s.Slices()
+	angle := func(i int) float64 { return s.Angle(i, n) }
+	for i := 0; i < n; i++ {
+		a0, a1 := angle(i), angle(i+1)
+		for _, r := range []float32{s.Inner, s.Outer} {
+			if r == 0 {
+				continue
+			}
+			for _, y := range []float32{y0, y1} {
+				rl.DrawLine3D(sectorVertex(s, a0, r, y), sectorVertex(s, a1, r, y), c)
+			}
+		}
+	}
+	if s.Sweep >= 2*math.Pi {
+		return
+	}
+	for _, a := range []float64{angle(0), angle(n)} {
+		for _, y := range []float32{y0, y1} {
+			rl.DrawLine3D(sectorVertex(s, a, s.Inner, y), sectorVertex(s, a, s.Outer, y), c)
+		}
+		for _, r := range []float32{s.Inner, s.Outer} {
+			rl.DrawLine3D(sectorVertex(s, a, r, y0), sectorVertex(s, a, r, y1), c)
+		}
+	}
+}
+
+// sectorNodes returns every drawable sunburst segment in tree order. They
+// are drawn one by one instead of going into the instanced cuboid batches.
+func sectorNodes(graph *scene.Graph) []*scene.SceneNode {
+	var nodes []*scene.SceneNode
+	graph.Traverse(func(node *scene.SceneNode) bool {
+		if node.Sector != nil && drawable(node) {
+			nodes = append(nodes, node)
+		}
+		return true
+	})
+	return nodes
+}
+
+// visibleSectors returns the sector nodes whose Order falls in spans. Both
+// are in ascending order, so one merged pass suffices.
+func visibleSectors(nodes []*scene.SceneNode, spans []scene.Span) []*scene.SceneNode {
+	var visible []*scene.SceneNode
+	j := 0
+	for _, node := range nodes {
+		for j < len(spans) && spans[j].End <= node.Order {
+			j++
+		}
+		if j == len(spans) {
+			break
+		}
+		if node.Order >= spans[j].Start {
+			visible = append(visible, node)
+		}
+	}
+	return visible
+}
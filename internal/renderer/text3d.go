@@ -0,0 +1,179 @@
+package renderer
+
+import (
+	"strings"
+	"unicode/utf8"
+	"unsafe"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// labelLineGap is the extra space between wrapped label lines, as a
+// fraction of the text height.
+const labelLineGap = 0.15
+
+// TextFont draws text as textured quads in world space.
+type TextFont struct {
+	Font   rl.Font
+	Shader rl.Shader // SDF shader; unused when SDF is false
+	SDF    bool
+}
+
+// glyph returns the atlas rectangle and metrics of r (or '?' when missing).
+func (f *TextFont) glyph(r rune) (rl.Rectangle, rl.GlyphInfo) {
+	i := rl.GetGlyphIndex(f.Font, r)
+	recs := unsafe.Slice(f.Font.Recs, f.Font.CharsCount)
+	infos := unsafe.Slice(f.Font.Chars, f.Font.CharsCount)
+	return recs[i], infos[i]
+}
+
+// Measure returns the width of one line of text drawn height units tall.
+func (f *TextFont) Measure(text string, height float32) float32 {
+	if f.Font.BaseSize == 0 {
+		return 0
+	}
+	scale := height / float32(f.Font.BaseSize)
+	var w float32
+	for _, r := range text {
+		rec, info := f.glyph(r)
+		if info.AdvanceX > 0 {
+			w += float32(info.AdvanceX) * scale
+		} else {
+			w += rec.Width * scale
+		}
+	}
+	return w
+}
+
+// WrapLabel splits a name into lines no wider than maxWidth, preferring to
+// break after separators such as '_', '-', '.' and ' ' and splitting long
+// runs mid-word. At most maxLines lines are returned; the last one is
+// shortened with ".." if the name doesn't fit.
+func WrapLabel(text string, maxWidth float32, maxLines int, measure func(string) float32) []string {
+	if text == "" || maxLines <= 0 {
+		return nil
+	}
+	var lines []string
+	for text != "" {
+		if len(lines) == maxLines-1 {
+			lines = append(lines, ellipsize(text, maxWidth, measure))
+			break
+		}
+		n := fitPrefix(text, maxWidth, measure)
+		if n < len(text) {
+			// Back up to just after the last separator, if there is one
+			if i := strings.LastIndexAny(text[:n], "_-. "); i > 0 {
+				n = i + 1
+			}
+		}
+		lines = append(lines, strings.TrimRight(text[:n], " "))
+		text = strings.TrimLeft(text[n:], " ")
+	}
+	return lines
+}
+
+// fitPrefix returns the byte length of the longest prefix of text that fits
+// in maxWidth (at least one rune).
+func fitPrefix(text string, maxWidth float32, measure func(string) float32) int {
+	n := 0
+	for n < len(text) {
+		_, size := utf8.DecodeRuneInString(text[n:])
+		if n > 0 && measure(text[:n+size]) > maxWidth {
+			break
+		}
+		n += size
+	}
+	return n
+}
+
+// ellipsize shortens text to fit maxWidth, ending it with ".." when cut.
+func ellipsize(text string, maxWidth float32, measure func(string) float32) string {
+	if measure(text) <= maxWidth {
+		return text
+	}
+	n := fitPrefix(text, maxWidth-measure(".."), measure)
+	return text[:n] + ".."
+}
+
+// DrawLabel3D draws lines of text in world space, centered horizontally on
+// anchor with the bottom of the block at the anchor. right and up span the
+// text plane (unit vectors); height is the line height in world units. Call
+// between BeginLabels and EndLabels inside BeginMode3D.
+func (f *TextFont) DrawLabel3D(lines []string, anchor, right, up rl.Vector3, height float32, tint rl.Color) {
+	if f.Font.BaseSize == 0 || len(lines) == 0 {
+		return
+	}
+	scale := height / float32(f.Font.BaseSize)
+	pad := float32(f.Font.CharsPadding)
+	texW, texH := float32(f.Font.Texture.Width), float32(f.Font.Texture.Height)
+	lineStep := height * (1 + labelLineGap)
+	normal := rl.Vector3CrossProduct(right, up)
+
+	corner := func(dx, dy, u, v float32) {
+		p := rl.Vector3Add(anchor, rl.Vector3Add(rl.Vector3Scale(right, dx), rl.Vector3Scale(up, dy)))
+		rl.TexCoord2f(u, v)
+		rl.Vertex3f(p.X, p.Y, p.Z)
+	}
+	for li, line := range lines {
+		top := float32(len(lines)-li) * lineStep // from the anchor up to this line's top
+		x := -f.Measure(line, height) / 2
+		for _, r := range line {
+			rec, info := f.glyph(r)
+			advance := float32(info.AdvanceX) * scale
+			if advance == 0 {
+				advance = rec.Width * scale
+			}
+			if r == ' ' {
+				x += advance
+				continue
+			}
+			x0 := x + (float32(info.OffsetX)-pad)*scale
+			y0 := top - (float32(info.OffsetY)-pad)*scale
+			w := (rec.Width + 2*pad) * scale
+			h := (rec.Height + 2*pad) * scale
+			u0, v0 := (rec.X-pad)/texW, (rec.Y-pad)/texH
+			u1, v1 := (rec.X+rec.Width+pad)/texW, (rec.Y+rec.Height+pad)/texH
+
+			// One quad per glyph, as raylib's DrawTextCodepoint3D does
+			rl.CheckRenderBatchLimit(4)
+			rl.SetTexture(f.Font.Texture.ID)
+			rl.Begin(rl.Quads)
+			rl.Color4ub(tint.R, tint.G, tint.B, tint.A)
+			rl.Normal3f(normal.X, normal.Y, normal.Z)
+			corner(x0, y0, u0, v0)
+			corner(x0, y0-h, u0, v1)
+			corner(x0+w, y0-h, u1, v1)
+			corner(x0+w, y0, u1, v0)
+			rl.End()
+			rl.SetTexture(0)
+			x += advance
+		}
+	}
+}
+
+// BeginLabels prepares state for a run of DrawLabel3D calls. Labels are
+// depth-tested against the scene but don't write depth, so overlapping
+// labels blend instead of cutting into each other.
+func (f *TextFont) BeginLabels() {
+	rl.DrawRenderBatchActive()
+	rl.DisableDepthMask()
+	rl.DisableBackfaceCulling()
+	if f.SDF {
+		rl.BeginShaderMode(f.Shader)
+	}
+}
+
+// EndLabels restores state after BeginLabels.
+func (f *TextFont) EndLabels() {
+	if f.SDF {
+		rl.EndShaderMode()
+	}
+	rl.DrawRenderBatchActive()
+	rl.EnableBackfaceCulling()
+	rl.EnableDepthMask()
+}
+
+// LabelHeight returns the total height of n wrapped lines as DrawLabel3D lays them out.
+func LabelHeight(n int, height float32) float32 {
+	return float32(n) * height * (1 + labelLineGap)
+}
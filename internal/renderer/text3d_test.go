@@ -0,0 +1,46 @@
+package renderer
+
+import (
+	"reflect"
+	"testing"
+	"unicode/utf8"
+)
+
+// monospace measures one unit per rune.
+func monospace(s string) float32 {
+	return float32(utf8.RuneCountInString(s))
+}
+
+func TestWrapLabel(t *testing.T) {
+	tests := []struct {
+		text     string
+		width    float32
+		maxLines int
+		want     []string
+	}{
+		{"short", 10, 3, []string{"short"}},
+		{"release_notes_2024.md", 10, 3, []string{"release_", "notes_", "2024.md"}},
+		{"my project files", 10, 3, []string{"my", "project", "files"}},
+		{"abcdefghijklmnop", 6, 3, []string{"abcdef", "ghijkl", "mnop"}},
+		{"abcdefghijklmnopqrstuvwxyz", 6, 2, []string{"abcdef", "ghij.."}},
+		{"", 10, 3, nil},
+	}
+	for _, tt := range tests {
+		got := WrapLabel(tt.text, tt.width, tt.maxLines, monospace)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("WrapLabel(%q, %v, %d) = %q, want %q", tt.text, tt.width, tt.maxLines, got, tt.want)
+		}
+		for _, line := range got {
+			if monospace(line) > tt.width {
+				t.Errorf("WrapLabel(%q): line %q is wider than %v", tt.text, line, tt.width)
+			}
+		}
+	}
+}
+
+func TestWrapLabel_NarrowWidthStillMakesProgress(t *testing.T) {
+	got := WrapLabel("héllo", 0.5, 10, monospace)
+	if want := []string{"h", "é", "l", "l", "o"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want one rune per line %q", got, want)
+	}
+}
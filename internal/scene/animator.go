@@ -1,8 +1,6 @@
 package scene
 
 import (
-	"math"
-
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
@@ -17,9 +15,20 @@ type CameraAnimation struct {
 	Duration   float32
 }
 
+// ValueAnimation holds state for easing a scalar (e.g. the time-travel clock).
+type ValueAnimation struct {
+	Active   bool
+	From     float64
+	To       float64
+	Current  float64
+	Progress float32
+	Duration float32
+}
+
 // Animator handles smooth transitions.
 type Animator struct {
 	Camera CameraAnimation
+	Value  ValueAnimation
 }
 
 // NewAnimator creates a new animator.
@@ -54,7 +63,7 @@ func (a *Animator) Tick(dt float32) (rl.Vector3, rl.Vector3, bool) {
 	}
 
 	// Ease-in-out cubic
-	t := easeInOutCubic(a.Camera.Progress)
+	t := EaseInOutCubic(a.Camera.Progress)
 
 	pos := lerpVector3(a.Camera.From, a.Camera.To, t)
 	target := lerpVector3(a.Camera.FromTarget, a.Camera.ToTarget, t)
@@ -62,9 +71,44 @@ func (a *Animator) Tick(dt float32) (rl.Vector3, rl.Vector3, bool) {
 	return pos, target, a.Camera.Active
 }
 
+// StartValueMove begins easing the scalar from its current value to target.
+// Calling it mid-animation retargets smoothly from wherever the value is now.
+func (a *Animator) StartValueMove(target float64, duration float32) {
+	a.Value = ValueAnimation{
+		Active:   true,
+		From:     a.Value.Current,
+		To:       target,
+		Current:  a.Value.Current,
+		Duration: duration,
+	}
+}
+
+// SetValue jumps the scalar to v, cancelling any animation.
+func (a *Animator) SetValue(v float64) {
+	a.Value = ValueAnimation{From: v, To: v, Current: v}
+}
+
+// TickValue advances the scalar animation by dt seconds.
+// Returns (currentValue, stillAnimating).
+func (a *Animator) TickValue(dt float32) (float64, bool) {
+	if !a.Value.Active {
+		return a.Value.Current, false
+	}
+
+	a.Value.Progress += dt / a.Value.Duration
+	if a.Value.Progress >= 1.0 {
+		a.Value.Progress = 1.0
+		a.Value.Active = false
+	}
+
+	t := float64(EaseOutCubic(a.Value.Progress))
+	a.Value.Current = a.Value.From + (a.Value.To-a.Value.From)*t
+	return a.Value.Current, a.Value.Active
+}
+
 // IsAnimating returns true if any animation is in progress.
 func (a *Animator) IsAnimating() bool {
-	return a.Camera.Active
+	return a.Camera.Active || a.Value.Active
 }
 
 // lerpVector3 linearly interpolates between two vectors.
@@ -75,11 +119,3 @@ func lerpVector3(a, b rl.Vector3, t float32) rl.Vector3 {
 		a.Z+(b.Z-a.Z)*t,
 	)
 }
-
-// easeInOutCubic provides smooth acceleration and deceleration.
-func easeInOutCubic(t float32) float32 {
-	if t < 0.5 {
-		return 4 * t * t * t
-	}
-	return 1 - float32(math.Pow(float64(-2*t+2), 3))/2
-}
@@ -0,0 +1,44 @@
+package scene
+
+import "testing"
+
+func TestAnimator_ValueMoves(t *testing.T) {
+	a := NewAnimator()
+	a.SetValue(10)
+	if v, active := a.TickValue(0.1); v != 10 || active {
+		t.Fatalf("idle value %v (active %v), want 10 at rest", v, active)
+	}
+
+	a.StartValueMove(20, 1)
+	for _, tc := range []struct {
+		dt     float32
+		min    float64
+		max    float64
+		active bool
+	}{
+		{0, 10, 10, true},
+		{0.5, 18.75, 18.75, true}, // ease-out: 7/8 of the way at half time
+		{0.25, 18.75, 20, true},
+		{1, 20, 20, false}, // overshooting the duration lands on the target
+		{1, 20, 20, false},
+	} {
+		v, active := a.TickValue(tc.dt)
+		if v < tc.min-1e-6 || v > tc.max+1e-6 || active != tc.active {
+			t.Errorf("after %v s: value %v (active %v), want %v..%v (active %v)", tc.dt, v, active, tc.min, tc.max, tc.active)
+		}
+	}
+}
+
+func TestAnimator_RetargetStartsFromCurrent(t *testing.T) {
+	a := NewAnimator()
+	a.SetValue(0)
+	a.StartValueMove(100, 1)
+	mid, _ := a.TickValue(0.5)
+	a.StartValueMove(0, 1)
+	if a.Value.From != mid {
+		t.Errorf("retarget starts from %v, want the current %v", a.Value.From, mid)
+	}
+	if v, _ := a.TickValue(0.01); v > mid || v < mid-5 {
+		t.Errorf("retargeted value jumped from %v to %v", mid, v)
+	}
+}
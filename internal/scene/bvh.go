@@ -0,0 +1,251 @@
+package scene
+
+import (
+	"math"
+	"sort"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// bvhLeafSize is the maximum number of scene nodes in a BVH leaf.
+const bvhLeafSize = 4
+
+// bvhNode is one box in the hierarchy. Leaves reference items[start:start+count];
+// inner nodes have count == 0 and two children at left and left+1.
+type bvhNode struct {
+	bounds rl.BoundingBox
+	left   int32
+	start  int32
+	count  int32
+}
+
+// BVH is a bounding volume hierarchy over scene node boxes, used to answer
+// ray picks and region queries without testing every node. Nodes inserted
+// after the build are tested one by one, and removed ones are skipped,
+// until the next build.
+type BVH struct {
+	nodes   []bvhNode
+	items   []*SceneNode
+	extra   []*SceneNode
+	removed map[*SceneNode]bool
+}
+
+// NewBVH builds a hierarchy over the given nodes by median split along the
+// longest axis of each box's centroids.
+func NewBVH(nodes []*SceneNode) *BVH {
+	b := &BVH{items: append([]*SceneNode(nil), nodes...)}
+	if len(b.items) == 0 {
+		return b
+	}
+	b.nodes = make([]bvhNode, 1, 2*len(b.items)/bvhLeafSize+1)
+	b.build(0, 0, len(b.items))
+	return b
+}
+
+func (b *BVH) build(index, start, end int) {
+	items := b.items[start:end]
+	bounds := items[0].Bounds
+	cmin, cmax := center(items[0].Bounds), center(items[0].Bounds)
+	for _, n := range items[1:] {
+		bounds.Min = rl.Vector3Min(bounds.Min, n.Bounds.Min)
+		bounds.Max = rl.Vector3Max(bounds.Max, n.Bounds.Max)
+		c := center(n.Bounds)
+		cmin = rl.Vector3Min(cmin, c)
+		cmax = rl.Vector3Max(cmax, c)
+	}
+	b.nodes[index].bounds = bounds
+
+	if len(items) <= bvhLeafSize {
+		b.nodes[index].start = int32(start)
+		b.nodes[index].count = int32(len(items))
+		return
+	}
+
+	// Split at the median along the axis with the widest centroid spread
+	extent := rl.Vector3Subtract(cmax, cmin)
+	axis := func(v rl.Vector3) float32 { return v.X }
+	if extent.Y > extent.X && extent.Y >= extent.Z {
+		axis = func(v rl.Vector3) float32 { return v.Y }
+	} else if extent.Z > extent.X && extent.Z > extent.Y {
+		axis = func(v rl.Vector3) float32 { return v.Z }
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return axis(center(items[i].Bounds)) < axis(center(items[j].Bounds))
+	})
+	mid := start + len(items)/2
+
+	left := len(b.nodes)
+	b.nodes = append(b.nodes, bvhNode{}, bvhNode{})
+	b.nodes[index].left = int32(left)
+	b.build(left, start, mid)
+	b.build(left+1, mid, end)
+}
+
+// Refit recomputes every box in the hierarchy from the current bounds of
+// its nodes, keeping the tree's shape. It is much cheaper than a rebuild and
+// keeps queries exact while nodes move; only pruning gets looser the further
+// they stray from where the tree was built.
+func (b *BVH) Refit() {
+	// Children always come after their parent, so a reverse pass is bottom-up
+	for i := len(b.nodes) - 1; i >= 0; i-- {
+		n := &b.nodes[i]
+		if n.count == 0 {
+			l, r := b.nodes[n.left].bounds, b.nodes[n.left+1].bounds
+			n.bounds = rl.BoundingBox{Min: rl.Vector3Min(l.Min, r.Min), Max: rl.Vector3Max(l.Max, r.Max)}
+			continue
+		}
+		items := b.items[n.start : n.start+n.count]
+		n.bounds = items[0].Bounds
+		for _, item := range items[1:] {
+			n.bounds.Min = rl.Vector3Min(n.bounds.Min, item.Bounds.Min)
+			n.bounds.Max = rl.Vector3Max(n.bounds.Max, item.Bounds.Max)
+		}
+	}
+}
+
+// Insert adds nodes to the hierarchy without rebuilding it.
+func (b *BVH) Insert(nodes []*SceneNode) {
+	b.extra = append(b.extra, nodes...)
+}
+
+// Remove drops nodes from the hierarchy without rebuilding it.
+func (b *BVH) Remove(nodes []*SceneNode) {
+	if len(nodes) == 0 {
+		return
+	}
+	if b.removed == nil {
+		b.removed = make(map[*SceneNode]bool, len(nodes))
+	}
+	for _, n := range nodes {
+		b.removed[n] = true
+	}
+}
+
+// pending returns how many insertions and removals wait for a rebuild.
+func (b *BVH) pending() int {
+	return len(b.extra) + len(b.removed)
+}
+
+func center(box rl.BoundingBox) rl.Vector3 {
+	return rl.Vector3Scale(rl.Vector3Add(box.Min, box.Max), 0.5)
+}
+
+// Raycast returns the closest node hit by the ray for which accept returns
+// true, and the hit distance. Hits are measured with RayNode and ties broken
+// by Order, so results match a linear scan exactly.
+func (b *BVH) Raycast(ray rl.Ray, accept func(*SceneNode) bool) (*SceneNode, float32) {
+	var closest *SceneNode
+	closestDist := float32(math.MaxFloat32)
+	test := func(item *SceneNode) {
+		if b.removed[item] || (accept != nil && !accept(item)) {
+			return
+		}
+		c := RayNode(ray, item)
+		if !c.Hit {
+			return
+		}
+		// Ties go to the earlier node in traversal order, like a linear scan
+		if c.Distance < closestDist || (c.Distance == closestDist && item.Order < closest.Order) {
+			closestDist = c.Distance
+			closest = item
+		}
+	}
+	if len(b.nodes) == 0 {
+		for _, item := range b.extra {
+			test(item)
+		}
+		return closest, closestDist
+	}
+
+	inv := rl.NewVector3(1/ray.Direction.X, 1/ray.Direction.Y, 1/ray.Direction.Z)
+	stack := make([]int32, 0, 64)
+	stack = append(stack, 0)
+	for len(stack) > 0 {
+		n := &b.nodes[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+
+		// The slack keeps float rounding in slab from pruning a tie
+		tmin, hit := slab(ray.Position, inv, n.bounds)
+		if !hit || tmin > closestDist+1e-4 {
+			continue
+		}
+		if n.count == 0 {
+			// Visit the nearer child first so the far one is more often pruned
+			l, r := n.left, n.left+1
+			tl, _ := slab(ray.Position, inv, b.nodes[l].bounds)
+			tr, _ := slab(ray.Position, inv, b.nodes[r].bounds)
+			if tl < tr {
+				l, r = r, l
+			}
+			stack = append(stack, l, r)
+			continue
+		}
+		for _, item := range b.items[n.start : n.start+n.count] {
+			test(item)
+		}
+	}
+	for _, item := range b.extra {
+		test(item)
+	}
+	return closest, closestDist
+}
+
+// slab returns the ray's entry distance into the box (negative when the
+// origin is inside) and whether the ray's forward half hits it at all.
+func slab(origin, inv rl.Vector3, box rl.BoundingBox) (float32, bool) {
+	tmin := float32(math.Inf(-1))
+	tmax := float32(math.Inf(1))
+	axes := [3][4]float32{
+		{origin.X, inv.X, box.Min.X, box.Max.X},
+		{origin.Y, inv.Y, box.Min.Y, box.Max.Y},
+		{origin.Z, inv.Z, box.Min.Z, box.Max.Z},
+	}
+	for _, a := range axes {
+		t1 := (a[2] - a[0]) * a[1]
+		t2 := (a[3] - a[0]) * a[1]
+		if math.IsNaN(float64(t1)) || math.IsNaN(float64(t2)) {
+			// Ray parallel to the slab and lying on its face: treat as inside
+			continue
+		}
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		if t1 > tmin {
+			tmin = t1
+		}
+		if t2 < tmax {
+			tmax = t2
+		}
+	}
+	return tmin, tmax >= 0 && tmin <= tmax
+}
+
+// Query calls fn for every node whose box passes leaf, descending only into
+// subtrees whose combined bounds pass overlaps.
+func (b *BVH) Query(overlaps func(rl.BoundingBox) bool, leaf func(*SceneNode) bool, fn func(*SceneNode)) {
+	for _, item := range b.extra {
+		if !b.removed[item] && overlaps(item.Bounds) && leaf(item) {
+			fn(item)
+		}
+	}
+	if len(b.nodes) == 0 {
+		return
+	}
+	stack := []int32{0}
+	for len(stack) > 0 {
+		n := &b.nodes[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+		if !overlaps(n.bounds) {
+			continue
+		}
+		if n.count == 0 {
+			stack = append(stack, n.left, n.left+1)
+			continue
+		}
+		for _, item := range b.items[n.start : n.start+n.count] {
+			if !b.removed[item] && leaf(item) {
+				fn(item)
+			}
+		}
+	}
+}
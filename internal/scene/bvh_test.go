@@ -0,0 +1,200 @@
+package scene
+
+import (
+	"math/rand"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+	"github.com/Crank-Git/FSNRedux/internal/layout"
+)
+
+// syntheticGraph lays out a synthetic tree with every directory expanded
+// except every fifth one, so some laid-out nodes are hidden.
+func syntheticGraph(files int) *Graph {
+	tree := fs.SyntheticTree(files, 40, 4)
+	expanded := make(map[string]bool)
+	var walk func(e *fs.Entry)
+	walk = func(e *fs.Entry) {
+		if !e.IsDir() {
+			return
+		}
+		expanded[e.Path] = true
+		for _, c := range e.Children {
+			walk(c)
+		}
+	}
+	walk(tree.Root)
+	opts := layout.DefaultOptions(layout.ModeTreeV)
+	opts.ExpandedPaths = expanded
+	g := NewGraph(layout.Compute(tree, opts), expanded)
+
+	// Collapse some directories after layout and fade a few nodes out
+	var all []*SceneNode
+	var collect func(n *SceneNode)
+	collect = func(n *SceneNode) {
+		all = append(all, n)
+		for _, c := range n.Children {
+			collect(c)
+		}
+	}
+	collect(g.Root)
+	for i, node := range all {
+		if node != g.Root && len(node.Children) > 0 && i%5 == 0 {
+			node.Expanded = false
+		}
+		if i%17 == 0 {
+			node.Fade = 1
+		}
+	}
+	return g
+}
+
+// pickLinear is the brute-force picker: ray-test every shown node.
+func pickLinear(g *Graph, ray rl.Ray) *SceneNode {
+	var closest *SceneNode
+	closestDist := float32(1e30)
+	g.Traverse(func(node *SceneNode) bool {
+		if node.Fade >= 1 {
+			return true
+		}
+		c := rl.GetRayCollisionBox(ray, node.Bounds)
+		if c.Hit && c.Distance < closestDist {
+			closestDist = c.Distance
+			closest = node
+		}
+		return true
+	})
+	return closest
+}
+
+// randomRay aims from a random point above the scene at a random node.
+func randomRay(g *Graph, rnd *rand.Rand) rl.Ray {
+	b := g.Root.SubtreeBounds
+	origin := rl.NewVector3(
+		b.Min.X+rnd.Float32()*(b.Max.X-b.Min.X),
+		2+rnd.Float32()*20,
+		b.Min.Z+rnd.Float32()*(b.Max.Z-b.Min.Z)+10,
+	)
+	target := g.NodeIndex[g.Root.ID+uint32(rnd.Intn(g.NodeCount))].Position
+	target.X += rnd.Float32() - 0.5
+	target.Z += rnd.Float32() - 0.5
+	return rl.Ray{Position: origin, Direction: rl.Vector3Normalize(rl.Vector3Subtract(target, origin))}
+}
+
+func TestPick_MatchesBruteForce(t *testing.T) {
+	g := syntheticGraph(3000)
+	rnd := rand.New(rand.NewSource(1))
+
+	hits := 0
+	for i := 0; i < 2000; i++ {
+		ray := randomRay(g, rnd)
+		want := pickLinear(g, ray)
+		got := g.Pick(ray)
+		if got != want {
+			t.Fatalf("ray %d: Pick = %v, brute force = %v", i, nodePath(got), nodePath(want))
+		}
+		if got != nil {
+			hits++
+		}
+	}
+	if hits < 500 {
+		t.Errorf("only %d of 2000 rays hit anything; test rays are not exercising the BVH", hits)
+	}
+}
+
+func TestBVH_RefitFollowsMovedNodes(t *testing.T) {
+	g := syntheticGraph(2000)
+	for _, node := range g.NodeIndex {
+		node.Position.X = node.Position.X*1.5 + 3
+		node.Position.Z -= 2
+		node.ComputeBounds()
+	}
+	g.summarize(g.Root)
+	g.bvh.Refit()
+
+	rnd := rand.New(rand.NewSource(2))
+	for i := 0; i < 1000; i++ {
+		ray := randomRay(g, rnd)
+		if got, want := g.Pick(ray), pickLinear(g, ray); got != want {
+			t.Fatalf("ray %d after refit: Pick = %v, brute force = %v", i, nodePath(got), nodePath(want))
+		}
+	}
+}
+
+func TestPick_AxisAlignedRays(t *testing.T) {
+	g := syntheticGraph(500)
+	for _, node := range []*SceneNode{g.Root, g.Root.Children[0], g.Root.Children[len(g.Root.Children)-1]} {
+		ray := rl.Ray{Position: rl.NewVector3(node.Position.X, 50, node.Position.Z), Direction: rl.NewVector3(0, -1, 0)}
+		if got, want := g.Pick(ray), pickLinear(g, ray); got != want {
+			t.Errorf("vertical ray over %s: Pick = %v, brute force = %v", nodePath(node), nodePath(got), nodePath(want))
+		}
+	}
+}
+
+func TestSelectInRect_MatchesBruteForce(t *testing.T) {
+	g := syntheticGraph(3000)
+	b := g.Root.SubtreeBounds
+	cam := rl.Camera3D{
+		Position: rl.NewVector3((b.Min.X+b.Max.X)/2, 40, b.Max.Z+30),
+		Target:   rl.NewVector3((b.Min.X+b.Max.X)/2, 0, (b.Min.Z+b.Max.Z)/2),
+		Up:       rl.NewVector3(0, 1, 0),
+		Fovy:     50,
+	}
+	view := NewView(cam, 1280, 800)
+	rnd := rand.New(rand.NewSource(2))
+
+	for i := 0; i < 50; i++ {
+		rect := rl.NewRectangle(rnd.Float32()*1000, rnd.Float32()*600, 20+rnd.Float32()*400, 20+rnd.Float32()*300)
+
+		var want []*SceneNode
+		g.Traverse(func(node *SceneNode) bool {
+			if p, ok := view.Project(node.Position); ok && node.Fade < 1 && pointInRect(p, rect) {
+				want = append(want, node)
+			}
+			return true
+		})
+		got := g.SelectInRect(view, rect)
+		if len(got) != len(want) {
+			t.Fatalf("rect %v: selected %d nodes, brute force %d", rect, len(got), len(want))
+		}
+		for j := range got {
+			if got[j] != want[j] {
+				t.Fatalf("rect %v: node %d = %s, brute force %s", rect, j, nodePath(got[j]), nodePath(want[j]))
+			}
+		}
+	}
+}
+
+func nodePath(n *SceneNode) string {
+	if n == nil {
+		return "<nil>"
+	}
+	return n.Entry.Path
+}
+
+func BenchmarkPick(b *testing.B) {
+	g := syntheticGraph(200000)
+	rnd := rand.New(rand.NewSource(3))
+	rays := make([]rl.Ray, 256)
+	for i := range rays {
+		rays[i] = randomRay(g, rnd)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.Pick(rays[i%len(rays)])
+	}
+}
+
+func BenchmarkPickLinear(b *testing.B) {
+	g := syntheticGraph(200000)
+	rnd := rand.New(rand.NewSource(3))
+	rays := make([]rl.Ray, 256)
+	for i := range rays {
+		rays[i] = randomRay(g, rnd)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pickLinear(g, rays[i%len(rays)])
+	}
+}
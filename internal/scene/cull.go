@@ -0,0 +1,195 @@
+package scene
+
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// DefaultLODPixels is the on-screen size below which an expanded directory is
+// drawn as a single aggregated block.
+const DefaultLODPixels = 6
+
+// View is the camera state used for frustum culling and level of detail.
+type View struct {
+	Frustum   Frustum
+	Eye       rl.Vector3
+	LODPixels float32 // 0 disables level of detail
+
+	pixelsPerUnit float32 // screen pixels spanned by one world unit at distance 1
+
+	// Camera basis and viewport, for projecting points to the screen
+	fwd, right, up   rl.Vector3
+	screenW, screenH float32
+}
+
+// NewView captures a camera and viewport for culling.
+func NewView(cam rl.Camera3D, screenW, screenH int32) *View {
+	if screenH <= 0 {
+		screenH = 1
+	}
+	tanV := float32(math.Tan(float64(cam.Fovy) * math.Pi / 360))
+	fwd := rl.Vector3Normalize(rl.Vector3Subtract(cam.Target, cam.Position))
+	right := rl.Vector3Normalize(rl.Vector3CrossProduct(fwd, cam.Up))
+	return &View{
+		Frustum:       NewFrustum(cam, float32(screenW)/float32(screenH)),
+		Eye:           cam.Position,
+		LODPixels:     DefaultLODPixels,
+		pixelsPerUnit: float32(screenH) / 2 / tanV,
+		fwd:           fwd,
+		right:         right,
+		up:            rl.Vector3CrossProduct(right, fwd),
+		screenW:       float32(screenW),
+		screenH:       float32(screenH),
+	}
+}
+
+// Project maps a world point to screen coordinates (like rl.GetWorldToScreen,
+// without a cgo call). ok is false for points behind the near plane.
+func (v *View) Project(p rl.Vector3) (rl.Vector2, bool) {
+	d := rl.Vector3Subtract(p, v.Eye)
+	z := rl.Vector3DotProduct(d, v.fwd)
+	if z < nearClip {
+		return rl.Vector2{}, false
+	}
+	s := v.pixelsPerUnit / z
+	return rl.NewVector2(
+		v.screenW/2+rl.Vector3DotProduct(d, v.right)*s,
+		v.screenH/2-rl.Vector3DotProduct(d, v.up)*s,
+	), true
+}
+
+// Basis returns the camera's right and up unit vectors, e.g. for billboards.
+func (v *View) Basis() (right, up rl.Vector3) {
+	return v.right, v.up
+}
+
+// BoxMayOverlapRect reports whether a box's screen projection may overlap a
+// screen rectangle. Boxes crossing the near plane are assumed to overlap.
+func (v *View) BoxMayOverlapRect(b rl.BoundingBox, rect rl.Rectangle) bool {
+	minX, minY := float32(math.Inf(1)), float32(math.Inf(1))
+	maxX, maxY := float32(math.Inf(-1)), float32(math.Inf(-1))
+	for i := 0; i < 8; i++ {
+		corner := b.Min
+		if i&1 != 0 {
+			corner.X = b.Max.X
+		}
+		if i&2 != 0 {
+			corner.Y = b.Max.Y
+		}
+		if i&4 != 0 {
+			corner.Z = b.Max.Z
+		}
+		p, ok := v.Project(corner)
+		if !ok {
+			return true
+		}
+		minX, maxX = min(minX, p.X), max(maxX, p.X)
+		minY, maxY = min(minY, p.Y), max(maxY, p.Y)
+	}
+	return maxX >= rect.X && minX <= rect.X+rect.Width &&
+		maxY >= rect.Y && minY <= rect.Y+rect.Height
+}
+
+// pointInRect reports whether p lies inside rect (edges included).
+func pointInRect(p rl.Vector2, rect rl.Rectangle) bool {
+	return p.X >= rect.X && p.X <= rect.X+rect.Width &&
+		p.Y >= rect.Y && p.Y <= rect.Y+rect.Height
+}
+
+// ScreenSize estimates how many pixels a box spans on screen.
+func (v *View) ScreenSize(b rl.BoundingBox) float32 {
+	center := rl.Vector3Scale(rl.Vector3Add(b.Min, b.Max), 0.5)
+	radius := rl.Vector3Distance(b.Min, b.Max) / 2
+	dist := rl.Vector3Distance(center, v.Eye)
+	if dist <= radius {
+		return float32(math.Inf(1)) // eye inside or touching the box
+	}
+	return 2 * radius / dist * v.pixelsPerUnit
+}
+
+// aggregates reports whether an expanded node is small enough to collapse.
+func (v *View) aggregates(node *SceneNode) bool {
+	return v.LODPixels > 0 && node.Expanded && len(node.Children) > 0 &&
+		v.ScreenSize(node.SubtreeBounds) < v.LODPixels
+}
+
+// Cull calls fn for every visible node in Traverse order whose subtree
+// intersects the view frustum. Expanded directories too small on screen are
+// reported with aggregate set and their children skipped. If fn returns
+// false, children of that node are skipped. A nil view culls nothing.
+func (g *Graph) Cull(view *View, fn func(node *SceneNode, aggregate bool) bool) {
+	if g.Root == nil {
+		return
+	}
+	if view == nil {
+		g.Traverse(func(node *SceneNode) bool { return fn(node, false) })
+		return
+	}
+	cullNode(g.Root, view, fn)
+}
+
+func cullNode(node *SceneNode, view *View, fn func(*SceneNode, bool) bool) {
+	if !node.Visible || !view.Frustum.IntersectsBox(node.SubtreeBounds) {
+		return
+	}
+	if view.aggregates(node) {
+		fn(node, true)
+		return
+	}
+	if !fn(node, false) || !node.Expanded {
+		return
+	}
+	for _, child := range node.Children {
+		cullNode(child, view, fn)
+	}
+}
+
+// Span is a half-open range of node Order indices.
+type Span struct {
+	Start, End int
+}
+
+// VisibleSpans returns the merged Order ranges of nodes that survive culling
+// at directory granularity (a directory's leaf children are kept or dropped
+// with it), plus the directories to draw as aggregated blocks. Ranges are
+// ascending, so they index straight into data recorded in Traverse order.
+func (g *Graph) VisibleSpans(view *View) ([]Span, []*SceneNode) {
+	var spans []Span
+	var aggregates []*SceneNode
+	add := func(start, end int) {
+		if n := len(spans); n > 0 && spans[n-1].End == start {
+			spans[n-1].End = end
+			return
+		}
+		spans = append(spans, Span{Start: start, End: end})
+	}
+
+	var visit func(node *SceneNode)
+	visit = func(node *SceneNode) {
+		if !node.Visible || !view.Frustum.IntersectsBox(node.SubtreeBounds) {
+			return
+		}
+		if view.aggregates(node) {
+			aggregates = append(aggregates, node)
+			return
+		}
+		add(node.Order, node.Order+1)
+		if !node.Expanded {
+			return
+		}
+		for _, child := range node.Children {
+			if len(child.Children) == 0 {
+				if child.Visible {
+					add(child.Order, child.SubtreeEnd)
+				}
+				continue
+			}
+			visit(child)
+		}
+	}
+	if g.Root != nil {
+		visit(g.Root)
+	}
+	return spans, aggregates
+}
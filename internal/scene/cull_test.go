@@ -0,0 +1,142 @@
+package scene
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+	"github.com/Crank-Git/FSNRedux/internal/layout"
+)
+
+// testCamera looks down -Z from z=10.
+func testCamera() rl.Camera3D {
+	return rl.Camera3D{
+		Position:   rl.NewVector3(0, 0, 10),
+		Target:     rl.NewVector3(0, 0, 0),
+		Up:         rl.NewVector3(0, 1, 0),
+		Fovy:       50,
+		Projection: rl.CameraPerspective,
+	}
+}
+
+func box(x, y, z, half float32) rl.BoundingBox {
+	return rl.BoundingBox{
+		Min: rl.NewVector3(x-half, y-half, z-half),
+		Max: rl.NewVector3(x+half, y+half, z+half),
+	}
+}
+
+func TestFrustum_ContainsAndIntersects(t *testing.T) {
+	f := NewFrustum(testCamera(), 1)
+
+	if !f.Contains(rl.NewVector3(0, 0, 0)) {
+		t.Error("target should be inside")
+	}
+	if f.Contains(rl.NewVector3(0, 0, 20)) {
+		t.Error("point behind the camera should be outside")
+	}
+	if f.Contains(rl.NewVector3(100, 0, 0)) {
+		t.Error("point far to the side should be outside")
+	}
+	if !f.IntersectsBox(box(5, 0, 0, 1)) {
+		t.Error("box straddling the right plane should intersect")
+	}
+	if f.IntersectsBox(box(50, 0, 0, 1)) {
+		t.Error("box well outside should not intersect")
+	}
+}
+
+// testGraph builds root -> {near dir with 3 files, far dir with 3 files}.
+func testGraph(farX float32) *Graph {
+	dir := func(path string, x float32) *layout.Node {
+		n := &layout.Node{
+			Entry:    &fs.Entry{Name: path, Path: path, Type: fs.TypeDir},
+			Position: rl.NewVector3(x, 0, 0),
+			Size:     rl.NewVector3(2, 0.1, 2),
+		}
+		for i := 0; i < 3; i++ {
+			n.Children = append(n.Children, &layout.Node{
+				Entry:    &fs.Entry{Name: "f", Path: path + "/f" + string(rune('0'+i)), Type: fs.TypeFile},
+				Position: rl.NewVector3(x-0.5+float32(i)*0.5, 0.1, 0),
+				Size:     rl.NewVector3(0.4, 0.1, 0.4),
+				Color:    rl.NewColor(200, 100, 0, 255),
+			})
+		}
+		return n
+	}
+	root := &layout.Node{
+		Entry:    &fs.Entry{Name: "root", Path: "/r", Type: fs.TypeDir},
+		Size:     rl.NewVector3(1, 0.1, 1),
+		Children: []*layout.Node{dir("/r/near", 0), dir("/r/far", farX)},
+	}
+	expanded := map[string]bool{"/r": true, "/r/near": true, "/r/far": true}
+	return NewGraph(root, expanded)
+}
+
+func TestNewGraph_SubtreeSummaries(t *testing.T) {
+	g := testGraph(100)
+
+	if g.Root.Order != 0 || g.Root.SubtreeEnd != g.NodeCount {
+		t.Errorf("root order span = [%d,%d), want [0,%d)", g.Root.Order, g.Root.SubtreeEnd, g.NodeCount)
+	}
+	if g.Root.SubtreeBounds.Max.X < 100 {
+		t.Errorf("root subtree bounds %v should include the far dir", g.Root.SubtreeBounds)
+	}
+	far := g.FindByPath("/r/far")
+	if far.AggregateColor != rl.NewColor(200, 100, 0, 255) {
+		t.Errorf("AggregateColor = %v, want the mean file color", far.AggregateColor)
+	}
+}
+
+func TestCull_SkipsOffscreenSubtrees(t *testing.T) {
+	g := testGraph(100)
+	view := NewView(testCamera(), 800, 800)
+
+	seen := make(map[string]bool)
+	g.Cull(view, func(node *SceneNode, _ bool) bool {
+		seen[node.Entry.Path] = true
+		return true
+	})
+	if !seen["/r/near"] || !seen["/r/near/f1"] {
+		t.Error("on-screen subtree should be visited")
+	}
+	if seen["/r/far"] || seen["/r/far/f1"] {
+		t.Error("off-screen subtree should be culled")
+	}
+
+	// A nil view visits everything Traverse does
+	count := 0
+	g.Cull(nil, func(*SceneNode, bool) bool { count++; return true })
+	if count != g.VisibleNodeCount() {
+		t.Errorf("nil view visited %d nodes, want %d", count, g.VisibleNodeCount())
+	}
+}
+
+func TestVisibleSpans_AllVisible(t *testing.T) {
+	g := testGraph(3)
+	view := NewView(testCamera(), 800, 800)
+	view.LODPixels = 0
+
+	spans, aggregates := g.VisibleSpans(view)
+	if len(aggregates) != 0 {
+		t.Errorf("got %d aggregates with LOD off", len(aggregates))
+	}
+	if len(spans) != 1 || spans[0] != (Span{Start: 0, End: g.NodeCount}) {
+		t.Errorf("spans = %v, want one span covering all %d nodes", spans, g.NodeCount)
+	}
+}
+
+func TestVisibleSpans_AggregatesDistantDirs(t *testing.T) {
+	g := testGraph(3)
+	cam := testCamera()
+	cam.Position.Z = 900 // every directory is a speck
+	view := NewView(cam, 800, 800)
+
+	spans, aggregates := g.VisibleSpans(view)
+	if len(aggregates) != 1 || aggregates[0] != g.Root {
+		t.Fatalf("aggregates = %v, want just the root", aggregates)
+	}
+	if len(spans) != 0 {
+		t.Errorf("spans = %v, want none when the root is aggregated", spans)
+	}
+}
@@ -0,0 +1,57 @@
+package scene
+
+import "math"
+
+// Easing maps linear progress t in [0,1] to eased progress (0 at t=0, 1 at t=1).
+type Easing func(t float32) float32
+
+// Linear applies no easing.
+func Linear(t float32) float32 {
+	return t
+}
+
+// EaseInQuad starts slowly and accelerates.
+func EaseInQuad(t float32) float32 {
+	return t * t
+}
+
+// EaseOutQuad decelerates into the target.
+func EaseOutQuad(t float32) float32 {
+	return 1 - (1-t)*(1-t)
+}
+
+// EaseInOutQuad accelerates then decelerates.
+func EaseInOutQuad(t float32) float32 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+	u := -2*t + 2
+	return 1 - u*u/2
+}
+
+// EaseInCubic starts slowly and accelerates harder than EaseInQuad.
+func EaseInCubic(t float32) float32 {
+	return t * t * t
+}
+
+// EaseOutCubic decelerates into the target, so retargeting mid-move stays smooth.
+func EaseOutCubic(t float32) float32 {
+	u := 1 - t
+	return 1 - u*u*u
+}
+
+// EaseInOutCubic provides smooth acceleration and deceleration.
+func EaseInOutCubic(t float32) float32 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	return 1 - float32(math.Pow(float64(-2*t+2), 3))/2
+}
+
+// EaseOutBack overshoots the target slightly before settling.
+func EaseOutBack(t float32) float32 {
+	const c1 = 1.70158
+	const c3 = c1 + 1
+	u := t - 1
+	return 1 + c3*u*u*u + c1*u*u
+}
@@ -0,0 +1,88 @@
+package scene
+
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Clip distances used by rl.BeginMode3D (RL_CULL_DISTANCE_NEAR/FAR).
+const (
+	nearClip = 0.01
+	farClip  = 1000
+)
+
+// plane is a half-space: points with Normal·p + D >= 0 are inside.
+type plane struct {
+	Normal rl.Vector3
+	D      float32
+}
+
+func newPlane(normal, point rl.Vector3) plane {
+	n := rl.Vector3Normalize(normal)
+	return plane{Normal: n, D: -rl.Vector3DotProduct(n, point)}
+}
+
+func (p plane) distance(v rl.Vector3) float32 {
+	return rl.Vector3DotProduct(p.Normal, v) + p.D
+}
+
+// Frustum is the volume visible through a perspective camera.
+type Frustum struct {
+	planes [6]plane // near, far, left, right, bottom, top; normals point inward
+}
+
+// NewFrustum builds the view frustum of a perspective camera with the given
+// viewport aspect ratio (width / height).
+func NewFrustum(cam rl.Camera3D, aspect float32) Frustum {
+	fwd := rl.Vector3Normalize(rl.Vector3Subtract(cam.Target, cam.Position))
+	right := rl.Vector3Normalize(rl.Vector3CrossProduct(fwd, cam.Up))
+	up := rl.Vector3CrossProduct(right, fwd)
+
+	tanV := float32(math.Tan(float64(cam.Fovy) * math.Pi / 360))
+	tanH := tanV * aspect
+	eye := cam.Position
+
+	scale := func(v rl.Vector3, s float32) rl.Vector3 { return rl.Vector3Scale(v, s) }
+	add := rl.Vector3Add
+	return Frustum{planes: [6]plane{
+		newPlane(fwd, add(eye, scale(fwd, nearClip))),
+		newPlane(scale(fwd, -1), add(eye, scale(fwd, farClip))),
+		newPlane(add(right, scale(fwd, tanH)), eye),
+		newPlane(add(scale(right, -1), scale(fwd, tanH)), eye),
+		newPlane(add(up, scale(fwd, tanV)), eye),
+		newPlane(add(scale(up, -1), scale(fwd, tanV)), eye),
+	}}
+}
+
+// Contains reports whether a point is inside the frustum.
+func (f *Frustum) Contains(p rl.Vector3) bool {
+	for _, pl := range f.planes {
+		if pl.distance(p) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IntersectsBox reports whether any part of the box may be inside the
+// frustum. It is conservative: boxes near a frustum corner can pass.
+func (f *Frustum) IntersectsBox(b rl.BoundingBox) bool {
+	for _, pl := range f.planes {
+		// Test the box corner furthest along the plane normal
+		p := b.Min
+		if pl.Normal.X >= 0 {
+			p.X = b.Max.X
+		}
+		if pl.Normal.Y >= 0 {
+			p.Y = b.Max.Y
+		}
+		if pl.Normal.Z >= 0 {
+			p.Z = b.Max.Z
+		}
+		if pl.distance(p) < 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -1,9 +1,12 @@
 package scene
 
 import (
+	"sort"
 	"sync/atomic"
+	"time"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/fs"
 	"github.com/Crank-Git/FSNRedux/internal/layout"
 )
 
@@ -15,6 +18,30 @@ type Graph struct {
 	NodeIndex  map[uint32]*SceneNode
 	NodeByPath map[string]*SceneNode
 	NodeCount  int
+
+	// Version is bumped whenever node colors or fades change in place, so
+	// the renderer knows when its cached batches are stale.
+	Version uint64
+
+	// Moved is bumped when nodes only move or resize, so the renderer can
+	// update the boxes of its cached batches instead of rebuilding them.
+	Moved uint64
+
+	// Spatial index over every node's box, for picking and region queries
+	bvh *BVH
+
+	// Nodes moved by the running tweens and their ancestors, whose
+	// aggregate colors catch up when the tweens end
+	tweened map[*SceneNode]bool
+
+	// Expand/collapse animation after Apply. Leaving holds removed nodes
+	// still shrinking away; they are drawn but not part of the hierarchy.
+	tweens  *Tweener
+	Leaving []*SceneNode
+
+	// Memoized subtree timestamps for ApplyVirtualNow
+	earliest      map[*fs.Entry]time.Time
+	earliestField fs.TimeField
 }
 
 // NewGraph creates a Graph from a layout tree.
@@ -33,6 +60,8 @@ func NewGraph(layoutRoot *layout.Node, expandedPaths map[string]bool) *Graph {
 	}
 
 	g.Root = g.buildNode(layoutRoot, nil, expandedPaths)
+	g.summarize(g.Root)
+	g.RebuildBVH()
 	return g
 }
 
@@ -40,26 +69,31 @@ func (g *Graph) buildNode(ln *layout.Node, parent *SceneNode, expandedPaths map[
 	id := nextID.Add(1)
 
 	expanded := false
-	if ln.Entry != nil {
-		expanded = expandedPaths[ln.Entry.Path]
+	if path := ln.Path(); path != "" {
+		expanded = expandedPaths[path]
 	}
 
 	node := &SceneNode{
+		source:   ln,
 		ID:       id,
 		Entry:    ln.Entry,
 		Position: ln.Position,
 		Size:     ln.Size,
 		Color:    ln.Color,
+		Alpha:    1,
 		Visible:  true,
 		Expanded: expanded,
 		Depth:    ln.Depth,
+		Sector:   ownSector(ln.Sector),
+		Group:    ln.Group,
 		Parent:   parent,
+		Order:    g.NodeCount,
 	}
 	node.ComputeBounds()
 
 	g.NodeIndex[id] = node
-	if ln.Entry != nil {
-		g.NodeByPath[ln.Entry.Path] = node
+	if path := ln.Path(); path != "" {
+		g.NodeByPath[path] = node
 	}
 	g.NodeCount++
 
@@ -67,10 +101,51 @@ func (g *Graph) buildNode(ln *layout.Node, parent *SceneNode, expandedPaths map[
 		child := g.buildNode(childLayout, node, expandedPaths)
 		node.Children = append(node.Children, child)
 	}
+	node.SubtreeEnd = g.NodeCount
 
 	return node
 }
 
+// colorSum accumulates file colors for AggregateColor.
+type colorSum struct {
+	r, g, b, a, n uint64
+}
+
+// summarize computes SubtreeBounds and AggregateColor bottom-up.
+func (g *Graph) summarize(node *SceneNode) colorSum {
+	return g.resummarize(node, nil)
+}
+
+// resummarize recomputes SubtreeBounds and AggregateColor of node and of
+// the descendants in dirty, reusing the summaries of the other subtrees. A
+// nil dirty recomputes every node.
+func (g *Graph) resummarize(node *SceneNode, dirty map[*SceneNode]bool) colorSum {
+	if dirty != nil && !dirty[node] {
+		return node.files
+	}
+	var sum colorSum
+	node.SubtreeBounds = node.Bounds
+	if len(node.Children) == 0 && (node.Entry == nil || !node.Entry.IsDir()) {
+		sum = colorSum{uint64(node.Color.R), uint64(node.Color.G), uint64(node.Color.B), uint64(node.Color.A), 1}
+	}
+	for _, child := range node.Children {
+		cs := g.resummarize(child, dirty)
+		sum.r += cs.r
+		sum.g += cs.g
+		sum.b += cs.b
+		sum.a += cs.a
+		sum.n += cs.n
+		node.SubtreeBounds.Min = rl.Vector3Min(node.SubtreeBounds.Min, child.SubtreeBounds.Min)
+		node.SubtreeBounds.Max = rl.Vector3Max(node.SubtreeBounds.Max, child.SubtreeBounds.Max)
+	}
+	node.AggregateColor = node.Color
+	if sum.n > 0 {
+		node.AggregateColor = rl.NewColor(uint8(sum.r/sum.n), uint8(sum.g/sum.n), uint8(sum.b/sum.n), uint8(sum.a/sum.n))
+	}
+	node.files = sum
+	return sum
+}
+
 // Traverse calls fn for every visible node in depth-first order.
 // If fn returns false, children of that node are skipped.
 func (g *Graph) Traverse(fn func(node *SceneNode) bool) {
@@ -94,25 +169,72 @@ func traverseNode(node *SceneNode, fn func(*SceneNode) bool) {
 	}
 }
 
-// Pick returns the closest node intersected by the given ray, or nil.
-func (g *Graph) Pick(ray rl.Ray) *SceneNode {
-	if g.Root == nil {
-		return nil
+// RebuildBVH rebuilds the spatial index from the current node bounds.
+// Call it after moving nodes.
+func (g *Graph) RebuildBVH() {
+	nodes := make([]*SceneNode, 0, len(g.NodeIndex))
+	for _, node := range g.NodeIndex {
+		nodes = append(nodes, node)
 	}
+	g.bvh = NewBVH(nodes)
+}
 
-	var closest *SceneNode
-	closestDist := float32(1e30)
+// updateBVH brings the spatial index up to date after nodes were added,
+// removed or moved: it is refit, and rebuilt only once the patched-in
+// changes amount to a quarter of the graph.
+func (g *Graph) updateBVH(added, removed []*SceneNode) {
+	if g.bvh == nil || g.bvh.pending()+len(added)+len(removed) > len(g.NodeIndex)/4 {
+		g.RebuildBVH()
+		return
+	}
+	g.bvh.Remove(removed)
+	g.bvh.Insert(added)
+	g.bvh.Refit()
+}
 
-	g.Traverse(func(node *SceneNode) bool {
-		collision := rl.GetRayCollisionBox(ray, node.Bounds)
-		if collision.Hit && collision.Distance < closestDist {
-			closestDist = collision.Distance
-			closest = node
+// Pickable reports whether a node is shown: it and all its ancestors are
+// visible, its ancestors are expanded, and time travel hasn't faded it out.
+func Pickable(node *SceneNode) bool {
+	if node.Fade >= 1 || !node.Visible {
+		return false
+	}
+	for p := node.Parent; p != nil; p = p.Parent {
+		if !p.Visible || !p.Expanded {
+			return false
 		}
-		return true
-	})
+	}
+	return true
+}
 
-	return closest
+// Pick returns the closest node intersected by the given ray, or nil.
+func (g *Graph) Pick(ray rl.Ray) *SceneNode {
+	if g.Root == nil || g.bvh == nil {
+		return nil
+	}
+	node, _ := g.bvh.Raycast(ray, Pickable)
+	return node
+}
+
+// SelectInRect returns the shown nodes whose centers project inside the
+// screen rectangle, in traversal order.
+func (g *Graph) SelectInRect(view *View, rect rl.Rectangle) []*SceneNode {
+	if g.Root == nil || g.bvh == nil {
+		return nil
+	}
+	var selected []*SceneNode
+	g.bvh.Query(
+		func(b rl.BoundingBox) bool { return view.BoxMayOverlapRect(b, rect) },
+		func(node *SceneNode) bool {
+			if !Pickable(node) {
+				return false
+			}
+			p, ok := view.Project(node.Center())
+			return ok && pointInRect(p, rect)
+		},
+		func(node *SceneNode) { selected = append(selected, node) },
+	)
+	sort.Slice(selected, func(i, j int) bool { return selected[i].Order < selected[j].Order })
+	return selected
 }
 
 // FindByPath returns the node at the given filesystem path.
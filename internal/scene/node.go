@@ -3,6 +3,7 @@ package scene
 import (
 	rl "github.com/gen2brain/raylib-go/raylib"
 	"github.com/Crank-Git/FSNRedux/internal/fs"
+	"github.com/Crank-Git/FSNRedux/internal/layout"
 )
 
 // SceneNode is a renderable entity in the 3D scene.
@@ -15,9 +16,22 @@ type SceneNode struct {
 	Bounds   rl.BoundingBox
 	Visible  bool
 	Expanded bool
+	Fade     float32 // 0 = opaque, 1 = fully faded out (time travel)
+	Alpha    float32 // expand/collapse tween opacity, 1 = solid
 	Depth    int
+	Sector   *layout.Sector // sunburst segment; Position and Size are then its bounding box
+	Group    *layout.Group  // small files standing in for their directory's rest; Entry is then nil
 	Children []*SceneNode
 	Parent   *SceneNode
+
+	// Subtree summaries for culling and level of detail (set by NewGraph)
+	SubtreeBounds  rl.BoundingBox // this node and all descendants
+	AggregateColor rl.Color       // mean color of the subtree's files
+	Order          int            // pre-order index in the graph
+	SubtreeEnd     int            // Order of the first node after this subtree
+
+	source *layout.Node // layout node the node was last built from (see Apply)
+	files  colorSum     // file colors in the subtree, behind AggregateColor
 }
 
 // ComputeBounds calculates the axis-aligned bounding box from position and size.
@@ -29,6 +43,23 @@ func (n *SceneNode) ComputeBounds() {
 	}
 }
 
+// Path identifies the node across layouts: its entry's path, or its
+// group's (see layout.GroupPath).
+func (n *SceneNode) Path() string {
+	switch {
+	case n.Entry != nil:
+		return n.Entry.Path
+	case n.Group != nil:
+		return n.Group.Path()
+	}
+	return ""
+}
+
+// Opacity combines the time-travel fade with the tween alpha (1 = solid).
+func (n *SceneNode) Opacity() float32 {
+	return (1 - n.Fade) * n.Alpha
+}
+
 // ContainsPoint checks if a point is inside this node's bounds.
 func (n *SceneNode) ContainsPoint(point rl.Vector3) bool {
 	return point.X >= n.Bounds.Min.X && point.X <= n.Bounds.Max.X &&
@@ -0,0 +1,109 @@
+package scene
+
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/layout"
+)
+
+// sectorEpsilon is the tolerance for a ray hit lying on a sector's surface.
+const sectorEpsilon = 1e-4
+
+// Center returns the point a node is labeled, focused and box-selected by:
+// the middle of its box, or for a sunburst segment the middle of its arc
+// (the box center of a wide arc can lie outside the segment).
+func (n *SceneNode) Center() rl.Vector3 {
+	if n.Sector == nil {
+		return n.Position
+	}
+	x, z := n.Sector.Mid()
+	return rl.NewVector3(x, n.Position.Y, z)
+}
+
+// RayNode tests a ray against a node's geometry: its box, or the extruded
+// sector of a sunburst segment.
+func RayNode(ray rl.Ray, node *SceneNode) rl.RayCollision {
+	c := rl.GetRayCollisionBox(ray, node.Bounds)
+	if !c.Hit || node.Sector == nil {
+		return c
+	}
+	return raySector(ray, node.Sector, node.Bounds.Min.Y, node.Bounds.Max.Y)
+}
+
+// raySector returns the first point where the ray enters the sector extruded
+// from y0 to y1. Every surface the ray can enter through (top, bottom, inner
+// and outer walls, the two ends) is intersected and the nearest hit that
+// lies on the solid wins.
+func raySector(ray rl.Ray, s *layout.Sector, y0, y1 float32) rl.RayCollision {
+	o, d := ray.Position, ray.Direction
+	inside := func(t float64) bool {
+		px := float64(o.X) + t*float64(d.X) - float64(s.CenterX)
+		py := float64(o.Y) + t*float64(d.Y)
+		pz := float64(o.Z) + t*float64(d.Z) - float64(s.CenterZ)
+		if py < float64(y0)-sectorEpsilon || py > float64(y1)+sectorEpsilon {
+			return false
+		}
+		r := math.Hypot(px, pz)
+		if r < float64(s.Inner)-sectorEpsilon || r > float64(s.Outer)+sectorEpsilon {
+			return false
+		}
+		if s.Sweep >= 2*math.Pi || r < sectorEpsilon {
+			return true
+		}
+		// Widen the sweep slightly so hits on the end faces count
+		tol := sectorEpsilon / r
+		a := math.Mod(math.Atan2(pz, px)-float64(s.Start)+tol, 2*math.Pi)
+		if a < 0 {
+			a += 2 * math.Pi
+		}
+		return a <= float64(s.Sweep)+2*tol
+	}
+
+	var ts []float64
+	if inside(0) {
+		ts = append(ts, 0)
+	}
+	if d.Y != 0 {
+		for _, y := range []float32{y0, y1} {
+			ts = append(ts, float64((y-o.Y)/d.Y))
+		}
+	}
+	ox, oz := float64(o.X-s.CenterX), float64(o.Z-s.CenterZ)
+	dx, dz := float64(d.X), float64(d.Z)
+	if a := dx*dx + dz*dz; a > 0 {
+		b := 2 * (ox*dx + oz*dz)
+		for _, radius := range []float32{s.Inner, s.Outer} {
+			if radius <= 0 {
+				continue
+			}
+			disc := b*b - 4*a*(ox*ox+oz*oz-float64(radius)*float64(radius))
+			if disc < 0 {
+				continue
+			}
+			q := math.Sqrt(disc)
+			ts = append(ts, (-b-q)/(2*a), (-b+q)/(2*a))
+		}
+	}
+	if s.Sweep < 2*math.Pi {
+		for _, angle := range []float64{float64(s.Start), float64(s.Start + s.Sweep)} {
+			// Plane containing the axis and the ray of this end
+			nx, nz := -math.Sin(angle), math.Cos(angle)
+			if den := dx*nx + dz*nz; den != 0 {
+				ts = append(ts, -(ox*nx+oz*nz)/den)
+			}
+		}
+	}
+
+	best := math.Inf(1)
+	for _, t := range ts {
+		if t >= 0 && t < best && inside(t) {
+			best = t
+		}
+	}
+	if math.IsInf(best, 1) {
+		return rl.RayCollision{}
+	}
+	p := rl.Vector3Add(o, rl.Vector3Scale(d, float32(best)))
+	return rl.RayCollision{Hit: true, Distance: rl.Vector3Distance(o, p), Point: p}
+}
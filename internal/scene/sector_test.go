@@ -0,0 +1,95 @@
+package scene
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+	"github.com/Crank-Git/FSNRedux/internal/layout"
+)
+
+// sectorNode is a quarter ring from +X to +Z, radii 2..4, 1 unit tall.
+func sectorNode() *SceneNode {
+	s := &layout.Sector{Inner: 2, Outer: 4, Start: 0, Sweep: math.Pi / 2}
+	minX, minZ, maxX, maxZ := s.Extent()
+	node := &SceneNode{
+		Position: rl.NewVector3((minX+maxX)/2, 0.5, (minZ+maxZ)/2),
+		Size:     rl.NewVector3(maxX-minX, 1, maxZ-minZ),
+		Sector:   s,
+	}
+	node.ComputeBounds()
+	return node
+}
+
+func down(x, z float32) rl.Ray {
+	return rl.Ray{Position: rl.NewVector3(x, 10, z), Direction: rl.NewVector3(0, -1, 0)}
+}
+
+func TestRayNode_Sector(t *testing.T) {
+	node := sectorNode()
+	mid := float32(3 / math.Sqrt2)
+	tests := []struct {
+		name string
+		ray  rl.Ray
+		hit  bool
+		dist float32
+	}{
+		{"top", down(mid, mid), true, 9},
+		{"bbox corner inside the inner radius", down(0.5, 0.5), false, 0},
+		{"bbox corner outside the outer radius", down(3.9, 3.9), false, 0},
+		{"outer wall", rl.Ray{Position: rl.NewVector3(10, 0.5, 0.1), Direction: rl.NewVector3(-1, 0, 0)}, true, 10 - float32(math.Sqrt(16-0.01))},
+		{"inner wall from the axis", rl.Ray{Position: rl.NewVector3(0, 0.5, 0), Direction: rl.NewVector3(float32(math.Sqrt2/2), 0, float32(math.Sqrt2/2))}, true, 2},
+		{"end face", rl.Ray{Position: rl.NewVector3(3, 0.5, -5), Direction: rl.NewVector3(0, 0, 1)}, true, 5},
+		{"over the top", rl.Ray{Position: rl.NewVector3(10, 1.5, 0.1), Direction: rl.NewVector3(-1, 0, 0)}, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := RayNode(tt.ray, node)
+			if c.Hit != tt.hit {
+				t.Fatalf("hit = %v, want %v", c.Hit, tt.hit)
+			}
+			if c.Hit && math.Abs(float64(c.Distance-tt.dist)) > 1e-3 {
+				t.Errorf("distance = %f, want %f", c.Distance, tt.dist)
+			}
+		})
+	}
+}
+
+func TestRayNode_StartingInsideHitsAtZero(t *testing.T) {
+	node := sectorNode()
+	ray := rl.Ray{Position: rl.NewVector3(3, 0.5, 0.5), Direction: rl.NewVector3(0, 1, 0)}
+	if c := RayNode(ray, node); !c.Hit || c.Distance != 0 {
+		t.Errorf("ray from inside: %+v, want a hit at distance 0", c)
+	}
+}
+
+func TestPick_SunburstHitsTheSectorUnderTheRay(t *testing.T) {
+	tree := fs.SyntheticTree(2000, 20, 4)
+	opts := layout.DefaultOptions(layout.ModeSunburst)
+	g := NewGraph(layout.Compute(tree, opts), nil)
+	for _, node := range g.NodeIndex {
+		node.Expanded = true
+	}
+
+	// Vertical rays must pick a segment whose footprint contains the point
+	rnd := rand.New(rand.NewSource(3))
+	b := g.Root.SubtreeBounds
+	hits := 0
+	for i := 0; i < 500; i++ {
+		x := b.Min.X + rnd.Float32()*(b.Max.X-b.Min.X)
+		z := b.Min.Z + rnd.Float32()*(b.Max.Z-b.Min.Z)
+		got := g.Pick(down(x, z))
+		if got == nil {
+			continue
+		}
+		hits++
+		if !got.Sector.Contains(x, z) {
+			t.Fatalf("ray at (%f, %f) picked %s, whose sector doesn't contain it", x, z, nodePath(got))
+		}
+	}
+	if hits < 100 {
+		t.Errorf("only %d of 500 rays hit a segment", hits)
+	}
+}
@@ -0,0 +1,74 @@
+package scene
+
+import (
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+)
+
+// ApplyVirtualNow shows the scene as it looked at now: nodes whose entries
+// appeared later fade out over window, and a directory fades with its oldest
+// loaded descendant. recolor, when non-nil, recomputes every file's color
+// (e.g. ages measured against now).
+func (g *Graph) ApplyVirtualNow(now time.Time, window time.Duration, field fs.TimeField, recolor func(*fs.Entry) rl.Color) {
+	if g.Root == nil {
+		return
+	}
+	if g.earliest == nil || g.earliestField != field {
+		g.earliest = make(map[*fs.Entry]time.Time)
+		g.earliestField = field
+	}
+	g.applyVirtualNow(g.Root, now, window, field, recolor)
+	if recolor != nil {
+		g.summarize(g.Root)
+	}
+	g.Version++
+}
+
+func (g *Graph) applyVirtualNow(node *SceneNode, now time.Time, window time.Duration, field fs.TimeField, recolor func(*fs.Entry) rl.Color) {
+	if node.Entry != nil {
+		if recolor != nil && !node.Entry.IsDir() {
+			node.Color = recolor(node.Entry)
+		}
+		node.Fade = fadeAt(g.earliestTime(node.Entry, field), now, window)
+	}
+	for _, child := range node.Children {
+		g.applyVirtualNow(child, now, window, field, recolor)
+	}
+}
+
+// earliestTime returns the oldest timestamp among entry and its loaded
+// descendants (the moment the entry "appeared"), memoized per graph.
+func (g *Graph) earliestTime(entry *fs.Entry, field fs.TimeField) time.Time {
+	if t, ok := g.earliest[entry]; ok {
+		return t
+	}
+	var earliest time.Time
+	if !entry.IsDir() || len(entry.Children) == 0 {
+		earliest = entry.Time(field)
+	}
+	for _, child := range entry.Children {
+		t := g.earliestTime(child, field)
+		if !t.IsZero() && (earliest.IsZero() || t.Before(earliest)) {
+			earliest = t
+		}
+	}
+	g.earliest[entry] = earliest
+	return earliest
+}
+
+// fadeAt returns how far an entry that appeared at t has faded at now.
+func fadeAt(t, now time.Time, window time.Duration) float32 {
+	if t.IsZero() || !t.After(now) {
+		return 0
+	}
+	if window <= 0 {
+		return 1
+	}
+	f := float32(t.Sub(now)) / float32(window)
+	if f > 1 {
+		f = 1
+	}
+	return f
+}
@@ -0,0 +1,118 @@
+package scene
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+)
+
+var epoch = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func day(n int) time.Time { return epoch.AddDate(0, 0, n) }
+
+func TestFadeAt_WindowEdges(t *testing.T) {
+	window := 10 * time.Hour
+	for _, tc := range []struct {
+		name   string
+		t      time.Time
+		window time.Duration
+		want   float32
+	}{
+		{"unknown time", time.Time{}, window, 0},
+		{"appeared earlier", epoch.Add(-time.Hour), window, 0},
+		{"appeared at now", epoch, window, 0},
+		{"just after now", epoch.Add(time.Hour), window, 0.1},
+		{"half a window", epoch.Add(5 * time.Hour), window, 0.5},
+		{"window end", epoch.Add(window), window, 1},
+		{"beyond window", epoch.Add(3 * window), window, 1},
+		{"no window", epoch.Add(time.Second), 0, 1},
+	} {
+		if got := fadeAt(tc.t, epoch, tc.window); math.Abs(float64(got-tc.want)) > 1e-6 {
+			t.Errorf("%s: fade %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+// timelineTree is a root holding a directory of files from days 2 and 5,
+// a file from day 3 and an empty directory from day 4.
+func timelineTree() *fs.Tree {
+	root := &fs.Entry{Name: "root", Path: "/root", Type: fs.TypeDir, Loaded: true, ModTime: day(9)}
+	src := &fs.Entry{Name: "src", Path: "/root/src", Type: fs.TypeDir, Loaded: true, Depth: 1, ModTime: day(9)}
+	src.Children = []*fs.Entry{
+		{Name: "a.go", Path: "/root/src/a.go", Type: fs.TypeFile, Size: 20, Depth: 2, ModTime: day(2), AccessTime: day(7)},
+		{Name: "b.go", Path: "/root/src/b.go", Type: fs.TypeFile, Size: 10, Depth: 2, ModTime: day(5), AccessTime: day(6)},
+	}
+	root.Children = []*fs.Entry{
+		src,
+		{Name: "notes", Path: "/root/notes", Type: fs.TypeFile, Size: 5, Depth: 1, ModTime: day(3)},
+		{Name: "empty", Path: "/root/empty", Type: fs.TypeDir, Loaded: true, Depth: 1, ModTime: day(4)},
+	}
+	src.Size = 30
+	root.Size = 35
+	return &fs.Tree{Root: root}
+}
+
+func TestEarliestTime_OldestLoadedDescendantMemoized(t *testing.T) {
+	tree := timelineTree()
+	g := &Graph{earliest: make(map[*fs.Entry]time.Time)}
+	src, empty := tree.Root.Children[0], tree.Root.Children[2]
+	for _, tc := range []struct {
+		entry *fs.Entry
+		want  time.Time
+	}{
+		{tree.Root, day(2)},
+		{src, day(2)},
+		{src.Children[1], day(5)},
+		{empty, day(4)}, // an empty directory appeared when it was made
+	} {
+		if got := g.earliestTime(tc.entry, fs.TimeModified); !got.Equal(tc.want) {
+			t.Errorf("%s appeared %v, want %v", tc.entry.Path, got, tc.want)
+		}
+	}
+
+	src.Children[0].ModTime = day(8)
+	if got := g.earliestTime(src, fs.TimeModified); !got.Equal(day(2)) {
+		t.Errorf("memoized time for %s became %v", src.Path, got)
+	}
+	if len(g.earliest) != 6 {
+		t.Errorf("%d entries memoized, want all 6", len(g.earliest))
+	}
+}
+
+func TestApplyVirtualNow_FadesLaterEntries(t *testing.T) {
+	tree := timelineTree()
+	expanded := map[string]bool{tree.Root.Path: true, "/root/src": true}
+	g := NewGraph(layoutFor(tree, expanded), expanded)
+	recolored := 0
+	recolor := func(*fs.Entry) rl.Color { recolored++; return rl.Red }
+
+	version := g.Version
+	g.ApplyVirtualNow(day(4), 24*time.Hour, fs.TimeModified, recolor)
+	for path, want := range map[string]float32{
+		"/root":          0,
+		"/root/src":      0, // fades with its oldest file
+		"/root/src/a.go": 0,
+		"/root/src/b.go": 1,
+		"/root/notes":    0,
+		"/root/empty":    0,
+	} {
+		if node := g.FindByPath(path); node == nil || node.Fade != want {
+			t.Errorf("%s fade %v, want %v", path, node.Fade, want)
+		}
+	}
+	if recolored != 3 || g.FindByPath("/root/notes").Color != rl.Red {
+		t.Errorf("recolored %d files, want 3", recolored)
+	}
+	if g.Version == version {
+		t.Error("version not bumped")
+	}
+
+	// Another time field drops the memo
+	g.ApplyVirtualNow(day(6).Add(12*time.Hour), 24*time.Hour, fs.TimeAccessed, nil)
+	if f := g.FindByPath("/root/src/a.go").Fade; f != 0.5 {
+		t.Errorf("a.go fade by access time %v, want 0.5", f)
+	}
+}
@@ -0,0 +1,228 @@
+package scene
+
+import (
+	"math"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/layout"
+)
+
+// DefaultTweenBudget is how long Tweener.Tick may spend per frame.
+const DefaultTweenBudget = 2 * time.Millisecond
+
+// tweenCheckEvery is how many tweens are stepped between budget checks.
+const tweenCheckEvery = 256
+
+// NodeState is the animatable part of a scene node.
+type NodeState struct {
+	Position rl.Vector3
+	Size     rl.Vector3
+	Color    rl.Color
+	Alpha    float32
+	Sector   layout.Sector // sunburst segment; unused for box nodes
+}
+
+// StateOf captures a node's current animatable state.
+func StateOf(node *SceneNode) NodeState {
+	s := NodeState{Position: node.Position, Size: node.Size, Color: node.Color, Alpha: node.Alpha}
+	if node.Sector != nil {
+		s.Sector = *node.Sector
+	}
+	return s
+}
+
+// Set writes the state to a node and updates its bounds. A sunburst
+// segment takes the state's sector, and its box on the ground follows it.
+func (s NodeState) Set(node *SceneNode) {
+	node.Position = s.Position
+	node.Size = s.Size
+	node.Color = s.Color
+	node.Alpha = s.Alpha
+	if node.Sector != nil {
+		*node.Sector = s.Sector
+		minX, minZ, maxX, maxZ := s.Sector.Extent()
+		node.Position.X, node.Size.X = (minX+maxX)/2, maxX-minX
+		node.Position.Z, node.Size.Z = (minZ+maxZ)/2, maxZ-minZ
+	}
+	node.ComputeBounds()
+}
+
+// lerpState interpolates every field of two states.
+func lerpState(a, b NodeState, t float32) NodeState {
+	return NodeState{
+		Position: lerpVector3(a.Position, b.Position, t),
+		Size:     lerpVector3(a.Size, b.Size, t),
+		Color:    lerpColor(a.Color, b.Color, t),
+		Alpha:    a.Alpha + (b.Alpha-a.Alpha)*t,
+		Sector:   lerpSector(a.Sector, b.Sector, t),
+	}
+}
+
+// lerpSector interpolates the radii and angles of two sectors, turning the
+// shorter way round.
+func lerpSector(a, b layout.Sector, t float32) layout.Sector {
+	turn := float32(2 * math.Pi)
+	d := b.Start - a.Start
+	d -= turn * float32(math.Floor(float64((d+turn/2)/turn)))
+	return layout.Sector{
+		CenterX: a.CenterX + (b.CenterX-a.CenterX)*t,
+		CenterZ: a.CenterZ + (b.CenterZ-a.CenterZ)*t,
+		Inner:   a.Inner + (b.Inner-a.Inner)*t,
+		Outer:   a.Outer + (b.Outer-a.Outer)*t,
+		Start:   a.Start + d*t,
+		Sweep:   a.Sweep + (b.Sweep-a.Sweep)*t,
+	}
+}
+
+func lerpColor(a, b rl.Color, t float32) rl.Color {
+	mix := func(x, y uint8) uint8 { return uint8(float32(x) + (float32(y)-float32(x))*t + 0.5) }
+	return rl.NewColor(mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), mix(a.A, b.A))
+}
+
+// Tween eases one node between two states.
+type Tween struct {
+	Node     *SceneNode
+	From, To NodeState
+	Start    float32 // tweener clock time the tween begins
+	Duration float32
+	Ease     Easing // nil = EaseInOutCubic
+}
+
+// at returns the tween's state at clock time now and whether it has finished.
+func (tw *Tween) at(now float32) (NodeState, bool) {
+	t := float32(1)
+	if tw.Duration > 0 {
+		t = (now - tw.Start) / tw.Duration
+	}
+	switch {
+	case t <= 0:
+		return tw.From, false
+	case t >= 1:
+		return tw.To, true
+	}
+	ease := tw.Ease
+	if ease == nil {
+		ease = EaseInOutCubic
+	}
+	return lerpState(tw.From, tw.To, ease(t)), false
+}
+
+// Tweener runs node tweens against a shared clock. Each Tick spends at most
+// Budget stepping tweens; the rest are stepped on later ticks, round robin.
+// A tween's state depends only on the clock, so a skipped tick costs
+// smoothness, never correctness.
+type Tweener struct {
+	Budget time.Duration // 0 = unlimited
+
+	clock  float32
+	tweens []*Tween
+	byNode map[*SceneNode]*Tween
+	cursor int
+	moved  []*SceneNode // nodes stepped by the last Tick or Finish
+	// recolored reports whether the last Tick changed a color or alpha
+	recolored bool
+}
+
+// NewTweener creates a tweener with the default budget.
+func NewTweener() *Tweener {
+	return &Tweener{Budget: DefaultTweenBudget, byNode: make(map[*SceneNode]*Tween)}
+}
+
+// Add starts a tween from the node's current state to target, replacing any
+// tween already running on the node. The node is set to its start state.
+func (tw *Tweener) Add(node *SceneNode, target NodeState, duration float32, ease Easing) {
+	tw.AddFrom(node, StateOf(node), target, duration, ease)
+}
+
+// AddFrom starts a tween from an explicit state.
+func (tw *Tweener) AddFrom(node *SceneNode, from, target NodeState, duration float32, ease Easing) {
+	from.Set(node)
+	if old, ok := tw.byNode[node]; ok {
+		*old = Tween{Node: node, From: from, To: target, Start: tw.clock, Duration: duration, Ease: ease}
+		return
+	}
+	t := &Tween{Node: node, From: from, To: target, Start: tw.clock, Duration: duration, Ease: ease}
+	tw.tweens = append(tw.tweens, t)
+	tw.byNode[node] = t
+}
+
+// Active reports whether any tween is still running.
+func (tw *Tweener) Active() bool {
+	return len(tw.tweens) > 0
+}
+
+// Len returns the number of running tweens.
+func (tw *Tweener) Len() int {
+	return len(tw.tweens)
+}
+
+// Tick advances the clock by dt seconds and steps as many tweens as the
+// budget allows. It returns whether any node changed.
+func (tw *Tweener) Tick(dt float32) bool {
+	if len(tw.tweens) == 0 {
+		tw.clock = 0
+		return false
+	}
+	tw.clock += dt
+
+	start := time.Now()
+	tw.moved = tw.moved[:0]
+	tw.recolored = false
+	n := len(tw.tweens)
+	if tw.cursor >= n {
+		tw.cursor = 0
+	}
+	finished := false
+	stepped := 0
+	for ; stepped < n; stepped++ {
+		if tw.Budget > 0 && stepped > 0 && stepped%tweenCheckEvery == 0 && time.Since(start) > tw.Budget {
+			break
+		}
+		t := tw.tweens[(tw.cursor+stepped)%n]
+		state, done := t.at(tw.clock)
+		if state.Color != t.Node.Color || state.Alpha != t.Node.Alpha {
+			tw.recolored = true
+		}
+		state.Set(t.Node)
+		tw.moved = append(tw.moved, t.Node)
+		if done {
+			t.Node = nil // mark for removal
+			finished = true
+		}
+	}
+	tw.cursor = (tw.cursor + stepped) % n
+
+	if finished {
+		kept := tw.tweens[:0]
+		for i, t := range tw.tweens {
+			if t.Node != nil {
+				kept = append(kept, t)
+			} else if i < tw.cursor {
+				tw.cursor--
+			}
+		}
+		for i := len(kept); i < len(tw.tweens); i++ {
+			tw.tweens[i] = nil
+		}
+		tw.tweens = kept
+		tw.byNode = make(map[*SceneNode]*Tween, len(kept))
+		for _, t := range kept {
+			tw.byNode[t.Node] = t
+		}
+	}
+	return stepped > 0
+}
+
+// Finish jumps every tween to its end state.
+func (tw *Tweener) Finish() {
+	tw.moved = tw.moved[:0]
+	for _, t := range tw.tweens {
+		t.To.Set(t.Node)
+		tw.moved = append(tw.moved, t.Node)
+	}
+	tw.tweens = tw.tweens[:0]
+	tw.byNode = make(map[*SceneNode]*Tween)
+	tw.cursor = 0
+	tw.clock = 0
+}
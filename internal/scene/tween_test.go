@@ -0,0 +1,86 @@
+package scene
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestEasing_Endpoints(t *testing.T) {
+	for name, ease := range map[string]Easing{
+		"Linear": Linear, "EaseInQuad": EaseInQuad, "EaseOutQuad": EaseOutQuad,
+		"EaseInOutQuad": EaseInOutQuad, "EaseInCubic": EaseInCubic, "EaseOutCubic": EaseOutCubic,
+		"EaseInOutCubic": EaseInOutCubic, "EaseOutBack": EaseOutBack,
+	} {
+		if v := ease(0); v < -1e-6 || v > 1e-6 {
+			t.Errorf("%s(0) = %v, want 0", name, v)
+		}
+		if v := ease(1); v < 1-1e-6 || v > 1+1e-6 {
+			t.Errorf("%s(1) = %v, want 1", name, v)
+		}
+	}
+}
+
+func TestTweener_InterpolatesAndFinishes(t *testing.T) {
+	node := &SceneNode{Size: rl.NewVector3(1, 1, 1), Alpha: 1}
+	tw := NewTweener()
+	target := NodeState{Position: rl.NewVector3(10, 0, 0), Size: rl.NewVector3(1, 3, 1), Color: rl.NewColor(200, 0, 0, 255), Alpha: 1}
+	tw.Add(node, target, 1, Linear)
+
+	tw.Tick(0.5)
+	if node.Position.X != 5 || node.Size.Y != 2 {
+		t.Errorf("halfway: position %v size %v", node.Position, node.Size)
+	}
+	if node.Bounds.Max.X != 5.5 {
+		t.Errorf("bounds not updated: %v", node.Bounds)
+	}
+
+	tw.Tick(0.6)
+	if tw.Active() {
+		t.Error("tween should have finished")
+	}
+	if StateOf(node) != target {
+		t.Errorf("final state %+v, want %+v", StateOf(node), target)
+	}
+}
+
+func TestTweener_AddReplacesRunningTween(t *testing.T) {
+	node := &SceneNode{Alpha: 1}
+	tw := NewTweener()
+	tw.Add(node, NodeState{Position: rl.NewVector3(10, 0, 0), Alpha: 1}, 1, Linear)
+	tw.Tick(0.5)
+	tw.Add(node, NodeState{Alpha: 1}, 1, Linear)
+	if tw.Len() != 1 {
+		t.Fatalf("%d tweens, want 1 after retargeting", tw.Len())
+	}
+	tw.Tick(0.5)
+	if node.Position.X != 2.5 {
+		t.Errorf("retargeted tween should start from the current state: x = %v, want 2.5", node.Position.X)
+	}
+}
+
+func TestTweener_BudgetEventuallyStepsEveryTween(t *testing.T) {
+	tw := NewTweener()
+	tw.Budget = 1 // a nanosecond: one batch of tweens per tick
+	nodes := make([]*SceneNode, 3*tweenCheckEvery)
+	for i := range nodes {
+		nodes[i] = &SceneNode{Alpha: 1}
+		tw.Add(nodes[i], NodeState{Position: rl.NewVector3(1, 0, 0), Alpha: 1}, 0.1, nil)
+	}
+
+	tw.Tick(0.05)
+	if tw.Len() != len(nodes) {
+		t.Fatalf("%d tweens running mid-way, want %d", tw.Len(), len(nodes))
+	}
+	for ticks := 0; tw.Active(); ticks++ {
+		if ticks > 10 {
+			t.Fatal("tweens never finished under the budget")
+		}
+		tw.Tick(0.1)
+	}
+	for i, n := range nodes {
+		if n.Position.X != 1 {
+			t.Fatalf("node %d ended at %v", i, n.Position)
+		}
+	}
+}
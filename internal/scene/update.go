@@ -0,0 +1,349 @@
+package scene
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/layout"
+)
+
+// DefaultMoveDuration is how long nodes take to glide to a new layout, in seconds.
+const DefaultMoveDuration = 0.4
+
+// maxLeaving caps how many removed nodes animate out; the rest vanish at once.
+const maxLeaving = 5000
+
+// Apply updates the graph in place to match a new layout of the same tree.
+// Nodes are matched by path and keep their SceneNode and ID; nodes new to
+// the layout are created and nodes no longer in it are dropped. Over
+// duration seconds (0 = snap), moved nodes glide to their new place, new
+// nodes grow out of their parent's pedestal, and removed nodes shrink back
+// into it (they stay in Leaving until their tween ends).
+//
+// A layout subtree that is the very *layout.Node the scene node was last
+// built from is unchanged (layout.Incremental hands those back), so Apply
+// skips it: only its pre-order numbers shift. The indexes, subtree summaries
+// and spatial index are patched for the nodes that did change.
+func (g *Graph) Apply(layoutRoot *layout.Node, expandedPaths map[string]bool, duration float32) {
+	if layoutRoot == nil {
+		*g = *NewGraph(nil, expandedPaths)
+		return
+	}
+	if g.tweens == nil {
+		g.tweens = NewTweener()
+	}
+	if duration <= 0 {
+		g.tweens.Finish()
+		g.refitMoved(g.tweens.moved)
+		g.Leaving = nil
+	}
+
+	u := &update{
+		g:        g,
+		expanded: expandedPaths,
+		duration: duration,
+		matched:  make(map[*SceneNode]bool),
+		dirty:    make(map[*SceneNode]bool),
+		peds:     make(map[*SceneNode]pedestal),
+	}
+	if g.Root != nil {
+		u.dropped = append(u.dropped, droppedNode{node: g.Root})
+	}
+	g.Root = u.applyNode(layoutRoot, nil, nil, true)
+	g.NodeCount = u.order
+	removed := u.removeDropped()
+
+	if !g.tweens.Active() {
+		// Nothing is left to glide, so earlier moves are summarized now
+		for node := range g.tweened {
+			u.dirty[node] = true
+		}
+		g.tweened = nil
+	}
+	g.resummarize(g.Root, u.dirty)
+	g.updateBVH(u.added, removed)
+	g.earliest = nil // lazily loaded children change subtree timestamps
+	g.Version++
+}
+
+// update is the state of one Apply pass.
+type update struct {
+	g        *Graph
+	expanded map[string]bool
+	duration float32
+	order    int // Order of the next node
+
+	matched map[*SceneNode]bool     // existing nodes found in the new layout
+	dirty   map[*SceneNode]bool     // nodes applied again, whose summaries are stale
+	peds    map[*SceneNode]pedestal // states of the nodes applied again
+	dropped []droppedNode           // former children of the nodes applied again
+	added   []*SceneNode            // nodes new to the graph
+}
+
+// droppedNode is a former child of a node applied again, and whether it was
+// shown before the update.
+type droppedNode struct {
+	node  *SceneNode
+	shown bool
+}
+
+// pedestal is a parent's state before and after an update.
+type pedestal struct {
+	from, to NodeState
+}
+
+// applyNode reuses or creates the scene node for ln. New nodes start on
+// the pedestal of their parent as it was before the update. showed is
+// whether the parent showed its children before the update.
+func (u *update) applyNode(ln *layout.Node, parent *SceneNode, ped *pedestal, showed bool) *SceneNode {
+	g := u.g
+	var node *SceneNode
+	path := ln.Path()
+	if path != "" {
+		node = g.NodeByPath[path]
+	}
+
+	if node != nil && node.source == ln && node.Parent == parent {
+		// Unchanged subtree: only its place in the pre-order moves
+		u.matched[node] = true
+		if delta := u.order - node.Order; delta != 0 {
+			shiftOrder(node, delta)
+		}
+		u.order = node.SubtreeEnd
+		return node
+	}
+
+	to := targetState(ln)
+	var from NodeState
+	if node != nil {
+		u.matched[node] = true
+		from = StateOf(node)
+		if node.Sector == nil {
+			from.Sector = to.Sector // a box turning into a segment
+		}
+		showsChildren := showed && node.Visible && node.Expanded
+		for _, child := range node.Children {
+			u.dropped = append(u.dropped, droppedNode{node: child, shown: showsChildren && child.Visible && child.Fade < 1})
+		}
+	} else {
+		node = &SceneNode{ID: nextID.Add(1)}
+		u.added = append(u.added, node)
+		from = to
+		if ped != nil {
+			// Grow out of the parent's pedestal where it was before the update
+			from = onPedestal(to, ped.to, ped.from)
+		}
+	}
+
+	node.source = ln
+	node.Entry = ln.Entry
+	node.Visible = true
+	node.Fade = 0
+	node.Depth = ln.Depth
+	node.Sector = ownSector(ln.Sector)
+	node.Group = ln.Group
+	node.Parent = parent
+	node.Children = node.Children[:0]
+	node.Order = u.order
+	node.Expanded = path != "" && u.expanded[path]
+
+	if u.duration > 0 && from != to {
+		ease := EaseInOutCubic
+		if from.Alpha == 0 {
+			ease = EaseOutCubic
+		}
+		g.tweens.AddFrom(node, from, to, u.duration, ease)
+	} else {
+		to.Set(node)
+	}
+
+	g.NodeIndex[node.ID] = node
+	if path != "" {
+		g.NodeByPath[path] = node
+	}
+	u.dirty[node] = true
+	u.peds[node] = pedestal{from: from, to: to}
+	u.order++
+
+	for _, childLayout := range ln.Children {
+		child := u.applyNode(childLayout, node, &pedestal{from: from, to: to}, true)
+		node.Children = append(node.Children, child)
+	}
+	node.SubtreeEnd = u.order
+
+	return node
+}
+
+// shiftOrder moves the pre-order numbers of node's subtree by delta.
+func shiftOrder(node *SceneNode, delta int) {
+	node.Order += delta
+	node.SubtreeEnd += delta
+	for _, child := range node.Children {
+		shiftOrder(child, delta)
+	}
+}
+
+// removeDropped drops the former children missing from the new layout, and
+// their subtrees, from the indexes. Shown ones shrink into the pedestal of
+// their nearest surviving ancestor while the update glides (see Leaving).
+// It returns the removed nodes.
+func (u *update) removeDropped() []*SceneNode {
+	g := u.g
+	var leaving []*SceneNode
+	if u.duration > 0 {
+		for _, node := range g.Leaving {
+			if g.tweens.byNode[node] != nil {
+				leaving = append(leaving, node)
+			}
+		}
+	}
+
+	var removed []*SceneNode
+	var remove func(node *SceneNode, shown bool)
+	remove = func(node *SceneNode, shown bool) {
+		removed = append(removed, node)
+		delete(g.NodeIndex, node.ID)
+		if path := node.Path(); path != "" && g.NodeByPath[path] == node {
+			delete(g.NodeByPath, path)
+		}
+		if shown && u.duration > 0 && len(leaving) < maxLeaving {
+			anc := node.Parent
+			for anc != nil && !u.dirty[anc] {
+				anc = anc.Parent
+			}
+			if anc != nil {
+				ped := u.peds[anc]
+				g.tweens.Add(node, onPedestal(StateOf(node), ped.from, ped.to), u.duration, EaseInCubic)
+				leaving = append(leaving, node)
+			}
+		}
+		for _, child := range node.Children {
+			remove(child, shown && node.Visible && node.Expanded && child.Visible && child.Fade < 1)
+		}
+	}
+	for _, d := range u.dropped {
+		if !u.matched[d.node] {
+			remove(d.node, d.shown)
+		}
+	}
+	if u.duration > 0 {
+		g.Leaving = leaving
+	}
+	return removed
+}
+
+// targetState is the resting state of a laid-out node.
+func targetState(ln *layout.Node) NodeState {
+	s := NodeState{Position: ln.Position, Size: ln.Size, Color: ln.Color, Alpha: 1}
+	if ln.Sector != nil {
+		s.Sector = *ln.Sector
+	}
+	return s
+}
+
+// onPedestal flattens state onto the top of a directory pedestal at base,
+// keeping the node's offset from the directory at dir.
+func onPedestal(s NodeState, dir, base NodeState) NodeState {
+	offset := rl.Vector3Subtract(s.Position, dir.Position)
+	s.Position = rl.Vector3Add(base.Position, offset)
+	s.Position.Y = base.Position.Y + base.Size.Y/2
+	s.Size.Y = 0
+	s.Alpha = 0
+	return s
+}
+
+// ownSector returns a copy of a layout sector for a scene node to tween,
+// or nil.
+func ownSector(s *layout.Sector) *layout.Sector {
+	if s == nil {
+		return nil
+	}
+	own := *s
+	return &own
+}
+
+// RestingCenter returns where node's Center will be once it has glided
+// to its place in the current layout.
+func (g *Graph) RestingCenter(node *SceneNode) rl.Vector3 {
+	if g.tweens == nil {
+		return node.Center()
+	}
+	tw, ok := g.tweens.byNode[node]
+	if !ok {
+		return node.Center()
+	}
+	c := tw.To.Position
+	if node.Sector != nil {
+		c.X, c.Z = tw.To.Sector.Mid()
+	}
+	return c
+}
+
+// Animating reports whether nodes are still tweening to a new layout.
+func (g *Graph) Animating() bool {
+	return g.tweens != nil && g.tweens.Active()
+}
+
+// Tick advances node tweens by dt seconds within the tween budget. It
+// returns true while tweens are running. Each step refits the spatial index
+// and the subtree bounds above the moved nodes, so picking and culling
+// follow them, and bumps Version only when a color or fade changed. Once
+// everything has arrived, the aggregate colors above the moved nodes catch
+// up and leaving nodes are dropped.
+func (g *Graph) Tick(dt float32) bool {
+	if !g.Animating() {
+		return false
+	}
+	if g.tweens.Tick(dt) {
+		g.refitMoved(g.tweens.moved)
+		if g.tweens.recolored {
+			g.Version++
+		} else {
+			g.Moved++
+		}
+	}
+	if g.tweens.Active() {
+		return true
+	}
+	g.Leaving = nil
+	if g.tweened != nil {
+		g.resummarize(g.Root, g.tweened)
+		g.tweened = nil
+	}
+	g.updateBVH(nil, nil)
+	g.Version++
+	return false
+}
+
+// refitMoved updates the spatial index and the SubtreeBounds of the moved
+// nodes and their ancestors, leaving the rest of the tree alone.
+func (g *Graph) refitMoved(moved []*SceneNode) {
+	if g.Root == nil || len(moved) == 0 {
+		return
+	}
+	dirty := make(map[*SceneNode]bool, 2*len(moved))
+	if g.tweened == nil {
+		g.tweened = make(map[*SceneNode]bool)
+	}
+	for _, node := range moved {
+		for n := node; n != nil && !dirty[n]; n = n.Parent {
+			dirty[n] = true
+			g.tweened[n] = true
+		}
+	}
+	refitSubtree(g.Root, dirty)
+	if g.bvh != nil {
+		g.bvh.Refit()
+	}
+}
+
+// refitSubtree recomputes SubtreeBounds of node and of its dirty descendants.
+func refitSubtree(node *SceneNode, dirty map[*SceneNode]bool) {
+	if !dirty[node] {
+		return
+	}
+	node.SubtreeBounds = node.Bounds
+	for _, child := range node.Children {
+		refitSubtree(child, dirty)
+		node.SubtreeBounds.Min = rl.Vector3Min(node.SubtreeBounds.Min, child.SubtreeBounds.Min)
+		node.SubtreeBounds.Max = rl.Vector3Max(node.SubtreeBounds.Max, child.SubtreeBounds.Max)
+	}
+}
@@ -0,0 +1,373 @@
+package scene
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+	"github.com/Crank-Git/FSNRedux/internal/layout"
+)
+
+func layoutFor(tree *fs.Tree, expanded map[string]bool) *layout.Node {
+	opts := layout.DefaultOptions(layout.ModeTreeV)
+	opts.ExpandedPaths = expanded
+	return layout.Compute(tree, opts)
+}
+
+func TestApply_KeepsIDsAndMovesNodes(t *testing.T) {
+	tree := fs.SyntheticTree(500, 20, 3)
+	expanded := map[string]bool{tree.Root.Path: true}
+	g := NewGraph(layoutFor(tree, expanded), expanded)
+
+	ids := make(map[string]uint32)
+	for path, node := range g.NodeByPath {
+		ids[path] = node.ID
+	}
+
+	// Expanding the first subdirectory pushes its siblings aside
+	var first *fs.Entry
+	for _, c := range tree.Root.Children {
+		if c.IsDir() {
+			first = c
+			break
+		}
+	}
+	expanded[first.Path] = true
+	target := layoutFor(tree, expanded)
+	g.Apply(target, expanded, 0.4)
+
+	for path, id := range ids {
+		node := g.FindByPath(path)
+		if node == nil {
+			t.Fatalf("%s dropped by Apply", path)
+		}
+		if node.ID != id {
+			t.Errorf("%s: ID changed from %d to %d", path, id, node.ID)
+		}
+	}
+	if len(g.NodeByPath) <= len(ids) {
+		t.Fatalf("expanding %s added no nodes", first.Path)
+	}
+	if !g.Animating() {
+		t.Fatal("expected nodes to be moving after an expand")
+	}
+	if len(g.NodeIndex) != g.NodeCount || g.Root.SubtreeEnd != g.NodeCount {
+		t.Errorf("index has %d nodes, count %d, root span ends at %d", len(g.NodeIndex), g.NodeCount, g.Root.SubtreeEnd)
+	}
+
+	// New nodes start flat and transparent on their parent's pedestal
+	parent := g.FindByPath(first.Path)
+	newChild := g.FindByPath(first.Children[0].Path)
+	if newChild.Size.Y != 0 || newChild.Alpha != 0 {
+		t.Errorf("new node starts with height %v, alpha %v; want both 0", newChild.Size.Y, newChild.Alpha)
+	}
+	if top := parent.Position.Y + parent.Size.Y/2; newChild.Position.Y != top {
+		t.Errorf("new node starts at y=%v, want the pedestal top %v", newChild.Position.Y, top)
+	}
+
+	// Everything arrives in place
+	for g.Tick(0.1) {
+	}
+	want := make(map[string]*layout.Node)
+	var collect func(n *layout.Node)
+	collect = func(n *layout.Node) {
+		want[n.Entry.Path] = n
+		for _, c := range n.Children {
+			collect(c)
+		}
+	}
+	collect(target)
+	for path, node := range g.NodeByPath {
+		if node.Position != want[path].Position || node.Size != want[path].Size || node.Alpha != 1 {
+			t.Fatalf("%s ended at %v size %v alpha %v, want %v size %v", path,
+				node.Position, node.Size, node.Alpha, want[path].Position, want[path].Size)
+		}
+	}
+
+	// Collapsing drops the subtree again without touching other IDs; its
+	// nodes shrink into the pedestal before they go
+	delete(expanded, first.Path)
+	g.Apply(layoutFor(tree, expanded), expanded, 0.4)
+	if len(g.Leaving) != len(first.Children) {
+		t.Errorf("%d nodes leaving, want the %d collapsed children", len(g.Leaving), len(first.Children))
+	}
+	for g.Tick(0.1) {
+	}
+	if len(g.Leaving) != 0 {
+		t.Errorf("%d nodes still leaving after the tween", len(g.Leaving))
+	}
+	if len(g.NodeByPath) != len(ids) {
+		t.Errorf("after collapse: %d nodes, want %d", len(g.NodeByPath), len(ids))
+	}
+	for path, id := range ids {
+		if g.FindByPath(path).ID != id {
+			t.Errorf("%s: ID changed after collapse", path)
+		}
+	}
+}
+
+func TestApply_ZeroDurationSnaps(t *testing.T) {
+	tree := fs.SyntheticTree(200, 20, 3)
+	expanded := map[string]bool{tree.Root.Path: true}
+	g := NewGraph(layoutFor(tree, expanded), expanded)
+	for _, c := range tree.Root.Children {
+		expanded[c.Path] = c.IsDir()
+	}
+	g.Apply(layoutFor(tree, expanded), expanded, 0)
+	if g.Animating() || len(g.Leaving) != 0 {
+		t.Error("a zero duration should snap")
+	}
+	for _, node := range g.NodeIndex {
+		if node.Alpha != 1 {
+			t.Fatalf("%s has alpha %v after a snap", node.Entry.Path, node.Alpha)
+		}
+	}
+}
+
+func TestApply_GroupOfSmallFiles(t *testing.T) {
+	root := &fs.Entry{Name: "crowd", Type: fs.TypeDir, Path: "/crowd", Loaded: true}
+	for i := 0; i < 1000; i++ {
+		root.Children = append(root.Children, &fs.Entry{
+			Name: fmt.Sprintf("f%04d", i), Path: fmt.Sprintf("/crowd/f%04d", i),
+			Type: fs.TypeFile, Size: 100, Depth: 1,
+		})
+	}
+	tree := &fs.Tree{Root: root}
+	expanded := map[string]bool{root.Path: true}
+	g := NewGraph(layoutFor(tree, expanded), expanded)
+
+	groupPath := layout.GroupPath(root.Path)
+	node := g.FindByPath(groupPath)
+	if node == nil || node.Group == nil || node.Entry != nil {
+		t.Fatalf("group node not indexed by %q: %+v", groupPath, node)
+	}
+	if node.Path() != groupPath || !Pickable(node) {
+		t.Errorf("group node path %q, pickable %v", node.Path(), Pickable(node))
+	}
+	if g.NodeCount != 2 {
+		t.Errorf("grouped graph has %d nodes, want the directory and its group", g.NodeCount)
+	}
+
+	// Expanding the group swaps it for its members; it shrinks away
+	expanded[groupPath] = true
+	g.Apply(layoutFor(tree, expanded), expanded, 0.4)
+	if g.FindByPath(groupPath) != nil {
+		t.Error("expanded group still in the graph")
+	}
+	if g.NodeCount != 1001 {
+		t.Errorf("expanded graph has %d nodes, want the directory and 1000 files", g.NodeCount)
+	}
+	if len(g.Leaving) != 1 || g.Leaving[0] != node {
+		t.Errorf("leaving = %v, want the group node", g.Leaving)
+	}
+}
+
+func TestApply_FisheyeGlidesAndRestingCenterLeadsTheWay(t *testing.T) {
+	tree := fs.SyntheticTree(500, 20, 3)
+	opts := layout.DefaultOptions(layout.ModeTreeV)
+	g := NewGraph(layout.Compute(tree, opts), nil)
+
+	var focus string
+	for _, c := range tree.Root.Children {
+		if c.IsDir() {
+			focus = c.Path
+			break
+		}
+	}
+	opts.Focus, opts.Magnify = focus, layout.DefaultMagnify
+	target := layout.Compute(tree, opts)
+	g.Apply(target, nil, DefaultMoveDuration)
+	if !g.Animating() {
+		t.Fatal("refocusing moved no nodes")
+	}
+
+	node := g.FindByPath(focus)
+	want := target.Children[0].Position
+	for _, c := range target.Children {
+		if c.Path() == focus {
+			want = c.Position
+		}
+	}
+	if node.Position == want {
+		t.Fatal("focus jumped straight to its new place")
+	}
+	if got := g.RestingCenter(node); got != want {
+		t.Errorf("resting center %v, want the new layout's %v", got, want)
+	}
+}
+
+func TestTick_PickingFollowsGlidingNodes(t *testing.T) {
+	tree := fs.SyntheticTree(800, 20, 3)
+	opts := layout.DefaultOptions(layout.ModeTreeV)
+	g := NewGraph(layout.Compute(tree, opts), nil)
+	opts.Focus, opts.Magnify = focusOf(tree), layout.DefaultMagnify
+	g.Apply(layout.Compute(tree, opts), nil, 1)
+	version, moved := g.Version, g.Moved
+
+	g.tweens.Budget = 0
+	g.Tick(0.5)
+	if !g.Animating() {
+		t.Fatal("tweens finished early; the test shows nothing")
+	}
+	if g.Version != version || g.Moved == moved {
+		t.Errorf("a pure move bumped Version %d->%d and Moved %d->%d", version, g.Version, moved, g.Moved)
+	}
+
+	g.Traverse(func(node *SceneNode) bool {
+		b := g.Root.SubtreeBounds
+		if node.Bounds.Min.X < b.Min.X || node.Bounds.Max.X > b.Max.X || node.Bounds.Min.Z < b.Min.Z || node.Bounds.Max.Z > b.Max.Z {
+			t.Fatalf("%s lies outside the root's subtree bounds mid-tween", nodePath(node))
+		}
+		return true
+	})
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		ray := randomRay(g, rnd)
+		if got, want := g.Pick(ray), pickLinear(g, ray); got != want {
+			t.Fatalf("ray %d mid-tween: Pick = %v, brute force = %v", i, nodePath(got), nodePath(want))
+		}
+	}
+}
+
+// focusOf returns the path of the first subdirectory of the root.
+func focusOf(tree *fs.Tree) string {
+	for _, c := range tree.Root.Children {
+		if c.IsDir() {
+			return c.Path
+		}
+	}
+	return ""
+}
+
+func TestApply_SectorsGlideBetweenLayouts(t *testing.T) {
+	tree := fs.SyntheticTree(500, 20, 3)
+	opts := layout.DefaultOptions(layout.ModeSunburst)
+	g := NewGraph(layout.Compute(tree, opts), nil)
+
+	var focus string
+	for _, c := range tree.Root.Children {
+		if c.IsDir() {
+			focus = c.Path
+			break
+		}
+	}
+	node := g.FindByPath(focus)
+	from := *node.Sector
+	opts.Focus, opts.Magnify = focus, layout.DefaultMagnify
+	g.Apply(layout.Compute(tree, opts), nil, 1)
+	to := g.tweens.byNode[node].To.Sector
+	if from == to {
+		t.Fatal("refocusing did not change the focus segment")
+	}
+
+	g.tweens.Budget = 0
+	g.Tick(0.5)
+	between := func(x, a, b float32) bool { return min(a, b)-1e-4 <= x && x <= max(a, b)+1e-4 }
+	mid := *node.Sector
+	if mid == from || mid == to {
+		t.Fatalf("segment snapped: %+v", mid)
+	}
+	for _, f := range []struct {
+		name    string
+		x, a, b float32
+	}{
+		{"inner", mid.Inner, from.Inner, to.Inner},
+		{"outer", mid.Outer, from.Outer, to.Outer},
+		{"start", mid.Start, from.Start, to.Start},
+		{"sweep", mid.Sweep, from.Sweep, to.Sweep},
+	} {
+		if !between(f.x, f.a, f.b) {
+			t.Errorf("mid-tween %s %v, want between %v and %v", f.name, f.x, f.a, f.b)
+		}
+	}
+	if x, z := mid.Mid(); node.Center().X != x || node.Center().Z != z {
+		t.Errorf("center %v does not follow the segment's middle (%v, %v)", node.Center(), x, z)
+	}
+	if x, z := to.Mid(); g.RestingCenter(node).X != x || g.RestingCenter(node).Z != z {
+		t.Errorf("resting center %v, want the target segment's middle (%v, %v)", g.RestingCenter(node), x, z)
+	}
+}
+
+func TestApply_SkipsUnchangedSubtrees(t *testing.T) {
+	tree := fs.SyntheticTree(4000, 10, 20)
+	expanded := make(map[string]bool)
+	var expandAll func(e *fs.Entry)
+	expandAll = func(e *fs.Entry) {
+		if e.IsDir() {
+			expanded[e.Path] = true
+			for _, c := range e.Children {
+				expandAll(c)
+			}
+		}
+	}
+	expandAll(tree.Root)
+	opts := layout.DefaultOptions(layout.ModeTreeV)
+	opts.ExpandedPaths = expanded
+	inc := layout.NewIncremental()
+	g := NewGraph(inc.Compute(tree, opts), expanded)
+
+	// Mark every node; nodes Apply visits lose the mark
+	sources := make(map[*SceneNode]*layout.Node)
+	for _, node := range g.NodeIndex {
+		node.Fade = 0.5
+		sources[node] = node.source
+	}
+	var toggled *fs.Entry
+	for _, c := range tree.Root.Children {
+		for _, gc := range c.Children {
+			if gc.IsDir() {
+				toggled = gc
+			}
+		}
+	}
+	delete(expanded, toggled.Path)
+	target := inc.Compute(tree, opts)
+	g.Apply(target, expanded, 0)
+
+	visited, reused := 0, 0
+	for _, node := range g.NodeIndex {
+		if node.Fade != 0.5 {
+			visited++
+		}
+		if node.source == sources[node] {
+			reused++
+			if node.Fade != 0.5 {
+				t.Errorf("%s was applied again though its layout node is the same", node.Path())
+			}
+		}
+	}
+	if reused == 0 {
+		t.Fatal("the layout reused no subtree; the test shows nothing")
+	}
+	if visited == 0 || visited > len(g.NodeIndex)/2 {
+		t.Errorf("Apply visited %d of %d nodes", visited, len(g.NodeIndex))
+	}
+
+	// The patched graph matches one built from scratch
+	fresh := NewGraph(target, expanded)
+	if len(g.NodeIndex) != len(fresh.NodeIndex) || g.NodeCount != fresh.NodeCount {
+		t.Fatalf("graph has %d nodes (count %d), fresh build %d", len(g.NodeIndex), g.NodeCount, len(fresh.NodeIndex))
+	}
+	for path, want := range fresh.NodeByPath {
+		got := g.FindByPath(path)
+		if got == nil {
+			t.Fatalf("%s missing after Apply", path)
+		}
+		if got.Order != want.Order || got.SubtreeEnd != want.SubtreeEnd || got.SubtreeBounds != want.SubtreeBounds || got.AggregateColor != want.AggregateColor {
+			t.Fatalf("%s: order %d..%d bounds %v color %v, fresh build %d..%d %v %v", path,
+				got.Order, got.SubtreeEnd, got.SubtreeBounds, got.AggregateColor,
+				want.Order, want.SubtreeEnd, want.SubtreeBounds, want.AggregateColor)
+		}
+	}
+	for _, node := range g.NodeIndex {
+		node.Fade = 0
+	}
+	rnd := rand.New(rand.NewSource(3))
+	for i := 0; i < 500; i++ {
+		ray := randomRay(g, rnd)
+		if got, want := g.Pick(ray), pickLinear(g, ray); got != want {
+			t.Fatalf("ray %d after Apply: Pick = %v, brute force = %v", i, nodePath(got), nodePath(want))
+		}
+	}
+}
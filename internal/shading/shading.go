@@ -0,0 +1,52 @@
+// Package shading names the lighting pipelines the renderer offers, so that
+// configuration and the settings menu can pick one without depending on the
+// renderer.
+package shading
+
+import "strings"
+
+// Mode selects how cuboids are lit.
+type Mode uint8
+
+const (
+	Flat    Mode = iota // unlit flat colors (cheapest)
+	Lit                 // directional light plus ambient, outlines, spotlight
+	Shadows             // Lit plus shadows on the ground plane
+)
+
+// modeCount is the number of shading modes (used for cycling).
+const modeCount = 3
+
+// String returns the shading name.
+func (m Mode) String() string {
+	switch m {
+	case Flat:
+		return "Flat"
+	case Lit:
+		return "Lit"
+	case Shadows:
+		return "Lit + Shadows"
+	default:
+		return "Unknown"
+	}
+}
+
+// Parse converts a name ("flat", "lit", "shadows") to a Mode.
+// The empty string selects Shadows, the default.
+func Parse(name string) (Mode, bool) {
+	switch strings.ToLower(name) {
+	case "flat":
+		return Flat, true
+	case "lit":
+		return Lit, true
+	case "shadows", "":
+		return Shadows, true
+	default:
+		return Shadows, false
+	}
+}
+
+// Next returns the shading that follows m when cycling.
+func (m Mode) Next() Mode {
+	return (m + 1) % modeCount
+}
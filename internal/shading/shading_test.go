@@ -0,0 +1,21 @@
+package shading
+
+import "testing"
+
+func TestParse_RoundTrip(t *testing.T) {
+	for m := Flat; m < modeCount; m++ {
+		name := map[Mode]string{Flat: "flat", Lit: "lit", Shadows: "shadows"}[m]
+		if got, ok := Parse(name); !ok || got != m {
+			t.Errorf("Parse(%q) = %s, %v", name, got, ok)
+		}
+	}
+	if Shadows.Next() != Flat {
+		t.Error("Next should cycle back to flat")
+	}
+	if got, ok := Parse(""); !ok || got != Shadows {
+		t.Errorf("empty name should select the default, got %s", got)
+	}
+	if _, ok := Parse("raytraced"); ok {
+		t.Error("expected unknown shading to be rejected")
+	}
+}
@@ -0,0 +1,279 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/color"
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+)
+
+// BreakdownGroup selects how the breakdown panel groups files.
+type BreakdownGroup uint8
+
+const (
+	GroupByExtension BreakdownGroup = iota
+	GroupByCategory
+)
+
+// BreakdownState holds the size-breakdown panel state.
+type BreakdownState struct {
+	Open    bool
+	Loading bool
+	Group   BreakdownGroup
+	SortBy  fs.BreakdownSort
+	Desc    bool
+	Active  string // highlighted row key ("" = none)
+	scroll  int    // first visible table row
+}
+
+// NewBreakdownState creates a closed panel sorted by size, largest first.
+func NewBreakdownState() *BreakdownState {
+	return &BreakdownState{Desc: true}
+}
+
+// breakdownPalette colors the bar chart segments and table swatches.
+var breakdownPalette = []rl.Color{
+	rl.NewColor(66, 165, 245, 255),
+	rl.NewColor(102, 187, 106, 255),
+	rl.NewColor(255, 167, 38, 255),
+	rl.NewColor(171, 71, 188, 255),
+	rl.NewColor(239, 83, 80, 255),
+	rl.NewColor(38, 198, 218, 255),
+	rl.NewColor(212, 225, 87, 255),
+	rl.NewColor(141, 110, 99, 255),
+}
+
+// breakdownBarSegments is how many rows get their own bar segment; the rest are "other".
+const breakdownBarSegments = 8
+
+const breakdownPanelW = int32(360)
+
+// BreakdownRect returns the screen area covered by the panel (right edge of the viewport).
+func BreakdownRect(screenW, screenH int32) rl.Rectangle {
+	y := BreadcrumbHeight + 8
+	return rl.NewRectangle(float32(screenW-breakdownPanelW-8), float32(y),
+		float32(breakdownPanelW), float32(screenH-y-8))
+}
+
+// Captures reports whether the mouse is over the open panel.
+func (s *BreakdownState) Captures(screenW, screenH int32) bool {
+	if s == nil || !s.Open {
+		return false
+	}
+	return rl.CheckCollisionPointRec(rl.GetMousePosition(), BreakdownRect(screenW, screenH))
+}
+
+// Rows returns the rows of the active grouping, in the panel's sort order.
+func (s *BreakdownState) Rows(b *fs.Breakdown) []fs.BreakdownRow {
+	if b == nil {
+		return nil
+	}
+	src := b.ByExtension
+	if s.Group == GroupByCategory {
+		src = b.ByCategory
+	}
+	rows := append([]fs.BreakdownRow(nil), src...)
+	fs.SortBreakdown(rows, s.SortBy, s.Desc)
+	return rows
+}
+
+// RowLabel returns the display name of a breakdown key.
+func RowLabel(key string) string {
+	if key == "" {
+		return "(no extension)"
+	}
+	return key
+}
+
+// DrawBreakdownPanel renders per-extension/category totals for a directory
+// with a stacked bar chart and a sortable table. Clicking a column header
+// sorts by it; clicking a row toggles its highlight. Returns true if the
+// highlighted row changed.
+func DrawBreakdownPanel(state *BreakdownState, b *fs.Breakdown, screenW, screenH int32) bool {
+	if state == nil || !state.Open {
+		return false
+	}
+
+	rect := BreakdownRect(screenW, screenH)
+	panelX, panelY := int32(rect.X), int32(rect.Y)
+	panelW, panelH := int32(rect.Width), int32(rect.Height)
+	rl.DrawRectangle(panelX, panelY, panelW, panelH, rl.NewColor(
+		color.Active.SidebarBg.R,
+		color.Active.SidebarBg.G,
+		color.Active.SidebarBg.B,
+		235,
+	))
+	rl.DrawRectangleLines(panelX, panelY, panelW, panelH, color.BorderColor)
+
+	mousePos := rl.GetMousePosition()
+	clicked := rl.IsMouseButtonPressed(rl.MouseButtonLeft)
+	hit := func(x, y, w, h int32) bool {
+		return rl.CheckCollisionPointRec(mousePos, rl.NewRectangle(float32(x), float32(y), float32(w), float32(h)))
+	}
+
+	x := panelX + 12
+	y := panelY + 8
+
+	// Title
+	title := "Breakdown"
+	if b != nil && b.Root != "" {
+		title = "Breakdown: " + filepath.Base(b.Root)
+	}
+	DrawTextUI(title, x, y, FontSize, color.TextPrimary)
+	y += 20
+
+	if state.Loading || b == nil {
+		DrawTextUI("Scanning...", x, y+4, SmallFontSize, color.TextDim)
+		return false
+	}
+	DrawTextUI(fmt.Sprintf("%d files, %s", b.TotalFiles, FormatSize(b.TotalBytes)),
+		x, y, SmallFontSize, color.TextDim)
+	y += 18
+
+	// Grouping tabs
+	changed := false
+	tabW := (panelW - 24) / 2
+	for i, label := range []string{"Extension", "Category"} {
+		tx := x + int32(i)*tabW
+		selected := state.Group == BreakdownGroup(i)
+		bg := color.HoverBg
+		if selected {
+			bg = color.SelectionBg
+		}
+		rl.DrawRectangle(tx, y, tabW-4, 18, bg)
+		lw := MeasureTextUI(label, SmallFontSize)
+		DrawTextUI(label, tx+(tabW-4-lw)/2, y+3, SmallFontSize, color.TextPrimary)
+		if clicked && hit(tx, y, tabW-4, 18) && !selected {
+			state.Group = BreakdownGroup(i)
+			state.Active = ""
+			state.scroll = 0
+			changed = true
+		}
+	}
+	y += 26
+
+	// Stacked bar chart (largest groups by size, the rest lumped together)
+	bySize := state.Rows(b)
+	fs.SortBreakdown(bySize, fs.SortByBytes, true)
+	colorOf := make(map[string]rl.Color, len(bySize))
+	for i, row := range bySize {
+		if i < breakdownBarSegments {
+			colorOf[row.Key] = breakdownPalette[i%len(breakdownPalette)]
+		} else {
+			colorOf[row.Key] = color.TextDim
+		}
+	}
+	barW := panelW - 24
+	barH := int32(14)
+	rl.DrawRectangle(x, y, barW, barH, color.HoverBg)
+	if b.TotalBytes > 0 {
+		bx := float32(x)
+		for _, row := range bySize {
+			w := float32(barW) * float32(row.Bytes) / float32(b.TotalBytes)
+			c := colorOf[row.Key]
+			if state.Active != "" && row.Key != state.Active {
+				c = color.LerpColor(c, color.Background, 0.6)
+			}
+			rl.DrawRectangle(int32(bx), y, int32(w+0.5), barH, c)
+			bx += w
+		}
+	}
+	y += barH + 10
+
+	// Table header (click to sort)
+	cols := []struct {
+		label string
+		sort  fs.BreakdownSort
+		x     int32
+	}{
+		{"Name", fs.SortByKey, x + 16},
+		{"Files", fs.SortByFiles, x + 170},
+		{"Size", fs.SortByBytes, x + 230},
+	}
+	for _, col := range cols {
+		label := col.label
+		if state.SortBy == col.sort {
+			if state.Desc {
+				label += " v"
+			} else {
+				label += " ^"
+			}
+		}
+		DrawTextUI(label, col.x, y, SmallFontSize, color.TextSecondary)
+		if clicked && hit(col.x-4, y-2, 60, 16) {
+			if state.SortBy == col.sort {
+				state.Desc = !state.Desc
+			} else {
+				state.SortBy = col.sort
+				state.Desc = col.sort != fs.SortByKey
+			}
+		}
+	}
+	DrawTextUI("%", x+300, y, SmallFontSize, color.TextSecondary)
+	y += 16
+	rl.DrawRectangle(x, y, panelW-24, 1, color.BorderColor)
+	y += 4
+
+	// Table rows
+	rows := state.Rows(b)
+	rowH := int32(18)
+	visible := int((panelY + panelH - 24 - y) / rowH)
+	if visible < 1 {
+		visible = 1
+	}
+	if hit(panelX, y, panelW, int32(visible)*rowH) {
+		state.scroll -= int(rl.GetMouseWheelMove())
+	}
+	if state.scroll > len(rows)-visible {
+		state.scroll = len(rows) - visible
+	}
+	if state.scroll < 0 {
+		state.scroll = 0
+	}
+
+	for i := state.scroll; i < len(rows) && i < state.scroll+visible; i++ {
+		row := rows[i]
+		inRow := hit(panelX+2, y, panelW-4, rowH)
+		if row.Key == state.Active {
+			rl.DrawRectangle(panelX+2, y, panelW-4, rowH, color.SelectionBg)
+		} else if inRow {
+			rl.DrawRectangle(panelX+2, y, panelW-4, rowH, color.HoverBg)
+		}
+
+		rl.DrawRectangle(x, y+4, 10, 10, colorOf[row.Key])
+		name := RowLabel(row.Key)
+		if len(name) > 22 {
+			name = name[:20] + ".."
+		}
+		textColor := color.TextPrimary
+		if state.Active != "" && row.Key != state.Active {
+			textColor = color.TextDim
+		}
+		DrawTextUI(name, cols[0].x, y+2, SmallFontSize, textColor)
+		DrawTextUI(fmt.Sprintf("%d", row.Files), cols[1].x, y+2, SmallFontSize, color.TextSecondary)
+		DrawTextUI(FormatSize(row.Bytes), cols[2].x, y+2, SmallFontSize, color.TextSecondary)
+		pct := 0.0
+		if b.TotalBytes > 0 {
+			pct = 100 * float64(row.Bytes) / float64(b.TotalBytes)
+		}
+		DrawTextUI(fmt.Sprintf("%.1f", pct), x+300, y+2, SmallFontSize, color.TextSecondary)
+
+		if inRow && clicked {
+			if state.Active == row.Key {
+				state.Active = ""
+			} else {
+				state.Active = row.Key
+			}
+			changed = true
+		}
+		y += rowH
+	}
+
+	// Footer hint
+	hint := "Click a row to highlight it in 3D - E to close"
+	DrawTextUI(hint, x, panelY+panelH-18, SmallFontSize, color.TextDim)
+
+	return changed
+}
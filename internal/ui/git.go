@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/color"
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+	"github.com/Crank-Git/FSNRedux/internal/git"
+)
+
+// GitBadge returns a one-letter status badge and its color.
+// Clean entries and entries outside a repository have no badge ("").
+func GitBadge(status fs.GitStatus) (string, rl.Color) {
+	switch status {
+	case fs.GitModified:
+		return "M", color.ColorFromGit(status)
+	case fs.GitUntracked:
+		return "U", color.ColorFromGit(status)
+	case fs.GitIgnored:
+		return "I", color.ColorFromGit(status)
+	default:
+		return "", rl.Color{}
+	}
+}
+
+// repoRows returns inspect-panel rows describing a repository root.
+func repoRows(repo *git.Repo) [][2]string {
+	if repo == nil {
+		return nil
+	}
+	branch := repo.Branch
+	if branch == "" {
+		branch = "(detached HEAD)"
+	}
+	rows := [][2]string{{"Branch:", branch}}
+	if repo.Head.Hash != "" {
+		subject := repo.Head.Subject
+		if len(subject) > 36 {
+			subject = subject[:34] + ".."
+		}
+		rows = append(rows,
+			[2]string{"Last commit:", repo.Head.Hash + " " + subject},
+			[2]string{"Committed:", fmt.Sprintf("%s by %s",
+				repo.Head.Time.Format("2006-01-02 15:04"), repo.Head.Author)},
+		)
+	}
+	rows = append(rows,
+		[2]string{"Tracked files:", fmt.Sprintf("%d", repo.Tracked)},
+		[2]string{"Changes:", fmt.Sprintf("%d modified, %d untracked, %d ignored",
+			repo.Modified, repo.Untracked, repo.Ignored)},
+	)
+	return rows
+}
@@ -9,186 +9,66 @@ import (
 	rl "github.com/gen2brain/raylib-go/raylib"
 	"github.com/Crank-Git/FSNRedux/internal/color"
 	"github.com/Crank-Git/FSNRedux/internal/fs"
+	"github.com/Crank-Git/FSNRedux/internal/git"
+	"github.com/Crank-Git/FSNRedux/internal/layout"
 )
 
-// fileTypeEntry maps a file extension to an icon label and category.
-type fileTypeEntry struct {
-	Icon     string
-	Category string
-}
-
-var fileTypeMap = map[string]fileTypeEntry{
-	// Source code
-	".go":    {"Go", "Source Code"},
-	".py":    {"Py", "Source Code"},
-	".js":    {"JS", "Source Code"},
-	".ts":    {"TS", "Source Code"},
-	".tsx":   {"TSX", "Source Code"},
-	".jsx":   {"JSX", "Source Code"},
-	".rs":    {"Rs", "Source Code"},
-	".c":     {"C", "Source Code"},
-	".cpp":   {"C++", "Source Code"},
-	".cc":    {"C++", "Source Code"},
-	".h":     {"H", "Header File"},
-	".hpp":   {"H++", "Header File"},
-	".java":  {"Jv", "Source Code"},
-	".kt":    {"Kt", "Source Code"},
-	".swift": {"Sw", "Source Code"},
-	".rb":    {"Rb", "Source Code"},
-	".php":   {"PHP", "Source Code"},
-	".cs":    {"C#", "Source Code"},
-	".lua":   {"Lua", "Source Code"},
-	".zig":   {"Zig", "Source Code"},
-	".dart":  {"Drt", "Source Code"},
-	".scala": {"Scl", "Source Code"},
-	".ex":    {"Ex", "Source Code"},
-	".exs":   {"Exs", "Source Code"},
-	".erl":   {"Erl", "Source Code"},
-	".hs":    {"Hs", "Source Code"},
-	".ml":    {"ML", "Source Code"},
-	".r":     {"R", "Source Code"},
-	".m":     {"OC", "Source Code"},
-	// Shell / Scripts
-	".sh":   {"Sh", "Shell Script"},
-	".bash": {"Sh", "Shell Script"},
-	".zsh":  {"Sh", "Shell Script"},
-	".fish": {"Sh", "Shell Script"},
-	".ps1":  {"PS", "PowerShell Script"},
-	".bat":  {"Bat", "Batch Script"},
-	// Markup / Config
-	".html": {"HTM", "Markup"},
-	".htm":  {"HTM", "Markup"},
-	".xml":  {"XML", "Markup"},
-	".svg":  {"SVG", "Vector Image"},
-	".css":  {"CSS", "Stylesheet"},
-	".scss": {"SCS", "Stylesheet"},
-	".less": {"Les", "Stylesheet"},
-	".json": {"JSN", "Data (JSON)"},
-	".yaml": {"YML", "Data (YAML)"},
-	".yml":  {"YML", "Data (YAML)"},
-	".toml": {"TML", "Data (TOML)"},
-	".ini":  {"INI", "Configuration"},
-	".cfg":  {"CFG", "Configuration"},
-	".env":  {"ENV", "Configuration"},
-	// Documents
-	".md":   {"MD", "Markdown"},
-	".txt":  {"TXT", "Plain Text"},
-	".rst":  {"RST", "Markup Document"},
-	".pdf":  {"PDF", "PDF Document"},
-	".doc":  {"DOC", "Word Document"},
-	".docx": {"DOC", "Word Document"},
-	".xls":  {"XLS", "Spreadsheet"},
-	".xlsx": {"XLS", "Spreadsheet"},
-	".csv":  {"CSV", "Comma-Separated"},
-	".ppt":  {"PPT", "Presentation"},
-	".pptx": {"PPT", "Presentation"},
-	// Images
-	".png":  {"PNG", "Image"},
-	".jpg":  {"JPG", "Image"},
-	".jpeg": {"JPG", "Image"},
-	".gif":  {"GIF", "Image"},
-	".bmp":  {"BMP", "Image"},
-	".webp": {"WBP", "Image"},
-	".ico":  {"ICO", "Icon"},
-	".tiff": {"TIF", "Image"},
-	// Audio
-	".mp3":  {"MP3", "Audio"},
-	".wav":  {"WAV", "Audio"},
-	".flac": {"FLC", "Audio"},
-	".ogg":  {"OGG", "Audio"},
-	".aac":  {"AAC", "Audio"},
-	".m4a":  {"M4A", "Audio"},
-	// Video
-	".mp4":  {"MP4", "Video"},
-	".mkv":  {"MKV", "Video"},
-	".avi":  {"AVI", "Video"},
-	".mov":  {"MOV", "Video"},
-	".webm": {"WBM", "Video"},
-	".wmv":  {"WMV", "Video"},
-	// Archives
-	".zip":  {"ZIP", "Archive"},
-	".tar":  {"TAR", "Archive"},
-	".gz":   {"GZ", "Archive"},
-	".bz2":  {"BZ2", "Archive"},
-	".xz":   {"XZ", "Archive"},
-	".7z":   {"7Z", "Archive"},
-	".rar":  {"RAR", "Archive"},
-	".zst":  {"ZST", "Archive"},
-	// Binary / Executable
-	".exe":  {"EXE", "Executable"},
-	".dll":  {"DLL", "Library"},
-	".so":   {"SO", "Shared Library"},
-	".dylib": {"DYL", "Shared Library"},
-	".bin":  {"BIN", "Binary"},
-	".o":    {"OBJ", "Object File"},
-	".a":    {"LIB", "Static Library"},
-	".wasm": {"WSM", "WebAssembly"},
-	// Database
-	".db":     {"DB", "Database"},
-	".sqlite": {"SQL", "Database"},
-	".sql":    {"SQL", "SQL Script"},
-	// Build / Lock
-	".lock": {"LCK", "Lock File"},
-	".sum":  {"SUM", "Checksum"},
-	".mod":  {"MOD", "Module File"},
-}
-
 // FileTypeIcon returns a short icon label and category for a filename.
 func FileTypeIcon(name string, isDir bool) (icon string, category string) {
 	if isDir {
 		return "DIR", "Directory"
 	}
-	ext := strings.ToLower(filepath.Ext(name))
-	if entry, ok := fileTypeMap[ext]; ok {
-		return entry.Icon, entry.Category
+	ext := fs.Extension(name)
+	if ft, ok := fs.LookupFileType(name); ok {
+		return ft.Icon, ft.Category
 	}
 	if ext != "" {
-		return strings.ToUpper(strings.TrimPrefix(ext, ".")), "File"
+		return strings.ToUpper(strings.TrimPrefix(ext, ".")), fs.OtherCategory
 	}
-	return "---", "File"
+	return "---", fs.OtherCategory
 }
 
 // FileTypeIconColor returns a color for the file type icon badge.
 func FileTypeIconColor(icon string) rl.Color {
 	switch icon {
 	case "Go":
-		return rl.NewColor(0, 173, 216, 255)   // cyan
+		return rl.NewColor(0, 173, 216, 255) // cyan
 	case "Py":
-		return rl.NewColor(55, 118, 171, 255)   // blue
+		return rl.NewColor(55, 118, 171, 255) // blue
 	case "JS", "JSX":
-		return rl.NewColor(247, 223, 30, 255)   // yellow
+		return rl.NewColor(247, 223, 30, 255) // yellow
 	case "TS", "TSX":
-		return rl.NewColor(49, 120, 198, 255)   // blue
+		return rl.NewColor(49, 120, 198, 255) // blue
 	case "Rs":
-		return rl.NewColor(222, 165, 132, 255)  // rust orange
+		return rl.NewColor(222, 165, 132, 255) // rust orange
 	case "C", "C++", "H", "H++":
-		return rl.NewColor(85, 85, 255, 255)    // blue
+		return rl.NewColor(85, 85, 255, 255) // blue
 	case "Jv":
-		return rl.NewColor(248, 152, 32, 255)   // java orange
+		return rl.NewColor(248, 152, 32, 255) // java orange
 	case "Rb":
-		return rl.NewColor(204, 52, 45, 255)    // ruby red
+		return rl.NewColor(204, 52, 45, 255) // ruby red
 	case "Sh":
-		return rl.NewColor(78, 154, 6, 255)     // green
+		return rl.NewColor(78, 154, 6, 255) // green
 	case "HTM", "CSS", "SCS":
-		return rl.NewColor(228, 77, 38, 255)    // html orange
+		return rl.NewColor(228, 77, 38, 255) // html orange
 	case "MD", "TXT", "RST":
-		return rl.NewColor(180, 180, 180, 255)  // light gray
+		return rl.NewColor(180, 180, 180, 255) // light gray
 	case "JSN", "YML", "TML", "XML":
-		return rl.NewColor(160, 160, 80, 255)   // olive
+		return rl.NewColor(160, 160, 80, 255) // olive
 	case "PNG", "JPG", "GIF", "BMP", "SVG", "WBP", "ICO":
-		return rl.NewColor(140, 200, 60, 255)   // green
+		return rl.NewColor(140, 200, 60, 255) // green
 	case "MP3", "WAV", "FLC", "OGG", "AAC", "M4A":
-		return rl.NewColor(230, 126, 34, 255)   // orange
+		return rl.NewColor(230, 126, 34, 255) // orange
 	case "MP4", "MKV", "AVI", "MOV", "WBM":
-		return rl.NewColor(155, 89, 182, 255)   // purple
+		return rl.NewColor(155, 89, 182, 255) // purple
 	case "ZIP", "TAR", "GZ", "RAR", "7Z":
-		return rl.NewColor(127, 140, 141, 255)  // gray
+		return rl.NewColor(127, 140, 141, 255) // gray
 	case "PDF", "DOC", "XLS", "PPT":
-		return rl.NewColor(192, 57, 43, 255)    // dark red
+		return rl.NewColor(192, 57, 43, 255) // dark red
 	case "DIR":
-		return rl.NewColor(255, 193, 7, 255)    // amber
+		return rl.NewColor(255, 193, 7, 255) // amber
 	default:
-		return rl.NewColor(149, 165, 166, 255)  // silver
+		return rl.NewColor(149, 165, 166, 255) // silver
 	}
 }
 
@@ -292,10 +172,6 @@ func DrawSelectedTooltip(entry *fs.Entry, screenX, screenY float32) {
 		return
 	}
 
-	// Position below and to the right of the node's screen position
-	tx := int32(screenX) + 12
-	ty := int32(screenY) + 12
-
 	// Build info lines
 	name := entry.Name
 	if len(name) > 24 {
@@ -308,6 +184,27 @@ func DrawSelectedTooltip(entry *fs.Entry, screenX, screenY float32) {
 	sizeStr := FormatSize(entry.Size)
 	line2 := fmt.Sprintf("%s  %s", entry.Type.String(), sizeStr)
 
+	nameColor := color.TextPrimary
+	if entry.Type == fs.TypeDir {
+		nameColor = color.Active.DirAccent
+	}
+	drawTooltip(icon, name, nameColor, line2, screenX, screenY)
+}
+
+// DrawGroupTooltip renders the floating info card for a group of small files.
+func DrawGroupTooltip(g *layout.Group, screenX, screenY float32) {
+	if g == nil {
+		return
+	}
+	drawTooltip(groupIcon, GroupLabel(g), color.TextPrimary, "Double-click to expand", screenX, screenY)
+}
+
+// drawTooltip draws a card with an icon badge, a name and a second line
+// below and to the right of (screenX, screenY), kept on screen.
+func drawTooltip(icon, name string, nameColor rl.Color, line2 string, screenX, screenY float32) {
+	tx := int32(screenX) + 12
+	ty := int32(screenY) + 12
+
 	// Measure (account for badge + gap + name)
 	badgeTextW := MeasureTextUI(icon, SmallFontSize) + 12 + 8 // padding + gap
 	w1 := badgeTextW + MeasureTextUI(name, FontSize)
@@ -338,15 +235,72 @@ func DrawSelectedTooltip(entry *fs.Entry, screenX, screenY float32) {
 	))
 	rl.DrawRectangleLines(tx, ty, boxW, boxH, color.BorderColor)
 
-	nameColor := color.TextPrimary
-	if entry.Type == fs.TypeDir {
-		nameColor = color.Active.DirAccent
-	}
 	bw := drawIconBadge(icon, tx+8, ty+4)
 	DrawTextUI(name, tx+8+bw+6, ty+4, FontSize, nameColor)
 	DrawTextUI(line2, tx+8, ty+20, SmallFontSize, color.TextSecondary)
 }
 
+// groupIcon is the badge label of a group of small files.
+const groupIcon = "..."
+
+// GroupLabel describes a group of small files, e.g. "1,203 files, 4.1 MB".
+func GroupLabel(g *layout.Group) string {
+	return fmt.Sprintf("%s files, %s", FormatCount(len(g.Members)), FormatSize(g.Size))
+}
+
+// FormatCount formats n with thousands separators.
+func FormatCount(n int) string {
+	s := fmt.Sprint(n)
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	if neg {
+		s = "-" + s
+	}
+	return s
+}
+
+// DrawGroupInfo renders the info panel for a selected group of small files.
+func DrawGroupInfo(g *layout.Group, screenHeight int32) {
+	panelX := int32(0)
+	panelY := screenHeight - InfoPanelHeight
+	panelW := SidebarWidth
+
+	DrawPanel(panelX, panelY, panelW, InfoPanelHeight, color.SidebarBg)
+	rl.DrawRectangle(panelX+8, panelY, panelW-16, 1, color.BorderColor)
+
+	y := panelY + 6
+	badgeW := drawIconBadge(groupIcon, panelX+8, y)
+	DrawTextUI(GroupLabel(g), panelX+8+badgeW+6, y, FontSize, color.TextPrimary)
+	y += 18
+
+	// Directory holding the files (truncated)
+	pathStr := g.Dir.Path
+	maxPathChars := int((float32(panelW) - 16) / 7)
+	if len(pathStr) > maxPathChars {
+		pathStr = "..." + pathStr[len(pathStr)-maxPathChars+3:]
+	}
+	DrawTextUI(pathStr, panelX+8, y, SmallFontSize, color.TextDim)
+	y += 14
+
+	// Largest member, then how to see the rest
+	if len(g.Members) > 0 {
+		largest := g.Members[0]
+		name := largest.Name
+		if len(name) > 22 {
+			name = name[:20] + ".."
+		}
+		DrawTextUI("Largest: "+name, panelX+8, y, SmallFontSize, color.TextSecondary)
+		sizeStr := FormatSize(largest.Size)
+		sizeW := MeasureTextUI(sizeStr, SmallFontSize)
+		DrawTextUI(sizeStr, panelW-sizeW-8, y, SmallFontSize, color.TextSecondary)
+		y += 14
+	}
+	DrawTextUI("Enter or double-click to expand", panelX+8, y, SmallFontSize, color.TextDim)
+}
+
 // formatAge converts a duration to a human-readable age string.
 func formatAge(d time.Duration) string {
 	switch {
@@ -390,7 +344,8 @@ func drawIconBadge(icon string, bx, by int32) int32 {
 }
 
 // DrawInspectPanel renders a centered overlay with detailed file/directory info.
-func DrawInspectPanel(info *fs.InspectInfo, screenW, screenH int32) {
+// repo is non-nil when the inspected directory is a git repository root.
+func DrawInspectPanel(info *fs.InspectInfo, repo *git.Repo, screenW, screenH int32) {
 	if info == nil {
 		return
 	}
@@ -400,6 +355,15 @@ func DrawInspectPanel(info *fs.InspectInfo, screenW, screenH int32) {
 	if !info.IsDir {
 		panelH = 220
 	}
+	for _, t := range []time.Time{info.ChangeTime, info.AccessTime, info.BirthTime} {
+		if !t.IsZero() {
+			panelH += 18
+		}
+	}
+	gitRows := repoRows(repo)
+	if len(gitRows) > 0 {
+		panelH += int32(len(gitRows))*18 + 9
+	}
 	panelX := (screenW - panelW) / 2
 	panelY := (screenH - panelH) / 2
 
@@ -463,6 +427,15 @@ func DrawInspectPanel(info *fs.InspectInfo, screenW, screenH int32) {
 		age := time.Since(info.ModTime)
 		drawRow("Age:", formatAge(age))
 	}
+	if !info.ChangeTime.IsZero() {
+		drawRow("Changed:", info.ChangeTime.Format("2006-01-02 15:04:05"))
+	}
+	if !info.AccessTime.IsZero() {
+		drawRow("Accessed:", info.AccessTime.Format("2006-01-02 15:04:05"))
+	}
+	if !info.BirthTime.IsZero() {
+		drawRow("Created:", info.BirthTime.Format("2006-01-02 15:04:05"))
+	}
 
 	if info.IsDir {
 		if info.Loaded {
@@ -474,6 +447,14 @@ func DrawInspectPanel(info *fs.InspectInfo, screenW, screenH int32) {
 		}
 	}
 
+	if len(gitRows) > 0 {
+		rl.DrawRectangle(x, y+2, panelW-32, 1, color.BorderColor)
+		y += 9
+		for _, row := range gitRows {
+			drawRow(row[0], row[1])
+		}
+	}
+
 	// Dismiss hint
 	y = panelY + panelH - 20
 	hint := "Press Space or Escape to close"
@@ -523,6 +504,7 @@ func DrawHelpText(screenWidth, screenHeight int32) {
 		{"Scroll / +/-", "Zoom in/out"},
 		{"WASD / Arrows", "Pan camera"},
 		{"Click", "Select node"},
+		{"Shift+drag", "Box-select nodes"},
 		{"Double-click", "Expand/collapse dir"},
 		{"Enter", "Expand selected dir"},
 		{"Space", "Inspect dir / preview file"},
@@ -534,6 +516,10 @@ func DrawHelpText(screenWidth, screenHeight int32) {
 		{"Ctrl+L", "Go to path"},
 		{"N / P", "Next / prev search result"},
 		{"B", "Birdseye view"},
+		{"C", "Cycle color mode"},
+		{"T", "Time-travel slider"},
+		{"E", "Size breakdown"},
+		{"X", "Export scene to a file"},
 		{",", "Settings"},
 		{"H", "Toggle this help"},
 	}
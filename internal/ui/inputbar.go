@@ -14,14 +14,15 @@ const (
 	InputBarNone   InputBarMode = iota
 	InputBarPath                // Ctrl+L: type a filesystem path
 	InputBarSearch              // Ctrl+F / F: search by name
+	InputBarExport              // X: file to export the scene to
 )
 
 // InputBar is a text input overlay for path entry and search.
 type InputBar struct {
-	Active   bool
-	Mode     InputBarMode
-	Text     string
-	cursor   int
+	Active    bool
+	Mode      InputBarMode
+	Text      string
+	cursor    int
 	submitted bool
 }
 
@@ -135,8 +136,11 @@ func (b *InputBar) Draw(screenWidth int32) {
 
 	// Label
 	label := "Path: "
-	if b.Mode == InputBarSearch {
+	switch b.Mode {
+	case InputBarSearch:
 		label = "Search: "
+	case InputBarExport:
+		label = "Export to: "
 	}
 	labelW := MeasureTextUI(label, FontSize)
 	textY := barY + 6
@@ -155,8 +159,11 @@ func (b *InputBar) Draw(screenWidth int32) {
 
 	// Hint text
 	hint := "Enter to navigate | Esc to cancel"
-	if b.Mode == InputBarSearch {
+	switch b.Mode {
+	case InputBarSearch:
 		hint = "Enter to find | Esc to cancel"
+	case InputBarExport:
+		hint = ".gltf, .obj or .html | Enter to export | Esc to cancel"
 	}
 	hintW := MeasureTextUI(hint, SmallFontSize)
 	DrawTextUI(hint, screenWidth-hintW-8, textY+2, SmallFontSize, color.TextDim)
@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"os"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// labelFontSize is the glyph size of the SDF label atlas. SDF glyphs stay
+// sharp when scaled, so one modest atlas serves every label size.
+const labelFontSize = 48
+
+// sdfFS is raylib's SDF text shader: alpha from the distance field,
+// antialiased over one screen pixel.
+const sdfFS = `#version 330
+in vec2 fragTexCoord;
+in vec4 fragColor;
+uniform sampler2D texture0;
+uniform vec4 colDiffuse;
+out vec4 finalColor;
+void main() {
+    float d = texture(texture0, fragTexCoord).a - 0.5;
+    float w = length(vec2(dFdx(d), dFdy(d)));
+    float alpha = smoothstep(-w, w, d);
+    finalColor = vec4(fragColor.rgb, fragColor.a*alpha);
+}
+`
+
+var (
+	// LabelFont renders world-space labels: an SDF atlas drawn with
+	// LabelShader, or AppFont (with LabelShader unset) when SDF generation fails.
+	LabelFont   rl.Font
+	LabelShader rl.Shader
+	LabelSDF    bool
+
+	labelFontLoaded bool
+)
+
+// LabelMode selects how names are drawn in the scene.
+type LabelMode uint8
+
+const (
+	LabelsScreen    LabelMode = iota // 2D overlays projected from the scene
+	LabelsBillboard                  // world-space text facing the camera
+	LabelsGround                     // directory names on the ground, files billboarded
+)
+
+// labelModeCount is the number of label modes (used for cycling).
+const labelModeCount = 3
+
+// String returns the label mode name.
+func (m LabelMode) String() string {
+	switch m {
+	case LabelsScreen:
+		return "Screen"
+	case LabelsBillboard:
+		return "3D Billboard"
+	case LabelsGround:
+		return "3D Ground"
+	default:
+		return "Unknown"
+	}
+}
+
+// Next returns the label mode that follows m when cycling.
+func (m LabelMode) Next() LabelMode {
+	return (m + 1) % labelModeCount
+}
+
+// loadLabelFont builds the SDF label atlas from a TTF file, falling back to
+// AppFont. It holds the same glyphs as AppFont, so accented names render in
+// 3D labels too.
+func loadLabelFont(path string) {
+	LabelFont, LabelSDF = AppFont, false
+	data, err := os.ReadFile(path)
+	if err != nil || len(data) == 0 {
+		return
+	}
+	glyphs := rl.LoadFontData(data, labelFontSize, nil, fontGlyphs, rl.FontSdf)
+	if len(glyphs) == 0 {
+		return
+	}
+	const padding = 4
+	recs := make([]*rl.Rectangle, 1)
+	atlas := rl.GenImageFontAtlas(glyphs, recs, labelFontSize, padding, 0)
+	if recs[0] == nil {
+		rl.UnloadFontData(glyphs)
+		return
+	}
+	shader := rl.LoadShaderFromMemory("", sdfFS)
+	if !rl.IsShaderValid(shader) {
+		rl.UnloadImage(&atlas)
+		rl.UnloadFontData(glyphs)
+		return
+	}
+
+	LabelFont = rl.Font{
+		BaseSize:     labelFontSize,
+		CharsCount:   int32(len(glyphs)),
+		CharsPadding: padding,
+		Texture:      rl.LoadTextureFromImage(&atlas),
+		Recs:         recs[0],
+		Chars:        &glyphs[0],
+	}
+	rl.UnloadImage(&atlas)
+	rl.SetTextureFilter(LabelFont.Texture, rl.FilterBilinear)
+	LabelShader = shader
+	LabelSDF = true
+	labelFontLoaded = true
+}
+
+// unloadLabelFont frees the SDF atlas and shader.
+func unloadLabelFont() {
+	if labelFontLoaded {
+		rl.UnloadFont(LabelFont)
+		rl.UnloadShader(LabelShader)
+		labelFontLoaded = false
+	}
+}
@@ -0,0 +1,113 @@
+package ui
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/color"
+)
+
+// LegendState holds the color legend's highlight state.
+type LegendState struct {
+	Active     int // highlighted entry index (-1 = none)
+	hoverIndex int // which row is hovered (-1 = none)
+}
+
+// NewLegendState creates a legend with nothing highlighted.
+func NewLegendState() *LegendState {
+	return &LegendState{Active: -1, hoverIndex: -1}
+}
+
+// Reset clears the highlighted entry (e.g. when the color mode changes).
+func (s *LegendState) Reset() {
+	s.Active = -1
+}
+
+// Legend geometry: row height and header height.
+const (
+	legendRowH    = int32(16)
+	legendHeaderH = int32(20)
+)
+
+// LegendRect returns the screen area covered by a legend with the given
+// title and entries (bottom-left corner of the 3D viewport).
+func LegendRect(title string, entries []color.LegendEntry, screenH int32) rl.Rectangle {
+	panelW := int32(140)
+	if w := MeasureTextUI(title, SmallFontSize) + 16; w > panelW {
+		panelW = w
+	}
+	panelH := legendHeaderH + int32(len(entries))*legendRowH + 6
+	return rl.NewRectangle(float32(SidebarWidth+8), float32(screenH-panelH-8), float32(panelW), float32(panelH))
+}
+
+// Captures reports whether the legend is under the mouse, so clicks on it
+// should not reach the 3D picker.
+func (s *LegendState) Captures(title string, entries []color.LegendEntry, screenH int32) bool {
+	if s == nil {
+		return false
+	}
+	return rl.CheckCollisionPointRec(rl.GetMousePosition(), LegendRect(title, entries, screenH))
+}
+
+// DrawLegend renders swatches for the active color mapping in the bottom-left
+// corner of the 3D viewport. Clicking an entry toggles its highlight.
+// Returns true if the highlighted entry changed.
+func DrawLegend(state *LegendState, title string, entries []color.LegendEntry, screenH int32) bool {
+	if state == nil {
+		return false
+	}
+
+	rowH := legendRowH
+	swatch := int32(10)
+	headerH := legendHeaderH
+	rect := LegendRect(title, entries, screenH)
+	panelX, panelY := int32(rect.X), int32(rect.Y)
+	panelW, panelH := int32(rect.Width), int32(rect.Height)
+
+	rl.DrawRectangle(panelX, panelY, panelW, panelH, rl.NewColor(
+		color.Active.SidebarBg.R,
+		color.Active.SidebarBg.G,
+		color.Active.SidebarBg.B,
+		230,
+	))
+	rl.DrawRectangleLines(panelX, panelY, panelW, panelH, color.BorderColor)
+
+	DrawTextUI(title, panelX+8, panelY+4, SmallFontSize, color.TextSecondary)
+	rl.DrawRectangle(panelX+8, panelY+16, panelW-16, 1, color.BorderColor)
+
+	mousePos := rl.GetMousePosition()
+	clicked := rl.IsMouseButtonPressed(rl.MouseButtonLeft)
+	changed := false
+	state.hoverIndex = -1
+
+	y := panelY + headerH
+	for i, entry := range entries {
+		rowRect := rl.NewRectangle(float32(panelX), float32(y), float32(panelW), float32(rowH))
+		inRow := rl.CheckCollisionPointRec(mousePos, rowRect)
+		if i == state.Active {
+			rl.DrawRectangle(panelX+2, y, panelW-4, rowH, color.SelectionBg)
+		} else if inRow {
+			state.hoverIndex = i
+			rl.DrawRectangle(panelX+2, y, panelW-4, rowH, color.HoverBg)
+		}
+
+		rl.DrawRectangle(panelX+8, y+3, swatch, swatch, entry.Color)
+		rl.DrawRectangleLines(panelX+8, y+3, swatch, swatch, color.BorderColor)
+
+		textColor := color.TextPrimary
+		if state.Active >= 0 && i != state.Active {
+			textColor = color.TextDim
+		}
+		DrawTextUI(entry.Label, panelX+8+swatch+8, y+2, SmallFontSize, textColor)
+
+		if inRow && clicked {
+			if state.Active == i {
+				state.Active = -1
+			} else {
+				state.Active = i
+			}
+			changed = true
+		}
+		y += rowH
+	}
+
+	return changed
+}
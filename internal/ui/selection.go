@@ -0,0 +1,27 @@
+package ui
+
+import (
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/color"
+)
+
+// DrawSelectionBox draws the rubber-band rectangle of a box selection.
+func DrawSelectionBox(rect rl.Rectangle) {
+	fill := color.Active.LinkAccent
+	fill.A = 40
+	rl.DrawRectangleRec(rect, fill)
+	rl.DrawRectangleLinesEx(rect, 1, color.Active.LinkAccent)
+}
+
+// DrawSelectionSummary shows how many nodes are box-selected and their total
+// size, centered at the bottom of the 3D view.
+func DrawSelectionSummary(count, files int, bytes int64, screenW, screenH int32) {
+	text := fmt.Sprintf("%d selected (%d files, %s)  Esc=clear", count, files, FormatSize(bytes))
+	tw := MeasureTextUI(text, SmallFontSize)
+	x := SidebarWidth + (screenW-SidebarWidth-tw)/2
+	y := screenH - 28
+	rl.DrawRectangle(x-6, y-3, tw+12, 18, rl.NewColor(0, 0, 0, 180))
+	DrawTextUI(text, x, y, SmallFontSize, color.Active.LinkAccent)
+}
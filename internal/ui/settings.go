@@ -2,35 +2,62 @@ package ui
 
 import (
 	"fmt"
+	"math"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 	"github.com/Crank-Git/FSNRedux/internal/color"
+	"github.com/Crank-Git/FSNRedux/internal/fs"
+	"github.com/Crank-Git/FSNRedux/internal/layout"
+	"github.com/Crank-Git/FSNRedux/internal/shading"
 )
 
 // SettingsAction is returned when the user changes a setting.
 type SettingsAction int
 
 const (
-	SettingsNone       SettingsAction = iota
-	SettingsToggleHidden              // ShowHidden changed
-	SettingsCycleTheme                // Theme changed
-	SettingsDepthUp                   // MaxDepth increased
-	SettingsDepthDown                 // MaxDepth decreased
-	SettingsToggleLegend              // ShowLegend changed
+	SettingsNone           SettingsAction = iota
+	SettingsToggleHidden                  // ShowHidden changed
+	SettingsCycleTheme                    // Theme changed
+	SettingsDepthUp                       // MaxDepth increased
+	SettingsDepthDown                     // MaxDepth decreased
+	SettingsToggleLegend                  // ShowLegend changed
+	SettingsCycleColorMode                // ColorMode changed
+	SettingsCycleTimestamp                // TimeField changed
+	SettingsCycleReference                // Reference changed
+	SettingsCycleShading                  // Shading changed
+	SettingsCycleLabels                   // Labels changed
+	SettingsCycleSort                     // Sort changed
+	SettingsLayoutChanged                 // Geometry changed (slider dragged or reset)
+	SettingsToggleStable                  // Stable changed
+	SettingsToggleFisheye                 // Fisheye changed
 )
 
 // SettingsState holds runtime-modifiable settings and menu state.
 type SettingsState struct {
-	Open       bool
-	ShowHidden bool
-	ShowLegend bool
-	Theme      string // "dark", "light", "auto"
-	MaxDepth   int
-	hoverIndex int // which row is hovered (-1 = none)
+	Open        bool
+	ShowHidden  bool
+	ShowLegend  bool
+	Theme       string // "dark", "light", "auto"
+	MaxDepth    int
+	Prescanning bool // a background scan down to MaxDepth is running
+	ColorMode   color.Mode
+	TimeField   fs.TimeField // timestamp used for age coloring
+	Reference   int          // index into ReferenceOptions
+	Shading     shading.Mode
+	Labels      LabelMode
+	Sort        fs.SortMode
+	Stable      bool            // TreeV and MapV keep children where they were last seen
+	Fisheye     bool            // enlarge the selected directory's subtree (focus+context)
+	Geometry    layout.Geometry // layout sizes and spacings the sliders edit
+	hoverIndex  int             // which row is hovered (-1 = none)
+	dragging    int             // which slider is being dragged (-1 = none)
+
+	// ReferenceOptions labels the reference times ages can be measured against.
+	ReferenceOptions []string
 }
 
 // NewSettingsState creates settings from the initial config values.
-func NewSettingsState(showHidden bool, theme string, maxDepth int, showLegend bool) *SettingsState {
+func NewSettingsState(showHidden bool, theme string, maxDepth int, showLegend bool, colorMode color.Mode) *SettingsState {
 	if theme == "" {
 		theme = "auto"
 	}
@@ -39,7 +66,10 @@ func NewSettingsState(showHidden bool, theme string, maxDepth int, showLegend bo
 		ShowLegend: showLegend,
 		Theme:      theme,
 		MaxDepth:   maxDepth,
+		ColorMode:  colorMode,
+		Geometry:   layout.DefaultGeometry(),
 		hoverIndex: -1,
+		dragging:   -1,
 	}
 }
 
@@ -49,6 +79,24 @@ type settingsRow struct {
 	value string
 }
 
+// settingsSlider is a settings row that drags one layout.Geometry field
+// between min and max in steps of step.
+type settingsSlider struct {
+	label          string
+	field          func(g *layout.Geometry) *float32
+	min, max, step float32
+}
+
+var settingsSliders = []settingsSlider{
+	{"Padding (MapV)", func(g *layout.Geometry) *float32 { return &g.PaddingRatio }, 0, 0.2, 0.01},
+	{"Height Scale", func(g *layout.Geometry) *float32 { return &g.HeightScale }, 0.1, 4, 0.1},
+	{"Min Height", func(g *layout.Geometry) *float32 { return &g.MinHeight }, 0.05, 2, 0.05},
+	{"Max Height", func(g *layout.Geometry) *float32 { return &g.MaxHeight }, 1, 60, 1},
+	{"Row Distance", func(g *layout.Geometry) *float32 { return &g.DirDist }, 0.5, 20, 0.5},
+	{"Dir Spacing", func(g *layout.Geometry) *float32 { return &g.DirSpacing }, 0, 4, 0.1},
+	{"File Spacing", func(g *layout.Geometry) *float32 { return &g.FileSpacing }, 0, 0.5, 0.02},
+}
+
 // DrawSettingsPanel renders the settings menu and returns any action taken.
 func DrawSettingsPanel(state *SettingsState, screenW, screenH int32) SettingsAction {
 	if state == nil || !state.Open {
@@ -66,6 +114,14 @@ func DrawSettingsPanel(state *SettingsState, screenW, screenH int32) SettingsAct
 	if state.ShowLegend {
 		legendStr = "On"
 	}
+	stableStr := "Off"
+	if state.Stable {
+		stableStr = "On"
+	}
+	fisheyeStr := "Off"
+	if state.Fisheye {
+		fisheyeStr = "On"
+	}
 	depthStr := fmt.Sprintf("%d", state.MaxDepth)
 	if state.MaxDepth == 0 {
 		depthStr = "Unlimited"
@@ -75,14 +131,22 @@ func DrawSettingsPanel(state *SettingsState, screenW, screenH int32) SettingsAct
 		{"Show Hidden Files", hiddenStr},
 		{"Show Legend", legendStr},
 		{"Theme", state.Theme},
-		{"Max Scan Depth", depthStr},
+		{"Depth", depthStr},
+		{"Color Mode", state.ColorMode.String()},
+		{"Age Timestamp", state.TimeField.String()},
+		{"Age Reference", state.referenceLabel()},
+		{"Shading", state.Shading.String()},
+		{"Labels", state.Labels.String()},
+		{"Sort Order", state.Sort.String()},
+		{"Stable Layout", stableStr},
+		{"Fisheye Focus", fisheyeStr},
 	}
 
 	// Panel dimensions
 	panelW := int32(320)
 	rowH := int32(32)
 	headerH := int32(36)
-	panelH := headerH + int32(len(rows))*rowH + 24 // +24 for padding + hint
+	panelH := headerH + int32(len(rows)+len(settingsSliders))*rowH + 52 // +52 for padding + hints
 	panelX := (screenW - panelW) / 2
 	panelY := (screenH - panelH) / 2
 
@@ -158,6 +222,30 @@ func DrawSettingsPanel(state *SettingsState, screenW, screenH int32) SettingsAct
 					state.MaxDepth++
 					action = SettingsDepthUp
 				}
+			case 4: // Cycle color mode
+				state.ColorMode = state.ColorMode.Next()
+				action = SettingsCycleColorMode
+			case 5: // Cycle age timestamp
+				state.TimeField = state.TimeField.Next()
+				action = SettingsCycleTimestamp
+			case 6: // Cycle age reference
+				state.cycleReference()
+				action = SettingsCycleReference
+			case 7: // Cycle shading
+				state.Shading = state.Shading.Next()
+				action = SettingsCycleShading
+			case 8: // Cycle label mode
+				state.Labels = state.Labels.Next()
+				action = SettingsCycleLabels
+			case 9: // Cycle sort order
+				state.Sort = state.Sort.Next()
+				action = SettingsCycleSort
+			case 10: // Toggle stable layout
+				state.Stable = !state.Stable
+				action = SettingsToggleStable
+			case 11: // Toggle fisheye
+				state.Fisheye = !state.Fisheye
+				action = SettingsToggleFisheye
 			}
 		}
 	}
@@ -186,10 +274,44 @@ func DrawSettingsPanel(state *SettingsState, screenW, screenH int32) SettingsAct
 		state.MaxDepth++
 		action = SettingsDepthUp
 	}
+	if rl.IsKeyPressed(rl.KeyFive) || rl.IsKeyPressed(rl.KeyKp5) {
+		state.ColorMode = state.ColorMode.Next()
+		action = SettingsCycleColorMode
+	}
+	if rl.IsKeyPressed(rl.KeySix) || rl.IsKeyPressed(rl.KeyKp6) {
+		state.TimeField = state.TimeField.Next()
+		action = SettingsCycleTimestamp
+	}
+	if rl.IsKeyPressed(rl.KeySeven) || rl.IsKeyPressed(rl.KeyKp7) {
+		state.cycleReference()
+		action = SettingsCycleReference
+	}
+	if rl.IsKeyPressed(rl.KeyEight) || rl.IsKeyPressed(rl.KeyKp8) {
+		state.Shading = state.Shading.Next()
+		action = SettingsCycleShading
+	}
+	if rl.IsKeyPressed(rl.KeyNine) || rl.IsKeyPressed(rl.KeyKp9) {
+		state.Labels = state.Labels.Next()
+		action = SettingsCycleLabels
+	}
+	if rl.IsKeyPressed(rl.KeyZero) || rl.IsKeyPressed(rl.KeyKp0) {
+		state.Sort = state.Sort.Next()
+		action = SettingsCycleSort
+	}
+
+	// Layout sliders below the rows
+	for j := range settingsSliders {
+		ry := panelY + headerH + int32(len(rows)+j)*rowH
+		if state.drawSlider(j, panelX, ry, panelW, rowH, mousePos) {
+			action = SettingsLayoutChanged
+		}
+	}
 
-	// Depth controls hint for row 4
-	depthHintY := panelY + headerH + int32(len(rows))*rowH + 4
+	// Depth and slider hints
+	depthHintY := panelY + headerH + int32(len(rows)+len(settingsSliders))*rowH + 4
 	DrawTextUI("Depth: click left(-) / right(+) or press 4", panelX+12, depthHintY, SmallFontSize, color.TextDim)
+	DrawTextUI(state.depthSummary(), panelX+12, depthHintY+14, SmallFontSize, color.TextSecondary)
+	DrawTextUI("Sliders: drag to adjust, right-click to reset", panelX+12, depthHintY+28, SmallFontSize, color.TextDim)
 
 	// Close hint
 	hintY := panelY + panelH - 16
@@ -199,3 +321,91 @@ func DrawSettingsPanel(state *SettingsState, screenW, screenH int32) SettingsAct
 
 	return action
 }
+
+// drawSlider draws slider j as a row at y and handles dragging it. It
+// returns true when the value changed.
+func (s *SettingsState) drawSlider(j int, x, y, w, h int32, mouse rl.Vector2) bool {
+	sl := settingsSliders[j]
+	value := sl.field(&s.Geometry)
+
+	trackX := x + 150
+	trackW := w - 150 - 64
+	inRow := int32(mouse.X) >= x && int32(mouse.X) < x+w && int32(mouse.Y) >= y && int32(mouse.Y) < y+h
+	if inRow {
+		rl.DrawRectangle(x+4, y, w-8, h, color.HoverBg)
+	}
+	DrawTextUI(sl.label, x+16, y+8, FontSize, color.TextPrimary)
+
+	old := *value
+	switch {
+	case inRow && rl.IsMouseButtonPressed(rl.MouseButtonRight):
+		def := layout.DefaultGeometry()
+		*value = *sl.field(&def)
+	case inRow && rl.IsMouseButtonPressed(rl.MouseButtonLeft) && int32(mouse.X) >= trackX-6:
+		s.dragging = j
+	}
+	if s.dragging == j {
+		if !rl.IsMouseButtonDown(rl.MouseButtonLeft) {
+			s.dragging = -1
+		} else {
+			*value = sl.at((mouse.X - float32(trackX)) / float32(trackW))
+		}
+	}
+	// Keep the height range valid whichever end moved
+	if s.Geometry.MaxHeight < s.Geometry.MinHeight {
+		if value == &s.Geometry.MinHeight {
+			s.Geometry.MaxHeight = s.Geometry.MinHeight
+		} else {
+			s.Geometry.MinHeight = s.Geometry.MaxHeight
+		}
+	}
+
+	// Track, fill up to the value, and knob
+	frac := (*value - sl.min) / (sl.max - sl.min)
+	frac = min(max(frac, 0), 1)
+	ty := y + h/2 - 2
+	rl.DrawRectangle(trackX, ty, trackW, 4, color.BorderColor)
+	rl.DrawRectangle(trackX, ty, int32(frac*float32(trackW)), 4, color.Active.LinkAccent)
+	rl.DrawCircle(trackX+int32(frac*float32(trackW)), ty+2, 6, color.Active.LinkAccent)
+
+	valStr := fmt.Sprintf("%.2f", *value)
+	valW := MeasureTextUI(valStr, FontSize)
+	DrawTextUI(valStr, x+w-valW-16, y+8, FontSize, color.Active.LinkAccent)
+	return *value != old
+}
+
+// at returns the slider value at fraction f of the track, snapped to a step.
+func (sl settingsSlider) at(f float32) float32 {
+	f = min(max(f, 0), 1)
+	v := sl.min + f*(sl.max-sl.min)
+	return sl.min + float32(math.Round(float64((v-sl.min)/sl.step)))*sl.step
+}
+
+// depthSummary says what the depth setting does: how far the tree is
+// scanned in the background, expanded on open, and laid out.
+func (s *SettingsState) depthSummary() string {
+	scan := "Pre-scan"
+	if s.Prescanning {
+		scan = "Pre-scanning"
+	}
+	if s.MaxDepth == 0 {
+		return scan + " all, expand root only, no cap"
+	}
+	return fmt.Sprintf("%s %d, expand %d on open, cap %d", scan, s.MaxDepth, s.MaxDepth, s.MaxDepth)
+}
+
+// referenceLabel returns the label of the selected reference time.
+func (s *SettingsState) referenceLabel() string {
+	if s.Reference < 0 || s.Reference >= len(s.ReferenceOptions) {
+		return "now"
+	}
+	return s.ReferenceOptions[s.Reference]
+}
+
+// cycleReference advances to the next reference-time option.
+func (s *SettingsState) cycleReference() {
+	if len(s.ReferenceOptions) == 0 {
+		return
+	}
+	s.Reference = (s.Reference + 1) % len(s.ReferenceOptions)
+}
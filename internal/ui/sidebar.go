@@ -14,7 +14,14 @@ type TreeViewState struct {
 	ExpandedDirs map[string]bool
 	SelectedPath string
 	HoveredPath  string
-	rows         []treeRow // computed each frame
+	Sort         fs.SortMode // order of each directory's rows
+	rows         []treeRow   // computed each frame
+
+	// Children of expanded directories in Sort order, kept across frames
+	// for the tree rooted at sortedRoot (see sortedChildren)
+	sorted     map[*fs.Entry]sortedDir
+	sortedMode fs.SortMode
+	sortedRoot *fs.Entry
 
 	// Sidebar search
 	SearchActive bool
@@ -29,6 +36,13 @@ type treeRow struct {
 	Y     float32
 }
 
+// sortedDir caches a directory's children in sort order, along with the
+// Children slice they were sorted from.
+type sortedDir struct {
+	from     []*fs.Entry
+	children []*fs.Entry
+}
+
 // NewTreeViewState creates initial sidebar state with root expanded.
 func NewTreeViewState(rootPath string) *TreeViewState {
 	return &TreeViewState{
@@ -38,9 +52,9 @@ func NewTreeViewState(rootPath string) *TreeViewState {
 
 // SidebarSearchState holds the search field state in the sidebar.
 type SidebarSearchState struct {
-	Active  bool
-	Text    string
-	cursor  int
+	Active bool
+	Text   string
+	cursor int
 }
 
 // DrawSidebar renders the file tree sidebar and returns the selected path if clicked.
@@ -140,6 +154,11 @@ func DrawSidebar(tree *fs.Tree, state *TreeViewState, screenHeight int32) string
 	rl.DrawRectangle(panelX+8, panelY+headerH-1, panelW-16, 1, color.BorderColor)
 
 	// Compute visible rows
+	if state.sorted == nil || state.sortedMode != state.Sort || state.sortedRoot != tree.Root {
+		state.sorted = make(map[*fs.Entry]sortedDir)
+		state.sortedMode = state.Sort
+		state.sortedRoot = tree.Root
+	}
 	state.rows = state.rows[:0]
 	flattenTree(tree.Root, 0, state, &state.rows)
 
@@ -234,9 +253,16 @@ func DrawSidebar(tree *fs.Tree, state *TreeViewState, screenHeight int32) string
 			textX += 8
 		}
 
+		// Git status badge, right-aligned
+		badgeW := float32(0)
+		if badge, badgeColor := GitBadge(row.Entry.Git); badge != "" {
+			badgeW = 14
+			DrawTextUI(badge, panelX+panelW-18, int32(rowY+3), FontSize, badgeColor)
+		}
+
 		// Name (truncate if too long)
 		name := row.Entry.Name
-		maxChars := int((float32(panelW) - textX - 8) / 8) // approximate char width
+		maxChars := int((float32(panelW) - textX - 8 - badgeW) / 8) // approximate char width
 		if maxChars > 0 && len(name) > maxChars {
 			name = name[:maxChars-2] + ".."
 		}
@@ -279,12 +305,29 @@ func flattenTree(entry *fs.Entry, depth int, state *TreeViewState, rows *[]treeR
 	*rows = append(*rows, treeRow{Entry: entry, Depth: depth})
 
 	if entry.Type == fs.TypeDir && state.ExpandedDirs[entry.Path] {
-		for _, child := range entry.Children {
+		for _, child := range state.sortedChildren(entry) {
 			flattenTree(child, depth+1, state, rows)
 		}
 	}
 }
 
+// sortedChildren returns entry's children in Sort order, sorting them only
+// when the directory is new to the cache or has been (re)loaded since.
+func (s *TreeViewState) sortedChildren(entry *fs.Entry) []*fs.Entry {
+	c, ok := s.sorted[entry]
+	if ok && sameSlice(c.from, entry.Children) {
+		return c.children
+	}
+	c = sortedDir{from: entry.Children, children: fs.Sorted(entry.Children, s.Sort)}
+	s.sorted[entry] = c
+	return c.children
+}
+
+// sameSlice reports whether a and b are the same slice of the same array.
+func sameSlice(a, b []*fs.Entry) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
+}
+
 // FormatSize returns a human-readable file size string.
 func FormatSize(size int64) string {
 	switch {
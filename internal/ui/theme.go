@@ -21,6 +21,10 @@ var (
 	fontLoaded bool
 )
 
+// fontGlyphs is how many codepoints the font atlases hold, counting up from
+// the space: ASCII, Latin-1 and most of Latin Extended-A.
+const fontGlyphs = 256
+
 // LoadFont attempts to load a system TTF font. Call after rl.InitWindow().
 func LoadFont() {
 	candidates := systemFontPaths()
@@ -29,21 +33,24 @@ func LoadFont() {
 			// Load at large atlas size (48px) for crisp rendering at all display sizes.
 			// Text is drawn at 11-16px but the high-res atlas + bilinear filter
 			// produces sharp glyphs, especially on HiDPI/Retina screens.
-			AppFont = rl.LoadFontEx(path, 48, nil, 256)
+			AppFont = rl.LoadFontEx(path, 48, nil, fontGlyphs)
 			if AppFont.BaseSize > 0 {
 				rl.SetTextureFilter(AppFont.Texture, rl.FilterBilinear)
 				fontLoaded = true
+				loadLabelFont(path)
 				return
 			}
 		}
 	}
 	// Fallback: use raylib default
 	AppFont = rl.GetFontDefault()
+	LabelFont = AppFont
 	fontLoaded = true
 }
 
 // UnloadFont frees the loaded font. Call before rl.CloseWindow().
 func UnloadFont() {
+	unloadLabelFont()
 	if fontLoaded && AppFont.BaseSize > 0 {
 		rl.UnloadFont(AppFont)
 	}
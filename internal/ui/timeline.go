@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/Crank-Git/FSNRedux/internal/color"
+)
+
+// TimelineHeight is the height of the time-travel slider panel.
+const TimelineHeight = int32(40)
+
+// TimelineState holds the time-travel slider state.
+type TimelineState struct {
+	Active   bool
+	Value    float32 // slider position: 0 = oldest entry, 1 = scan time
+	dragging bool
+}
+
+// NewTimelineState creates an inactive slider parked at the scan time.
+func NewTimelineState() *TimelineState {
+	return &TimelineState{Value: 1}
+}
+
+// TimelineRect returns the screen area covered by the slider panel.
+func TimelineRect(screenW int32) rl.Rectangle {
+	x := SidebarWidth + 8
+	return rl.NewRectangle(float32(x), float32(BreadcrumbHeight+6),
+		float32(screenW-x-8), float32(TimelineHeight))
+}
+
+// Captures reports whether the slider owns the mouse this frame (hovered or dragging),
+// so the 3D camera should ignore it.
+func (s *TimelineState) Captures(screenW int32) bool {
+	if s == nil || !s.Active {
+		return false
+	}
+	return s.dragging || rl.CheckCollisionPointRec(rl.GetMousePosition(), TimelineRect(screenW))
+}
+
+// DrawTimeline renders the time-travel slider across the top of the 3D viewport.
+// from/to are the slider's end points and shown is the (animated) virtual now.
+// Drag the handle or press [ / ] to scrub; the keys are ignored unless keys
+// is set (false while a text field has focus). Returns true if Value changed.
+func DrawTimeline(state *TimelineState, from, to, shown time.Time, screenW int32, keys bool) bool {
+	if state == nil || !state.Active {
+		return false
+	}
+
+	rect := TimelineRect(screenW)
+	panelX, panelY := int32(rect.X), int32(rect.Y)
+	panelW, panelH := int32(rect.Width), int32(rect.Height)
+
+	rl.DrawRectangle(panelX, panelY, panelW, panelH, rl.NewColor(
+		color.Active.SidebarBg.R,
+		color.Active.SidebarBg.G,
+		color.Active.SidebarBg.B,
+		230,
+	))
+	rl.DrawRectangleLines(panelX, panelY, panelW, panelH, color.BorderColor)
+
+	// Header: current virtual date centered, range ends at the sides
+	title := fmt.Sprintf("Time travel: %s", shown.Format("2006-01-02"))
+	titleW := MeasureTextUI(title, SmallFontSize)
+	DrawTextUI(title, panelX+(panelW-titleW)/2, panelY+4, SmallFontSize, color.TextPrimary)
+	DrawTextUI(from.Format("2006-01-02"), panelX+8, panelY+4, SmallFontSize, color.TextDim)
+	toStr := to.Format("2006-01-02")
+	DrawTextUI(toStr, panelX+panelW-MeasureTextUI(toStr, SmallFontSize)-8, panelY+4, SmallFontSize, color.TextDim)
+
+	// Track
+	trackX := panelX + 12
+	trackW := panelW - 24
+	trackY := panelY + 26
+	rl.DrawRectangle(trackX, trackY, trackW, 3, color.BorderColor)
+	filledW := int32(float32(trackW) * state.Value)
+	rl.DrawRectangle(trackX, trackY, filledW, 3, color.Active.LinkAccent)
+
+	// Handle
+	handleX := trackX + filledW
+	rl.DrawCircle(handleX, trackY+1, 6, color.Active.LinkAccent)
+
+	// Mouse: press anywhere on the track area to grab, drag to scrub
+	old := state.Value
+	mousePos := rl.GetMousePosition()
+	trackRect := rl.NewRectangle(float32(trackX-6), float32(trackY-10), float32(trackW+12), 22)
+	if rl.IsMouseButtonPressed(rl.MouseButtonLeft) && rl.CheckCollisionPointRec(mousePos, trackRect) {
+		state.dragging = true
+	}
+	if !rl.IsMouseButtonDown(rl.MouseButtonLeft) {
+		state.dragging = false
+	}
+	if state.dragging {
+		state.Value = (mousePos.X - float32(trackX)) / float32(trackW)
+	}
+
+	// Keyboard: [ and ] step by 2%
+	if keys && rl.IsKeyPressed(rl.KeyLeftBracket) {
+		state.Value -= 0.02
+	}
+	if keys && rl.IsKeyPressed(rl.KeyRightBracket) {
+		state.Value += 0.02
+	}
+
+	if state.Value < 0 {
+		state.Value = 0
+	}
+	if state.Value > 1 {
+		state.Value = 1
+	}
+	return state.Value != old
+}
@@ -5,8 +5,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/Crank-Git/FSNRedux/internal/app"
+	"github.com/Crank-Git/FSNRedux/internal/color"
+	"github.com/Crank-Git/FSNRedux/internal/config"
+	"github.com/Crank-Git/FSNRedux/internal/input"
+	"github.com/Crank-Git/FSNRedux/internal/layout"
 )
 
 var version = "dev"
@@ -15,10 +20,20 @@ func main() {
 	rootPath := flag.String("path", "/", "Root directory to visualize")
 	width := flag.Int("width", 1280, "Window width")
 	height := flag.Int("height", 800, "Window height")
-	depth := flag.Int("depth", 5, "Maximum scan depth (0 = unlimited)")
+	depth := flag.Int("depth", 5, "Levels pre-scanned in the background, expanded on open and shown (0 = unlimited)")
 	theme := flag.String("theme", "", "Color theme: dark, light, or auto (default: auto-detect)")
 	showHidden := flag.Bool("hidden", false, "Show hidden files and directories (dotfiles)")
+	colorMode := flag.String("color", "size", "File color mode: age, size, type, or git")
 	showVersion := flag.Bool("version", false, "Print version and exit")
+	benchScene := flag.Int("bench-scene", 0, "Render a synthetic scene with this many files, print frame times and exit")
+	benchFrames := flag.Int("bench-frames", 300, "Frames timed per pass with -bench-scene")
+	renderPNG := flag.String("render-png", "", "Render the scene to this PNG file without a window and exit")
+	camera := flag.String("camera", "overview", "Camera preset for -render-png: "+strings.Join(input.CameraPresets, ", "))
+	layoutName := flag.String("layout", "treev", "Layout: treev, mapv, radial, or sunburst")
+	sectorHeight := flag.String("sunburst-height", "depth", "Segment height in the sunburst layout: depth or age")
+	expand := flag.Int("expand", 1, "Directory levels expanded below the root for -render-png and -export")
+	exportPath := flag.String("export", "", "Export the scene to this .gltf, .obj or .html file and exit (uses -layout and -expand)")
+	exportFull := flag.Bool("export-full", false, "With -export, include the whole tree down to -depth instead of the -expand levels")
 	flag.Parse()
 
 	if *showVersion {
@@ -26,6 +41,12 @@ func main() {
 		return
 	}
 
+	if *benchScene > 0 {
+		app.New(app.Config{Width: *width, Height: *height, Theme: *theme, ColorMode: color.ModeSize, Layout: layout.ModeTreeV}).
+			RunBenchmark(*benchScene, *benchFrames)
+		return
+	}
+
 	// Resolve path
 	absPath, err := filepath.Abs(*rootPath)
 	if err != nil {
@@ -33,19 +54,100 @@ func main() {
 		os.Exit(1)
 	}
 
+	mode, ok := color.ParseMode(*colorMode)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Invalid color mode: %s\n", *colorMode)
+		os.Exit(1)
+	}
+
+	layoutMode, ok := layout.ParseMode(*layoutName)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Invalid layout: %s\n", *layoutName)
+		os.Exit(1)
+	}
+	heightMode, ok := layout.ParseSectorHeight(*sectorHeight)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Invalid sunburst height: %s\n", *sectorHeight)
+		os.Exit(1)
+	}
+
+	// Optional user preferences (~/.config/fsnredux/config.json)
+	prefs, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring config: %v\n", err)
+	}
+	ageBuckets, _ := prefs.Buckets()
+	reference, _ := prefs.Reference()
+	geometry, _ := prefs.Geometry()
+
+	// Placement remembered by stable layouts (~/.cache/fsnredux/order.json)
+	orderPath, err := config.OrderPath()
+	order := layout.Order{}
+	if err == nil {
+		if order, err = config.LoadOrder(orderPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring saved layout order: %v\n", err)
+		}
+	}
+
 	info, err := os.Stat(absPath)
 	if err != nil || !info.IsDir() {
 		fmt.Fprintf(os.Stderr, "Invalid directory: %s\n", absPath)
 		os.Exit(1)
 	}
 
-	application := app.New(app.Config{
-		RootPath:   absPath,
-		Width:      *width,
-		Height:     *height,
-		MaxDepth:   *depth,
-		Theme:      *theme,
-		ShowHidden: *showHidden,
-	})
-	application.Run()
+	cfg := app.Config{
+		RootPath:      absPath,
+		Width:         *width,
+		Height:        *height,
+		MaxDepth:      *depth,
+		Theme:         *theme,
+		ShowHidden:    *showHidden,
+		ColorMode:     mode,
+		AgeBuckets:    ageBuckets,
+		Reference:     reference,
+		TimeField:     prefs.TimeField(),
+		Shading:       prefs.Shading(),
+		FileHeights:   prefs.FileHeights,
+		FileFootprint: prefs.FileFootprint,
+		Sort:          prefs.Sort(),
+		Geometry:      geometry,
+		Stable:        prefs.StableLayout,
+		Order:         order,
+		Fisheye:       prefs.Fisheye,
+		Layout:        layoutMode,
+		SectorHeight:  heightMode,
+	}
+
+	if *exportPath != "" {
+		err := app.New(cfg).ExportScene(app.ExportOptions{
+			Output: *exportPath,
+			Expand: *expand,
+			Full:   *exportFull,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error exporting %s: %v\n", *exportPath, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *renderPNG != "" {
+		err := app.New(cfg).RenderPNG(app.RenderOptions{
+			Output: *renderPNG,
+			Camera: *camera,
+			Expand: *expand,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error rendering %s: %v\n", *renderPNG, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	app.New(cfg).Run()
+	if orderPath != "" && len(order) > 0 {
+		if err := config.SaveOrder(orderPath, order); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not save layout order: %v\n", err)
+		}
+	}
 }